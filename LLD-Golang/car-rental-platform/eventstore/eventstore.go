@@ -0,0 +1,165 @@
+// Package eventstore gives the rental system's ReservationManager a
+// durable, replayable system of record. Instead of mutating
+// Reservations []Reservation directly under a mutex, callers append
+// typed events per vehicle and rebuild that vehicle's reservations by
+// folding its log.
+//
+// WHY: the old mutex-guarded slice hid a race between the overlap
+// check and the append - two concurrent CreateReservation calls for
+// the same vehicle could both pass the check before either appended.
+// Keying the log by VehicleID and requiring SaveEvents to see the
+// exact expected seq turns that race into a hard concurrency error
+// instead of a double-booked car. Same tradeoff as
+// meeting-schedular/storage and splitwise/eventstore.
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Known event types for the car-rental-platform domain.
+// WHY: versioned type strings let the schema evolve without breaking
+// events already on disk (e.g. a future ReservationCreatedV2).
+const (
+	ReservationCreatedV1     = "ReservationCreatedV1"
+	ReservationScheduledV1   = "ReservationScheduledV1"
+	ReservationStartedV1     = "ReservationStartedV1"
+	ReservationCompletedV1   = "ReservationCompletedV1"
+	ReservationCancelledV1   = "ReservationCancelledV1"
+	ReservationSoftDeletedV1 = "ReservationSoftDeletedV1"
+	BillPaidV1               = "BillPaidV1"
+)
+
+// Event is one fact appended to an aggregate's log. Each vehicle is
+// its own aggregate, identified by AggregateID = VehicleID (as a
+// string): every reservation ever made against that vehicle lives in
+// one ordered log, which is exactly the invariant boundary the
+// overlap check needs.
+type Event struct {
+	AggregateID string          `json:"aggregate_id"`
+	Seq         int64           `json:"seq"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+	Ts          time.Time       `json:"ts"`
+}
+
+// Aggregate is anything that can be rebuilt by folding events in seq
+// order. WHY: lets Rehydrate/ReplayUpTo be generic across whatever
+// projection a caller folds into (e.g. a per-vehicle reservation map).
+type Aggregate interface {
+	// Apply folds one event into the aggregate's state. Must be pure:
+	// no I/O, no side effects.
+	Apply(event Event) error
+}
+
+// EventStore is the contract ReservationManager depends on. WHY:
+// keeps it ignorant of whether events live in memory or Postgres.
+type EventStore interface {
+	// SaveEvents appends events for aggregateID starting at
+	// expectedSeq+1. It must fail with ErrConcurrencyConflict if
+	// another writer already advanced the aggregate past expectedSeq -
+	// this is the (VehicleID, version) check CreateReservation relies
+	// on to prevent double-booking a vehicle.
+	SaveEvents(ctx context.Context, aggregateID string, expectedSeq int64, events []Event) error
+	GetEvents(ctx context.Context, aggregateID string) ([]Event, error)
+}
+
+// ErrConcurrencyConflict is returned when expectedSeq no longer
+// matches the aggregate's latest seq in the store.
+type ErrConcurrencyConflict struct {
+	AggregateID string
+	Expected    int64
+	Actual      int64
+}
+
+func (e *ErrConcurrencyConflict) Error() string {
+	return fmt.Sprintf("eventstore: concurrency conflict on %s: expected seq %d, store is at %d", e.AggregateID, e.Expected, e.Actual)
+}
+
+// InMemoryEventStore is the default store used by the demo in
+// main.go. WHY: keeps `go run .` working with zero external
+// dependencies while still exercising the exact interface
+// PostgresEventStore serves in production.
+type InMemoryEventStore struct {
+	mu   sync.Mutex
+	logs map[string][]Event
+}
+
+func NewInMemoryEventStore() *InMemoryEventStore {
+	return &InMemoryEventStore{logs: make(map[string][]Event)}
+}
+
+func (s *InMemoryEventStore) SaveEvents(ctx context.Context, aggregateID string, expectedSeq int64, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log := s.logs[aggregateID]
+	actual := int64(len(log))
+	if actual != expectedSeq {
+		return &ErrConcurrencyConflict{AggregateID: aggregateID, Expected: expectedSeq, Actual: actual}
+	}
+	now := time.Now()
+	for i, e := range events {
+		e.AggregateID = aggregateID
+		e.Seq = expectedSeq + int64(i) + 1
+		if e.Ts.IsZero() {
+			e.Ts = now
+		}
+		log = append(log, e)
+	}
+	s.logs[aggregateID] = log
+	return nil
+}
+
+func (s *InMemoryEventStore) GetEvents(ctx context.Context, aggregateID string) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Event, len(s.logs[aggregateID]))
+	copy(out, s.logs[aggregateID])
+	sort.Slice(out, func(i, j int) bool { return out[i].Seq < out[j].Seq })
+	return out, nil
+}
+
+// Rehydrate folds every event for aggregateID, in seq order, into agg.
+// WHY: single choke point so every code path (startup, a manual
+// replay, a read-your-writes lookup) rebuilds a vehicle's reservations
+// the same way.
+func Rehydrate(ctx context.Context, store EventStore, aggregateID string, agg Aggregate) error {
+	events, err := store.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return fmt.Errorf("eventstore: rehydrate %s: %w", aggregateID, err)
+	}
+	for _, e := range events {
+		if err := agg.Apply(e); err != nil {
+			return fmt.Errorf("eventstore: rehydrate %s at seq %d: %w", aggregateID, e.Seq, err)
+		}
+	}
+	return nil
+}
+
+// ReplayUpTo folds events for aggregateID in seq order into agg,
+// stopping before the first event with Ts after cutoff. WHY: lets an
+// auditor ask "what did this vehicle's reservations look like at
+// 9am last Tuesday" without needing a snapshot taken at that instant -
+// soft-deleted and cancelled rows that hadn't happened yet simply
+// never get folded in.
+func ReplayUpTo(ctx context.Context, store EventStore, aggregateID string, cutoff time.Time, agg Aggregate) error {
+	events, err := store.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return fmt.Errorf("eventstore: replay %s up to %s: %w", aggregateID, cutoff, err)
+	}
+	for _, e := range events {
+		if e.Ts.After(cutoff) {
+			break
+		}
+		if err := agg.Apply(e); err != nil {
+			return fmt.Errorf("eventstore: replay %s at seq %d: %w", aggregateID, e.Seq, err)
+		}
+	}
+	return nil
+}
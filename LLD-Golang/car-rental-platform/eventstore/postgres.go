@@ -0,0 +1,91 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PostgresEventStore persists events to a single append-only table:
+//
+//	CREATE TABLE events (
+//	    aggregate_id TEXT NOT NULL,
+//	    seq          BIGINT NOT NULL,
+//	    type         TEXT NOT NULL,
+//	    data         JSONB NOT NULL,
+//	    ts           TIMESTAMPTZ NOT NULL DEFAULT now(),
+//	    PRIMARY KEY (aggregate_id, seq)
+//	);
+//
+// WHY: the (aggregate_id, seq) primary key is what gives CreateReservation
+// its optimistic concurrency for free - a conflicting writer's INSERT
+// for the same vehicle simply fails.
+type PostgresEventStore struct {
+	db *sql.DB
+}
+
+func NewPostgresEventStore(db *sql.DB) *PostgresEventStore {
+	return &PostgresEventStore{db: db}
+}
+
+// SaveEvents inserts events in a single transaction starting at
+// expectedSeq+1. WHY: wrapping the insert in a transaction means a
+// mid-batch failure never leaves a gap in the sequence that a later
+// replay would misread.
+func (s *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID string, expectedSeq int64, events []Event) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("eventstore: begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var actual int64
+	row := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(seq), 0) FROM events WHERE aggregate_id = $1`, aggregateID)
+	if err := row.Scan(&actual); err != nil {
+		return fmt.Errorf("eventstore: read current seq: %w", err)
+	}
+	if actual != expectedSeq {
+		return &ErrConcurrencyConflict{AggregateID: aggregateID, Expected: expectedSeq, Actual: actual}
+	}
+
+	for i, e := range events {
+		seq := expectedSeq + int64(i) + 1
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO events (aggregate_id, seq, type, data) VALUES ($1, $2, $3, $4)`,
+			aggregateID, seq, e.Type, []byte(e.Data),
+		); err != nil {
+			// WHY: a unique-violation here means a concurrent writer won
+			// the race between our SELECT and this INSERT - surface it
+			// the same way as the pre-check so CreateReservation only
+			// ever has one error to handle.
+			return &ErrConcurrencyConflict{AggregateID: aggregateID, Expected: expectedSeq, Actual: actual}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("eventstore: commit: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresEventStore) GetEvents(ctx context.Context, aggregateID string) ([]Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT aggregate_id, seq, type, data, ts FROM events WHERE aggregate_id = $1 ORDER BY seq ASC`,
+		aggregateID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("eventstore: query events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Event
+	for rows.Next() {
+		var e Event
+		var data []byte
+		if err := rows.Scan(&e.AggregateID, &e.Seq, &e.Type, &data, &e.Ts); err != nil {
+			return nil, fmt.Errorf("eventstore: scan event: %w", err)
+		}
+		e.Data = data
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unicode"
+)
+
+// Flatten recursively walks in (built from a struct/map via
+// flattenValue) and writes dotted-path leaf values into out, e.g.
+// user.user_name, vehicle.type, bill.payment_details.amount_paid. Nested
+// structs/maps recurse; a nil pointer/map/slice/interface is skipped
+// entirely rather than emitting an empty key, so ExportReservations
+// rows that haven't paid yet simply have no "bill.*" keys at all -
+// CSVExporter's header union is what turns that into blank cells
+// instead of ragged rows.
+func Flatten(prefix string, in map[string]any, out map[string]any) {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if isNil(v) {
+			continue
+		}
+		if nested, ok := v.(map[string]any); ok {
+			Flatten(key, nested, out)
+			continue
+		}
+		if nested, ok := structToMap(v); ok {
+			Flatten(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// structToMap turns a struct (or pointer to one) into a map of its
+// exported fields keyed by snake_case name, for Flatten to recurse
+// into. time.Time is treated as a leaf instead, since its only
+// "fields" are unexported.
+func structToMap(v any) (map[string]any, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	if _, isTime := rv.Interface().(time.Time); isTime {
+		return nil, false
+	}
+
+	rt := rv.Type()
+	out := make(map[string]any, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		out[snakeCase(field.Name)] = rv.Field(i).Interface()
+	}
+	return out, true
+}
+
+// snakeCase converts a Go exported field name (e.g. "PricePerDay") to
+// its export column form ("price_per_day").
+func snakeCase(name string) string {
+	var b []rune
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b = append(b, '_')
+		}
+		b = append(b, unicode.ToLower(r))
+	}
+	return string(b)
+}
+
+// flattenValue is the entry point into Flatten for a single row
+// value: it turns v's own exported fields into dotted keys via
+// structToMap, then recurses.
+func flattenValue(v any) map[string]any {
+	out := make(map[string]any)
+	if top, ok := structToMap(v); ok {
+		Flatten("", top, out)
+	}
+	return out
+}
+
+// CSVExporter unions every key observed across a set of flattened
+// rows into one stable, alphabetically sorted header, then streams
+// each row against that header via encoding/csv - a row missing a key
+// another row has just gets a blank cell in that column rather than
+// shifting every column after it.
+type CSVExporter struct{}
+
+func (e *CSVExporter) Export(w io.Writer, rows []map[string]any) error {
+	header := unionKeys(rows)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("export: write header: %w", err)
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, key := range header {
+			if v, ok := row[key]; ok {
+				record[i] = formatCell(v)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("export: write row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func unionKeys(rows []map[string]any) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, row := range rows {
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatCell(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// ExportFilter narrows an export to rows matching every non-zero-value
+// field, the same convention BookingFilter already uses in
+// ride-booking-system.
+type ExportFilter struct {
+	From        time.Time
+	To          time.Time
+	StoreID     int
+	VehicleType CarType
+	Status      ReservationStatus
+}
+
+func (f ExportFilter) matches(r Reservation) bool {
+	if f.Status != "" && r.Status != f.Status {
+		return false
+	}
+	if !f.From.IsZero() && r.StartTime.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !r.StartTime.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// ExportReservations writes every reservation across every store in
+// vrs matching filter as a CSV of Flatten'd rows to w.
+//
+// Placed on VehicleRentalSystem rather than Store, since StoreID is
+// one of filter's own fields and an export spanning every store is
+// the more useful default for the HTTP handler below; Store itself
+// has no independent use for it.
+func (vrs *VehicleRentalSystem) ExportReservations(w io.Writer, filter ExportFilter) error {
+	var rows []map[string]any
+	for i := range vrs.StoreList {
+		store := &vrs.StoreList[i]
+		if filter.StoreID != 0 && filter.StoreID != store.StoreID {
+			continue
+		}
+		for _, r := range store.ReservationManager.ActiveReservations() {
+			if filter.VehicleType != "" && r.Vehicle != nil && r.Vehicle.Type != filter.VehicleType {
+				continue
+			}
+			if !filter.matches(r) {
+				continue
+			}
+			rows = append(rows, flattenValue(r))
+		}
+	}
+	return new(CSVExporter).Export(w, rows)
+}
+
+// ExportFleet writes every vehicle across every store in vrs matching
+// filter's StoreID/VehicleType as a CSV of Flatten'd rows to w.
+func (vrs *VehicleRentalSystem) ExportFleet(w io.Writer, filter ExportFilter) error {
+	var rows []map[string]any
+	for i := range vrs.StoreList {
+		store := &vrs.StoreList[i]
+		if filter.StoreID != 0 && filter.StoreID != store.StoreID {
+			continue
+		}
+		for _, v := range store.InventoryManager.Vehicles {
+			if filter.VehicleType != "" && v.Type != filter.VehicleType {
+				continue
+			}
+			rows = append(rows, flattenValue(v))
+		}
+	}
+	return new(CSVExporter).Export(w, rows)
+}
+
+// ExportServer exposes GET /export/reservations.csv and
+// GET /export/fleet.csv over vrs, each accepting from/to (RFC3339),
+// store_id, vehicle_type and status query params.
+type ExportServer struct {
+	vrs *VehicleRentalSystem
+}
+
+func NewExportServer(vrs *VehicleRentalSystem) *ExportServer {
+	return &ExportServer{vrs: vrs}
+}
+
+// Handler returns the mux routing /export/reservations.csv and
+// /export/fleet.csv to their respective handlers.
+func (es *ExportServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/export/reservations.csv", es.handleReservations)
+	mux.HandleFunc("/export/fleet.csv", es.handleFleet)
+	return mux
+}
+
+func (es *ExportServer) handleReservations(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseExportFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="reservations.csv"`)
+	if err := es.vrs.ExportReservations(w, filter); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (es *ExportServer) handleFleet(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseExportFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="fleet.csv"`)
+	if err := es.vrs.ExportFleet(w, filter); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseExportFilter(q url.Values) (ExportFilter, error) {
+	var f ExportFilter
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, fmt.Errorf("export: invalid from: %w", err)
+		}
+		f.From = t
+	}
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, fmt.Errorf("export: invalid to: %w", err)
+		}
+		f.To = t
+	}
+	if v := q.Get("store_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return f, fmt.Errorf("export: invalid store_id: %w", err)
+		}
+		f.StoreID = id
+	}
+	if v := q.Get("vehicle_type"); v != "" {
+		f.VehicleType = CarType(v)
+	}
+	if v := q.Get("status"); v != "" {
+		f.Status = ReservationStatus(v)
+	}
+	return f, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"car-rental-platform/eventstore"
 )
 
 // Customers can do:
@@ -26,7 +28,7 @@ import (
 // 2. Car/Vehicle → Abstract class for different vehicle types (SUV, Sedan etc)
 // 3. VehicleInventoryManagement → Manages availability of cars in a store
 // 4. Store → Represents a physical location with cars & reservations
-// 5. ReservationManager → Handles lifecycle of reservations (create, update, cancel)
+// 5. ReservationManager → Handles lifecycle of reservations (create, update, cancel); persists transitions as an event log (see reservation.go)
 // 6. Bill → Calculates rental charges
 // 7. Payment/PaymentService → Handles different payment modes
 // 8. NotificationService → Sends booking confirmation/cancellation alerts.
@@ -101,16 +103,18 @@ type Vehicle struct {
 
 // Reservation
 type Reservation struct {
-	ReservationID     int
-	User              *User
-	Vehicle           *Vehicle
-	StartTime         time.Time
-	EndTime           time.Time
-	Status            ReservationStatus
-	Location          *Location
-	ReservationType   ReservationType // Added for billing
-	Bill              *Bill           // Link to Bill
-	reservationsMutex sync.Mutex      // For internal overlap check
+	ReservationID   int
+	UserID          int // canonical identity carried in the event log; User below is a live convenience pointer
+	User            *User
+	VehicleID       int // canonical identity carried in the event log; Vehicle below is a live convenience pointer
+	Vehicle         *Vehicle
+	StartTime       time.Time
+	EndTime         time.Time
+	Status          ReservationStatus
+	Location        *Location
+	ReservationType ReservationType // Added for billing
+	Bill            *Bill           // Link to Bill
+	Deleted         bool            // soft-deleted: hidden from admin overviews, still present in the event log for auditors
 }
 
 // Check if this reservation overlaps with any existing (dummy; in real, check against all for vehicle)
@@ -185,65 +189,12 @@ func (vim *VehicleInventoryManagement) AddVehicles(vehicles ...Vehicle) {
 	vim.Vehicles = append(vim.Vehicles, vehicles...)
 }
 
-// ReservationManager
-type ReservationManager struct {
-	Reservations []Reservation
-	mutex        sync.Mutex
-	counter      int
-}
-
-func (rm *ReservationManager) CreateReservation(res *Reservation) (*Reservation, error) {
-	rm.mutex.Lock()
-	defer rm.mutex.Unlock()
-
-	// Check availability (simple overlap check on all reservations for this vehicle)
-	for _, existing := range rm.Reservations {
-		if existing.Vehicle.ID == res.Vehicle.ID && existing.Status != Cancelled && existing.Overlaps(res.StartTime, res.EndTime) {
-			return nil, errors.New("vehicle not available")
-		}
-	}
-
-	rm.counter++
-	res.ReservationID = rm.counter
-	res.Status = Initiated
-	rm.Reservations = append(rm.Reservations, *res)
-	return res, nil
-}
-
-func (rm *ReservationManager) ChangeStatusToScheduled(id int) error {
-	return rm.updateStatus(id, Scheduled)
-}
-
-func (rm *ReservationManager) ChangeStatusToInProgress(id int) error {
-	return rm.updateStatus(id, InProgress)
-}
-
-func (rm *ReservationManager) CompleteReservation(id int) error {
-	return rm.updateStatus(id, Completed)
-}
-
-func (rm *ReservationManager) CancelReservation(id int) error {
-	return rm.updateStatus(id, Cancelled)
-}
-
-func (rm *ReservationManager) updateStatus(id int, status ReservationStatus) error {
-	rm.mutex.Lock()
-	defer rm.mutex.Unlock()
-	for i := range rm.Reservations {
-		if rm.Reservations[i].ReservationID == id {
-			rm.Reservations[i].Status = status
-			return nil
-		}
-	}
-	return errors.New("reservation not found")
-}
-
 // Store
 type Store struct {
 	StoreID            int
 	Location           Location
 	InventoryManager   VehicleInventoryManagement
-	ReservationManager ReservationManager
+	ReservationManager *ReservationManager // see reservation.go; constructed via NewReservationManager
 }
 
 func (s *Store) GetVehiclesByType(t CarType) []Vehicle {
@@ -261,7 +212,7 @@ func (s *Store) SearchAvailableVehicles(t CarType, start, end time.Time) []Vehic
 	for _, v := range s.GetVehiclesByType(t) {
 		avl := true
 		for _, res := range s.ReservationManager.Reservations {
-			if res.Vehicle.ID == v.ID && res.Status != Cancelled && res.Overlaps(start, end) {
+			if res.VehicleID == v.ID && res.Status != Cancelled && !res.Deleted && res.Overlaps(start, end) {
 				avl = false
 				break
 			}
@@ -276,7 +227,7 @@ func (s *Store) SearchAvailableVehicles(t CarType, start, end time.Time) []Vehic
 func (s *Store) UpdateOrCreateReservation(res *Reservation) error {
 	// If ID exists, update; else create
 	if res.ReservationID > 0 {
-		return s.ReservationManager.updateStatus(res.ReservationID, res.Status) // Example update
+		return s.ReservationManager.SetStatus(res.ReservationID, res.Status) // Example update
 	}
 	_, err := s.ReservationManager.CreateReservation(res)
 	return err
@@ -320,7 +271,7 @@ func main() {
 		StoreID:            1,
 		Location:           loc,
 		InventoryManager:   VehicleInventoryManagement{},
-		ReservationManager: ReservationManager{},
+		ReservationManager: NewReservationManager(eventstore.NewInMemoryEventStore()),
 	}
 	store.InventoryManager.AddVehicles(
 		Vehicle{ID: 1, Make: "Toyota", Model: "Camry", Year: 2020, PricePerDay: 50, NumberPlate: "ABC123", Type: Sedan},
@@ -364,6 +315,8 @@ func main() {
 	err = payment.PayBill(bill, Online)
 	if err != nil {
 		fmt.Println(err)
+	} else if err := store.ReservationManager.RecordBillPayment(res, bill.TotalAmount, Online); err != nil {
+		fmt.Println(err)
 	}
 
 	// 4. Cancel reservation
@@ -372,4 +325,17 @@ func main() {
 		fmt.Println(err)
 	}
 	fmt.Println("Reservation status:", res.Status)
+
+	// 5. Soft-delete it: admin overviews hide it from here on, while an
+	// auditor can still replay its full history from the event log.
+	if err := store.ReservationManager.SoftDeleteReservation(res.ReservationID); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("Active reservations visible to admins: %d\n", len(store.ReservationManager.ActiveReservations()))
+
+	if history, err := store.ReservationManager.HistoryAt(res.Vehicle.ID, time.Now()); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Printf("Full replayed history for vehicle %d: %d reservation(s)\n", res.Vehicle.ID, len(history))
+	}
 }
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"car-rental-platform/eventstore"
+)
+
+// statusEventTypes maps a target ReservationStatus to the event type
+// that records reaching it, so SetStatus/transition only need to look
+// the pair up once instead of a long switch.
+var statusEventTypes = map[ReservationStatus]string{
+	Scheduled:  eventstore.ReservationScheduledV1,
+	InProgress: eventstore.ReservationStartedV1,
+	Completed:  eventstore.ReservationCompletedV1,
+	Cancelled:  eventstore.ReservationCancelledV1,
+}
+
+// ReservationManager persists every reservation lifecycle transition
+// as an append-only event, one stream per vehicle (VehicleID is the
+// eventstore aggregate id): a vehicle's reservations, cancellations
+// and soft-deletes all live in its single ordered log, which is
+// exactly the invariant boundary CreateReservation's overlap check
+// needs. Reservations is a read-model projection folded from that
+// log and kept in sync on every append.
+//
+// WHY: the old design held one mutex around a shared slice, so the
+// overlap check and the append only ever raced within a single
+// process. SaveEvents' optimistic concurrency check on
+// (VehicleID, version) is what actually closes that race once this
+// runs as more than one instance - the mutex below still exists, but
+// only to serialize the in-process projection cache.
+type ReservationManager struct {
+	store eventstore.EventStore
+
+	mu           sync.Mutex
+	Reservations []Reservation
+	versions     map[int]int64 // VehicleID -> current seq/version
+	counter      int
+}
+
+func NewReservationManager(store eventstore.EventStore) *ReservationManager {
+	return &ReservationManager{store: store, versions: make(map[int]int64)}
+}
+
+// appendEvent saves payload as evtType on vehicleID's stream at its
+// expected version and advances that version on success.
+func (rm *ReservationManager) appendEvent(vehicleID int, evtType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("reservation: marshal %s: %w", evtType, err)
+	}
+	aggregateID := strconv.Itoa(vehicleID)
+	expected := rm.versions[vehicleID]
+	evt := eventstore.Event{Type: evtType, Data: data}
+	if err := rm.store.SaveEvents(context.Background(), aggregateID, expected, []eventstore.Event{evt}); err != nil {
+		return err
+	}
+	rm.versions[vehicleID] = expected + 1
+	return nil
+}
+
+// CreateReservation appends an eventstore.ReservationCreatedV1 event for
+// res.Vehicle.ID after checking it doesn't overlap an existing,
+// non-cancelled, non-deleted reservation for the same vehicle.
+func (rm *ReservationManager) CreateReservation(res *Reservation) (*Reservation, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	for _, existing := range rm.Reservations {
+		if existing.VehicleID == res.Vehicle.ID && existing.Status != Cancelled && !existing.Deleted && existing.Overlaps(res.StartTime, res.EndTime) {
+			return nil, errors.New("vehicle not available")
+		}
+	}
+
+	payload := struct {
+		ReservationID   int
+		UserID          int
+		VehicleID       int
+		StartTime       time.Time
+		EndTime         time.Time
+		ReservationType ReservationType
+	}{
+		ReservationID:   rm.counter + 1,
+		UserID:          res.User.UserID,
+		VehicleID:       res.Vehicle.ID,
+		StartTime:       res.StartTime,
+		EndTime:         res.EndTime,
+		ReservationType: res.ReservationType,
+	}
+	if err := rm.appendEvent(res.Vehicle.ID, eventstore.ReservationCreatedV1, payload); err != nil {
+		return nil, err
+	}
+
+	rm.counter++
+	res.ReservationID = payload.ReservationID
+	res.UserID = payload.UserID
+	res.VehicleID = payload.VehicleID
+	res.Status = Initiated
+	rm.Reservations = append(rm.Reservations, *res)
+	return res, nil
+}
+
+func (rm *ReservationManager) ChangeStatusToScheduled(id int) error {
+	return rm.SetStatus(id, Scheduled)
+}
+
+func (rm *ReservationManager) ChangeStatusToInProgress(id int) error {
+	return rm.SetStatus(id, InProgress)
+}
+
+func (rm *ReservationManager) CompleteReservation(id int) error {
+	return rm.SetStatus(id, Completed)
+}
+
+func (rm *ReservationManager) CancelReservation(id int) error {
+	return rm.SetStatus(id, Cancelled)
+}
+
+// SetStatus transitions id to status, recording the matching event on
+// its vehicle's stream. Covers the typed wrappers above plus
+// Store.UpdateOrCreateReservation's generic update path.
+func (rm *ReservationManager) SetStatus(id int, status ReservationStatus) error {
+	evtType, ok := statusEventTypes[status]
+	if !ok {
+		return fmt.Errorf("reservation: no event type for status %s", status)
+	}
+	return rm.transition(id, status, evtType)
+}
+
+func (rm *ReservationManager) transition(id int, status ReservationStatus, evtType string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	idx := rm.indexOfLocked(id)
+	if idx < 0 {
+		return errors.New("reservation not found")
+	}
+	payload := struct{ ReservationID int }{ReservationID: id}
+	if err := rm.appendEvent(rm.Reservations[idx].VehicleID, evtType, payload); err != nil {
+		return err
+	}
+	rm.Reservations[idx].Status = status
+	return nil
+}
+
+// SoftDeleteReservation marks id Deleted so ActiveReservations hides
+// it from admin overviews, matching the distinction the request draws
+// between cancellation (a booking outcome) and soft-delete (an admin
+// visibility decision) - the event log still keeps both, so a replay
+// via HistoryAt shows the reservation exactly as it happened.
+func (rm *ReservationManager) SoftDeleteReservation(id int) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	idx := rm.indexOfLocked(id)
+	if idx < 0 {
+		return errors.New("reservation not found")
+	}
+	payload := struct{ ReservationID int }{ReservationID: id}
+	if err := rm.appendEvent(rm.Reservations[idx].VehicleID, eventstore.ReservationSoftDeletedV1, payload); err != nil {
+		return err
+	}
+	rm.Reservations[idx].Deleted = true
+	return nil
+}
+
+// RecordBillPayment appends an eventstore.BillPaidV1 event once Payment.PayBill
+// has settled res's bill, so the payment fact lives in the same
+// per-vehicle audit log as the reservation's other transitions.
+func (rm *ReservationManager) RecordBillPayment(res *Reservation, amount float64, mode PaymentMode) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	payload := struct {
+		ReservationID int
+		Amount        float64
+		PaymentMode   PaymentMode
+	}{res.ReservationID, amount, mode}
+	return rm.appendEvent(res.Vehicle.ID, eventstore.BillPaidV1, payload)
+}
+
+// ActiveReservations returns every non-soft-deleted reservation - the
+// view an admin overview should show. Cancelled-but-not-deleted
+// reservations still appear here; Deleted and Cancelled are
+// orthogonal, exactly as the request asks.
+func (rm *ReservationManager) ActiveReservations() []Reservation {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	out := make([]Reservation, 0, len(rm.Reservations))
+	for _, r := range rm.Reservations {
+		if !r.Deleted {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (rm *ReservationManager) indexOfLocked(id int) int {
+	for i := range rm.Reservations {
+		if rm.Reservations[i].ReservationID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// HistoryAt rebuilds vehicleID's reservation set as of cutoff by
+// folding only the prefix of its event log up to that time - e.g.
+// "what did this vehicle's bookings look like last Tuesday at 9am",
+// including rows since cancelled or soft-deleted. Unlike the live
+// Reservations projection, the entries it returns carry no resolved
+// User/Vehicle pointers, since a cold replay only has the IDs the
+// events recorded.
+func (rm *ReservationManager) HistoryAt(vehicleID int, cutoff time.Time) ([]Reservation, error) {
+	projection := &vehicleProjection{}
+	if err := eventstore.ReplayUpTo(context.Background(), rm.store, strconv.Itoa(vehicleID), cutoff, projection); err != nil {
+		return nil, err
+	}
+	return projection.reservations(), nil
+}
+
+// vehicleProjection folds one vehicle's event log into its
+// reservation set from scratch, independent of whatever live
+// *Reservation objects a ReservationManager happens to be holding -
+// this is what makes HistoryAt usable by a fresh process or an
+// auditor who only has the event store.
+type vehicleProjection struct {
+	byID map[int]*Reservation
+	ids  []int
+}
+
+func (p *vehicleProjection) Apply(event eventstore.Event) error {
+	if p.byID == nil {
+		p.byID = make(map[int]*Reservation)
+	}
+	switch event.Type {
+	case eventstore.ReservationCreatedV1:
+		var payload struct {
+			ReservationID   int
+			UserID          int
+			VehicleID       int
+			StartTime       time.Time
+			EndTime         time.Time
+			ReservationType ReservationType
+		}
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return err
+		}
+		p.byID[payload.ReservationID] = &Reservation{
+			ReservationID:   payload.ReservationID,
+			UserID:          payload.UserID,
+			VehicleID:       payload.VehicleID,
+			StartTime:       payload.StartTime,
+			EndTime:         payload.EndTime,
+			ReservationType: payload.ReservationType,
+			Status:          Initiated,
+		}
+		p.ids = append(p.ids, payload.ReservationID)
+	case eventstore.ReservationScheduledV1:
+		return p.setStatus(event.Data, Scheduled)
+	case eventstore.ReservationStartedV1:
+		return p.setStatus(event.Data, InProgress)
+	case eventstore.ReservationCompletedV1:
+		return p.setStatus(event.Data, Completed)
+	case eventstore.ReservationCancelledV1:
+		return p.setStatus(event.Data, Cancelled)
+	case eventstore.ReservationSoftDeletedV1:
+		var payload struct{ ReservationID int }
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return err
+		}
+		if r, ok := p.byID[payload.ReservationID]; ok {
+			r.Deleted = true
+		}
+	case eventstore.BillPaidV1:
+		var payload struct {
+			ReservationID int
+			Amount        float64
+		}
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return err
+		}
+		if r, ok := p.byID[payload.ReservationID]; ok {
+			r.Bill = &Bill{TotalAmount: payload.Amount, IsPaid: true}
+		}
+	}
+	return nil
+}
+
+func (p *vehicleProjection) setStatus(data json.RawMessage, status ReservationStatus) error {
+	var payload struct{ ReservationID int }
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	if r, ok := p.byID[payload.ReservationID]; ok {
+		r.Status = status
+	}
+	return nil
+}
+
+func (p *vehicleProjection) reservations() []Reservation {
+	out := make([]Reservation, 0, len(p.ids))
+	for _, id := range p.ids {
+		out = append(out, *p.byID[id])
+	}
+	return out
+}
+
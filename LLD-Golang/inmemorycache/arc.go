@@ -0,0 +1,157 @@
+package main
+
+import "container/list"
+
+// arcEntry is the payload stored in every ARC list element.
+type arcEntry struct {
+	key string
+}
+
+// ARCPolicy implements Adaptive Replacement Cache. It tracks four lists -
+// T1 (seen once recently), T2 (seen at least twice), and the ghost lists
+// B1/B2 recording the keys most recently evicted from T1/T2 - and adapts
+// the target T1 size p based on which ghost list a subsequent miss lands
+// in. Unlike a fixed LRU or LFU policy, ARC self-tunes between recency
+// and frequency as the workload's access pattern shifts, without a human
+// having to pick one policy up front.
+type ARCPolicy struct {
+	capacity int
+	p        int // adaptive target size for T1
+
+	t1, t2, b1, b2 *list.List
+	elems          map[string]*list.Element
+	owner          map[string]*list.List // which of t1/t2/b1/b2 currently holds key
+}
+
+// NewARCPolicy creates a new ARC policy bounded to capacity resident
+// entries (T1+T2); the ghost lists B1+B2 are bounded to capacity as well,
+// per the standard ARC algorithm.
+func NewARCPolicy(capacity int) *ARCPolicy {
+	return &ARCPolicy{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		elems:    make(map[string]*list.Element),
+		owner:    make(map[string]*list.List),
+	}
+}
+
+func (p *ARCPolicy) pushFront(l *list.List, key string) {
+	elem := l.PushFront(&arcEntry{key: key})
+	p.elems[key] = elem
+	p.owner[key] = l
+}
+
+func (p *ARCPolicy) removeFrom(l *list.List, key string) {
+	if elem, ok := p.elems[key]; ok {
+		l.Remove(elem)
+		delete(p.elems, key)
+		delete(p.owner, key)
+	}
+}
+
+// Access handles a cache hit: T1 entries are promoted to T2 (they've now
+// been seen twice), T2 entries just move to the front.
+func (p *ARCPolicy) Access(key string) {
+	switch p.owner[key] {
+	case p.t1:
+		p.removeFrom(p.t1, key)
+		p.pushFront(p.t2, key)
+	case p.t2:
+		p.removeFrom(p.t2, key)
+		p.pushFront(p.t2, key)
+	}
+}
+
+// Add handles a cache miss for key once room has already been made by
+// Evict: any leftover ghost-list membership is cleared, and key enters at
+// the front of T1 as a freshly-seen-once entry.
+func (p *ARCPolicy) Add(key string) {
+	if l := p.owner[key]; l == p.b1 || l == p.b2 {
+		p.removeFrom(l, key)
+	}
+	p.pushFront(p.t1, key)
+}
+
+// Evict runs ARC's REPLACE procedure for an incoming miss on key: if key
+// is itself a ghost (in B1 or B2), p is adapted toward whichever real
+// list that ghost shadows, then the LRU entry of T1 or T2 - chosen by
+// comparing sizes against p - is moved to its matching ghost list and
+// its key returned so the caller can drop it from storage.
+func (p *ARCPolicy) Evict(key string) string {
+	switch p.owner[key] {
+	case p.b1:
+		delta := 1
+		if p.b1.Len() > 0 && p.b2.Len() > p.b1.Len() {
+			delta = p.b2.Len() / p.b1.Len()
+		}
+		p.p = minInt(p.capacity, p.p+delta)
+	case p.b2:
+		delta := 1
+		if p.b2.Len() > 0 && p.b1.Len() > p.b2.Len() {
+			delta = p.b1.Len() / p.b2.Len()
+		}
+		p.p = maxInt(0, p.p-delta)
+	}
+
+	preferT1 := p.t1.Len() > 0 &&
+		(p.t1.Len() > p.p || (p.owner[key] == p.b2 && p.t1.Len() == p.p))
+
+	var victimList, ghostList *list.List
+	switch {
+	case preferT1:
+		victimList, ghostList = p.t1, p.b1
+	case p.t2.Len() > 0:
+		victimList, ghostList = p.t2, p.b2
+	case p.t1.Len() > 0:
+		victimList, ghostList = p.t1, p.b1
+	default:
+		return ""
+	}
+
+	back := victimList.Back()
+	victimKey := back.Value.(*arcEntry).key
+	p.removeFrom(victimList, victimKey)
+	p.pushFront(ghostList, victimKey)
+	p.trimGhosts()
+	return victimKey
+}
+
+// trimGhosts keeps |B1|+|B2| within capacity, as ARC requires, by
+// dropping the oldest entry from whichever ghost list is currently
+// larger.
+func (p *ARCPolicy) trimGhosts() {
+	for p.b1.Len()+p.b2.Len() > p.capacity {
+		victim := p.b2
+		if p.b1.Len() > p.b2.Len() {
+			victim = p.b1
+		}
+		back := victim.Back()
+		if back == nil {
+			break
+		}
+		p.removeFrom(victim, back.Value.(*arcEntry).key)
+	}
+}
+
+func (p *ARCPolicy) Remove(key string) {
+	if l, ok := p.owner[key]; ok {
+		p.removeFrom(l, key)
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"os"
 	"sync"
 	"time"
 )
@@ -15,24 +17,63 @@ type Entry struct {
 type EvictionPolicy interface {
 	Access(key string)
 	Add(key string)
-	Evict() string
+	// Evict picks a victim to make room for key and reports its key, or
+	// "" if there's nothing to evict. Most policies ignore key entirely;
+	// it's threaded through for policies (like ARC) whose eviction choice
+	// depends on whether key itself was recently evicted.
+	Evict(key string) string
 	Remove(key string)
 }
 
 // cache storage with eviction policy and TTL
 type Cache struct {
-	capacity int
-	storage  map[string]*Entry
-	policy   EvictionPolicy
-	mu       sync.Mutex
+	capacity    int
+	storage     map[string]*Entry
+	policy      EvictionPolicy
+	persistence Persistence
+	mu          sync.Mutex
 }
 
-func NewCache(capacity int, policy EvictionPolicy) *Cache {
-	return &Cache{
+// CacheOption configures optional Cache behavior at construction time.
+type CacheOption func(*Cache)
+
+// WithPersistence wires p into the cache: NewCache replays p.Load() to
+// seed storage and snapshots the result, so the WAL starts empty, and
+// every Set/Delete afterwards is appended to the WAL via p.
+func WithPersistence(p Persistence) CacheOption {
+	return func(c *Cache) { c.persistence = p }
+}
+
+func NewCache(capacity int, policy EvictionPolicy, opts ...CacheOption) *Cache {
+	c := &Cache{
 		capacity: capacity,
 		storage:  make(map[string]*Entry),
 		policy:   policy,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.persistence != nil {
+		storage, err := c.persistence.Load()
+		if err != nil {
+			// Starting from an empty cache is preferable to refusing to
+			// start at all - the WAL/snapshot will simply be rebuilt from
+			// here on.
+			fmt.Fprintf(os.Stderr, "cache: failed to load persisted state: %v\n", err)
+		} else {
+			c.storage = storage
+			for key := range storage {
+				c.policy.Add(key)
+			}
+			// A gob stream can't be continued by a second encoder, so fold
+			// the replayed WAL into a snapshot before appending to it again.
+			if err := c.persistence.Snapshot(c.storage); err != nil {
+				fmt.Fprintf(os.Stderr, "cache: failed to snapshot loaded state: %v\n", err)
+			}
+		}
+	}
+	return c
 }
 
 func (c *Cache) Get(key string) (interface{}, bool) {
@@ -68,19 +109,23 @@ func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
 		entry.Value = value
 		entry.ExpireAt = expireAt
 		c.policy.Access(key)
+		c.appendSet(key, entry)
 		return
 	}
 
 	// New key: evict if full
 	if len(c.storage) >= c.capacity {
-		evicted := c.policy.Evict()
+		evicted := c.policy.Evict(key)
 		if evicted != "" {
 			delete(c.storage, evicted)
+			c.appendDelete(evicted)
 		}
 	}
 
-	c.storage[key] = &Entry{Value: value, ExpireAt: expireAt}
+	entry := &Entry{Value: value, ExpireAt: expireAt}
+	c.storage[key] = entry
 	c.policy.Add(key)
+	c.appendSet(key, entry)
 }
 
 // Delete removes the given key from the cache, and from the underlying
@@ -92,5 +137,51 @@ func (c *Cache) Delete(key string) {
 	if _, ok := c.storage[key]; ok {
 		delete(c.storage, key)
 		c.policy.Remove(key)
+		c.appendDelete(key)
+	}
+}
+
+// appendSet and appendDelete record a mutation to the WAL when
+// persistence is configured. Callers must hold c.mu. Append failures are
+// logged rather than propagated - the in-memory cache stays correct
+// either way, and Flush will still capture the current state next time
+// it runs.
+func (c *Cache) appendSet(key string, entry *Entry) {
+	if c.persistence == nil {
+		return
+	}
+	if err := c.persistence.AppendSet(key, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "cache: failed to append set for %q: %v\n", key, err)
+	}
+}
+
+func (c *Cache) appendDelete(key string) {
+	if c.persistence == nil {
+		return
+	}
+	if err := c.persistence.AppendDelete(key); err != nil {
+		fmt.Fprintf(os.Stderr, "cache: failed to append delete for %q: %v\n", key, err)
+	}
+}
+
+// Flush forces a full snapshot of the current cache state, after which
+// the WAL is truncated since the snapshot now captures everything in it.
+// No-op if no Persistence is configured.
+func (c *Cache) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.persistence == nil {
+		return nil
+	}
+	return c.persistence.Snapshot(c.storage)
+}
+
+// Close fsyncs and closes the underlying persistence, if any.
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.persistence == nil {
+		return nil
 	}
+	return c.persistence.Close()
 }
@@ -84,7 +84,9 @@ func (p *LFUPolicy) Add(key string) {
 	p.minFreq = 1
 }
 
-func (p *LFUPolicy) Evict() string {
+// Evict ignores its key argument - see LRUPolicy.Evict for why the
+// parameter exists at all.
+func (p *LFUPolicy) Evict(_ string) string {
 	if p.minFreq == 0 {
 		return ""
 	}
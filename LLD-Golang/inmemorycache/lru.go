@@ -51,7 +51,12 @@ func (p *LRUPolicy) Add(key string) {
 	p.head.next = node
 }
 
-func (p *LRUPolicy) Evict() string {
+// Evict ignores its key argument - LRU's victim is always whatever's
+// least recently used, not something specific to the key being
+// inserted. The parameter exists so LRUPolicy satisfies the same
+// EvictionPolicy signature as policies (like ARC) whose eviction choice
+// does depend on the incoming key.
+func (p *LRUPolicy) Evict(_ string) string {
 	if p.tail.prev == p.head {
 		return ""
 	}
@@ -69,3 +74,16 @@ func (p *LRUPolicy) Remove(key string) {
 		delete(p.nodes, key)
 	}
 }
+
+// PeekLRU reports the least recently used key without evicting it.
+func (p *LRUPolicy) PeekLRU() (string, bool) {
+	if p.tail.prev == p.head {
+		return "", false
+	}
+	return p.tail.prev.key, true
+}
+
+// Len reports how many keys this policy is currently tracking.
+func (p *LRUPolicy) Len() int {
+	return len(p.nodes)
+}
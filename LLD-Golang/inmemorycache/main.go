@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"time"
 )
 
@@ -19,4 +20,63 @@ func main() {
 	lfuCache.Get("keyA") // Increase frequency
 	lfuCache.Set("keyB", "valueB", 0)
 	lfuCache.Set("keyC", "valueC", 0) // Evicts least frequent (keyB)
+
+	// ARC example: adapts between recency (T1) and frequency (T2)
+	// automatically instead of committing to one policy up front.
+	arcCache := NewCache(2, NewARCPolicy(2))
+	arcCache.Set("keyX", "valueX", 0)
+	arcCache.Get("keyX") // promotes keyX into T2 (seen twice)
+	arcCache.Set("keyY", "valueY", 0)
+	arcCache.Set("keyZ", "valueZ", 0) // evicts keyY (T1), keyX survives in T2
+
+	// W-TinyLFU example: a small admission-filtered window in front of an
+	// SLRU main region, so a burst of one-hit keys can't evict something
+	// that's genuinely popular.
+	tinyLFUCache := NewCache(100, NewTinyLFUPolicy(100, 1000))
+	for i := 0; i < 150; i++ {
+		tinyLFUCache.Set(fmt.Sprintf("hot-%d", i%5), i, 0) // small, frequently-reused working set
+		tinyLFUCache.Get(fmt.Sprintf("hot-%d", i%5))
+		tinyLFUCache.Set(fmt.Sprintf("scan-%d", i), i, 0) // one-off scan traffic
+	}
+	for i := 0; i < 5; i++ {
+		_, found := tinyLFUCache.Get(fmt.Sprintf("hot-%d", i))
+		fmt.Printf("hot-%d survived scan: %v\n", i, found)
+	}
+
+	// Persistence example: every Set/Delete is appended to a WAL, Flush
+	// snapshots the full state and truncates it, and a fresh NewCache
+	// against the same files replays the snapshot + WAL tail.
+	snapshotPath := "cache_snapshot.gob.gz"
+	walPath := "cache_wal.gob"
+	defer os.Remove(snapshotPath)
+	defer os.Remove(walPath)
+
+	persistence, err := NewFilePersistence(snapshotPath, walPath)
+	if err != nil {
+		fmt.Println("persistence setup failed:", err)
+		return
+	}
+
+	durableCache := NewCache(10, NewLRUPolicy(), WithPersistence(persistence))
+	durableCache.Set("session:1", "token-abc", 0)
+	durableCache.Set("session:2", "token-def", 0)
+	if err := durableCache.Flush(); err != nil {
+		fmt.Println("flush failed:", err)
+	}
+	durableCache.Set("session:3", "token-ghi", 0) // lands in the WAL, not the snapshot
+	if err := durableCache.Close(); err != nil {
+		fmt.Println("close failed:", err)
+	}
+
+	reopenedPersistence, err := NewFilePersistence(snapshotPath, walPath)
+	if err != nil {
+		fmt.Println("persistence reopen failed:", err)
+		return
+	}
+	reopenedCache := NewCache(10, NewLRUPolicy(), WithPersistence(reopenedPersistence))
+	defer reopenedCache.Close()
+	for _, key := range []string{"session:1", "session:2", "session:3"} {
+		v, ok := reopenedCache.Get(key)
+		fmt.Printf("reopened %s: value=%v found=%v\n", key, v, ok)
+	}
 }
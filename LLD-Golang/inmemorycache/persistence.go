@@ -0,0 +1,204 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Register generic composite values and *Entry so gob can round-trip them
+// under Entry.Value (an interface{}); basic types such as the strings the
+// demo stores are already known to gob. Any other
+// concrete type a caller stores must either be registered the same way
+// or implement gob.GobEncoder/GobDecoder itself - gob refuses to encode
+// an unregistered concrete type through an interface{} field.
+func init() {
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(&Entry{})
+}
+
+// Persistence lets a Cache survive a restart: Snapshot writes a full
+// point-in-time dump of storage, AppendSet/AppendDelete record individual
+// mutations made since the last snapshot, and Load replays the latest
+// snapshot plus whatever was appended after it.
+type Persistence interface {
+	Load() (map[string]*Entry, error)
+	Snapshot(storage map[string]*Entry) error
+	AppendSet(key string, entry *Entry) error
+	AppendDelete(key string) error
+	Close() error
+}
+
+// walOp is one write-ahead-log record.
+type walOp struct {
+	Op    string // "set" or "delete"
+	Key   string
+	Entry *Entry
+}
+
+// FilePersistence is a file-based Persistence: a gzip-compressed gob
+// snapshot file plus a plain gob-encoded append log for everything since
+// the last snapshot. This mirrors the gzip+gob history-file technique
+// long-running Matrix clients use to keep restart-time small while
+// bounding how large the on-disk log grows between snapshots.
+type FilePersistence struct {
+	snapshotPath string
+	walPath      string
+
+	mu      sync.Mutex
+	walFile *os.File
+	walEnc  *gob.Encoder
+}
+
+// NewFilePersistence opens (creating if necessary) the WAL file at
+// walPath, appending to whatever is already there.
+func NewFilePersistence(snapshotPath, walPath string) (*FilePersistence, error) {
+	walFile, err := os.OpenFile(walPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open wal: %w", err)
+	}
+	return &FilePersistence{
+		snapshotPath: snapshotPath,
+		walPath:      walPath,
+		walFile:      walFile,
+		walEnc:       gob.NewEncoder(walFile),
+	}, nil
+}
+
+// Load replays the latest snapshot (if any), then every WAL record
+// written since, reconstructing storage while skipping any entry whose
+// ExpireAt has already passed.
+func (p *FilePersistence) Load() (map[string]*Entry, error) {
+	storage := make(map[string]*Entry)
+
+	if f, err := os.Open(p.snapshotPath); err == nil {
+		decodeErr := func() error {
+			defer f.Close()
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			return gob.NewDecoder(gz).Decode(&storage)
+		}()
+		if decodeErr != nil {
+			return nil, fmt.Errorf("decode snapshot: %w", decodeErr)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("open snapshot: %w", err)
+	}
+
+	walRead, err := os.Open(p.walPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pruneExpired(storage), nil
+		}
+		return nil, fmt.Errorf("open wal: %w", err)
+	}
+	defer walRead.Close()
+
+	dec := gob.NewDecoder(walRead)
+	for {
+		var op walOp
+		if err := dec.Decode(&op); err != nil {
+			break // EOF, or a partial trailing record from a crash mid-append
+		}
+		switch op.Op {
+		case "set":
+			storage[op.Key] = op.Entry
+		case "delete":
+			delete(storage, op.Key)
+		}
+	}
+
+	return pruneExpired(storage), nil
+}
+
+func pruneExpired(storage map[string]*Entry) map[string]*Entry {
+	now := time.Now()
+	for key, entry := range storage {
+		if !entry.ExpireAt.IsZero() && now.After(entry.ExpireAt) {
+			delete(storage, key)
+		}
+	}
+	return storage
+}
+
+// Snapshot writes a full gzip-compressed gob dump of storage to a temp
+// file, renames it into place, and truncates the WAL, since everything
+// in it up to this point is now captured by the snapshot.
+func (p *FilePersistence) Snapshot(storage map[string]*Entry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tmpPath := p.snapshotPath + ".tmp"
+	if err := writeSnapshotFile(tmpPath, storage); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, p.snapshotPath); err != nil {
+		return fmt.Errorf("rename snapshot: %w", err)
+	}
+	return p.truncateWALLocked()
+}
+
+func writeSnapshotFile(path string, storage map[string]*Entry) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	if err := gob.NewEncoder(gz).Encode(storage); err != nil {
+		gz.Close()
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("flush snapshot: %w", err)
+	}
+	return f.Sync()
+}
+
+// truncateWALLocked reopens the WAL file empty. Callers must hold p.mu.
+func (p *FilePersistence) truncateWALLocked() error {
+	if err := p.walFile.Close(); err != nil {
+		return fmt.Errorf("close wal: %w", err)
+	}
+	walFile, err := os.OpenFile(p.walPath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("reopen wal: %w", err)
+	}
+	p.walFile = walFile
+	p.walEnc = gob.NewEncoder(walFile)
+	return nil
+}
+
+func (p *FilePersistence) AppendSet(key string, entry *Entry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.walEnc.Encode(walOp{Op: "set", Key: key, Entry: entry})
+}
+
+func (p *FilePersistence) AppendDelete(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.walEnc.Encode(walOp{Op: "delete", Key: key})
+}
+
+// Close fsyncs and closes the WAL file.
+func (p *FilePersistence) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.walFile.Sync(); err != nil {
+		return err
+	}
+	return p.walFile.Close()
+}
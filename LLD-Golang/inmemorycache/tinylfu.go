@@ -0,0 +1,274 @@
+package main
+
+// cm4Sketch is a 4-bit Count-Min Sketch: each counter saturates at 15,
+// and the whole table is halved once total increments exceed sampleSize,
+// so frequency estimates track recent behavior instead of accumulating
+// forever.
+type cm4Sketch struct {
+	width      int
+	table      [4][]uint8 // one row per hash seed, each cell a 4-bit counter
+	seeds      [4]uint64
+	sampleSize int
+	additions  int
+}
+
+func newCM4Sketch(width, sampleSize int) *cm4Sketch {
+	if width < 1 {
+		width = 1
+	}
+	if sampleSize < 1 {
+		sampleSize = width
+	}
+	s := &cm4Sketch{
+		width:      width,
+		seeds:      [4]uint64{0x9E3779B97F4A7C15, 0xC2B2AE3D27D4EB4F, 0x165667B19E3779F9, 0x27D4EB2F165667C5},
+		sampleSize: sampleSize,
+	}
+	for i := range s.table {
+		s.table[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *cm4Sketch) index(row int, key string) int {
+	return int((fnv1a(key) ^ s.seeds[row]) % uint64(s.width))
+}
+
+// Increment records one observed access for key, then ages the whole
+// sketch if it has seen more than sampleSize increments since the last
+// aging.
+func (s *cm4Sketch) Increment(key string) {
+	for row := range s.table {
+		idx := s.index(row, key)
+		if s.table[row][idx] < 15 {
+			s.table[row][idx]++
+		}
+	}
+	s.additions++
+	if s.additions > s.sampleSize {
+		s.halve()
+	}
+}
+
+// Estimate returns the minimum counter across all rows for key - the
+// standard Count-Min Sketch query, biased high but never low.
+func (s *cm4Sketch) Estimate(key string) uint8 {
+	min := uint8(15)
+	for row := range s.table {
+		if v := s.table[row][s.index(row, key)]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func (s *cm4Sketch) halve() {
+	for row := range s.table {
+		for i := range s.table[row] {
+			s.table[row][i] /= 2
+		}
+	}
+	s.additions = 0
+}
+
+// fnv1a is a tiny string hash, good enough to spread keys across the
+// sketch's rows without pulling in a hashing library for a demo cache.
+func fnv1a(s string) uint64 {
+	var h uint64 = 14695981039346656037
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+const (
+	segWindow = iota
+	segProbation
+	segProtected
+)
+
+// TinyLFUPolicy implements W-TinyLFU: a small window-LRU (about 1% of
+// capacity) admits every new key first. When the window overflows, its
+// LRU entry only gets into the SLRU main region (a probation segment
+// feeding a protected segment) if the Count-Min Sketch estimates it's
+// been seen at least as often as the main region's own LRU victim -
+// otherwise it's dropped outright, keeping one-hit-wonders from ever
+// displacing a frequently reused key.
+type TinyLFUPolicy struct {
+	windowCap    int
+	probationCap int
+	protectedCap int
+
+	window    *LRUPolicy
+	probation *LRUPolicy
+	protected *LRUPolicy
+	location  map[string]int
+
+	sketch *cm4Sketch
+}
+
+// NewTinyLFUPolicy creates a W-TinyLFU policy over capacity entries. The
+// Count-Min Sketch ages itself every sampleSize recorded accesses; a
+// caller with a good sense of the expected working-set churn rate should
+// tune sampleSize accordingly (a common starting point is a small
+// multiple of capacity).
+func NewTinyLFUPolicy(capacity, sampleSize int) *TinyLFUPolicy {
+	windowCap := capacity / 100
+	if windowCap < 1 {
+		windowCap = 1
+	}
+	mainCap := capacity - windowCap
+	if mainCap < 2 {
+		mainCap = 2
+	}
+	protectedCap := mainCap * 4 / 5
+	if protectedCap < 1 {
+		protectedCap = 1
+	}
+	probationCap := mainCap - protectedCap
+	if probationCap < 1 {
+		probationCap = 1
+	}
+	if sampleSize <= 0 {
+		sampleSize = capacity * 10
+	}
+
+	return &TinyLFUPolicy{
+		windowCap:    windowCap,
+		probationCap: probationCap,
+		protectedCap: protectedCap,
+		window:       NewLRUPolicy(),
+		probation:    NewLRUPolicy(),
+		protected:    NewLRUPolicy(),
+		location:     make(map[string]int),
+		sketch:       newCM4Sketch(capacity*8, sampleSize),
+	}
+}
+
+func (p *TinyLFUPolicy) mainLen() int {
+	return p.probation.Len() + p.protected.Len()
+}
+
+// Access handles a cache hit: window entries just move to the front of
+// the window, probation entries graduate into protected (demoting
+// protected's own LRU back to probation if protected is full), and
+// protected entries move to the front of protected.
+func (p *TinyLFUPolicy) Access(key string) {
+	p.sketch.Increment(key)
+	switch p.location[key] {
+	case segWindow:
+		p.window.Access(key)
+	case segProbation:
+		p.probation.Remove(key)
+		delete(p.location, key)
+		p.promoteToProtected(key)
+	case segProtected:
+		p.protected.Access(key)
+	}
+}
+
+func (p *TinyLFUPolicy) promoteToProtected(key string) {
+	if p.protected.Len() >= p.protectedCap {
+		if demoted, ok := p.protected.PeekLRU(); ok {
+			p.protected.Remove(demoted)
+			p.probation.Add(demoted)
+			p.location[demoted] = segProbation
+		}
+	}
+	p.protected.Add(key)
+	p.location[key] = segProtected
+}
+
+// Add handles a miss while the cache still has room: nothing to contest
+// yet, so key enters the window, and whatever that pushes past windowCap
+// moves into probation uncontested. Once the cache fills up, misses
+// route through Evict instead (see Evict's doc comment), so this only
+// ever runs during the initial fill.
+func (p *TinyLFUPolicy) Add(key string) {
+	if _, already := p.location[key]; already {
+		return
+	}
+	p.sketch.Increment(key)
+	p.window.Add(key)
+	p.location[key] = segWindow
+	if p.window.Len() > p.windowCap {
+		if moved := p.window.Evict(""); moved != "" {
+			p.probation.Add(moved)
+			p.location[moved] = segProbation
+		}
+	}
+}
+
+// Evict handles a miss for key once the cache is full. Cache.Set calls
+// Evict before Add, so - unlike the other policies here - TinyLFU does
+// its insertion work in Evict itself: key always enters the window, and
+// if that overflows the window's own LRU entry becomes a candidate that
+// either displaces the main region's LRU victim (if the sketch favors
+// it) or is dropped. Add then sees key already tracked and no-ops.
+func (p *TinyLFUPolicy) Evict(key string) string {
+	p.sketch.Increment(key)
+	p.window.Add(key)
+	p.location[key] = segWindow
+
+	if p.window.Len() <= p.windowCap {
+		return p.evictMain()
+	}
+
+	candidateKey := p.window.Evict("")
+	if candidateKey == "" {
+		return p.evictMain()
+	}
+	delete(p.location, candidateKey)
+
+	if p.mainLen() < p.probationCap+p.protectedCap {
+		p.probation.Add(candidateKey)
+		p.location[candidateKey] = segProbation
+		return ""
+	}
+
+	victimKey, ok := p.probation.PeekLRU()
+	if !ok {
+		p.probation.Add(candidateKey)
+		p.location[candidateKey] = segProbation
+		return ""
+	}
+
+	if p.sketch.Estimate(candidateKey) >= p.sketch.Estimate(victimKey) {
+		p.probation.Remove(victimKey)
+		delete(p.location, victimKey)
+		p.probation.Add(candidateKey)
+		p.location[candidateKey] = segProbation
+		return victimKey
+	}
+	return candidateKey
+}
+
+// evictMain evicts the SLRU main region's own LRU victim - probation's,
+// falling back to protected's - for the rare case where the window
+// itself isn't over-capacity but the cache overall still is.
+func (p *TinyLFUPolicy) evictMain() string {
+	if key, ok := p.probation.PeekLRU(); ok {
+		p.probation.Remove(key)
+		delete(p.location, key)
+		return key
+	}
+	if key, ok := p.protected.PeekLRU(); ok {
+		p.protected.Remove(key)
+		delete(p.location, key)
+		return key
+	}
+	return ""
+}
+
+func (p *TinyLFUPolicy) Remove(key string) {
+	switch p.location[key] {
+	case segWindow:
+		p.window.Remove(key)
+	case segProbation:
+		p.probation.Remove(key)
+	case segProtected:
+		p.protected.Remove(key)
+	}
+	delete(p.location, key)
+}
@@ -0,0 +1,201 @@
+package main
+
+import "time"
+
+// intervalNode is one node of Calendar's augmented AVL interval tree:
+// besides the usual BST ordering by (StartTime, MeetingID), each node
+// tracks maxEnd - the largest EndTime anywhere in its subtree - so a
+// query can skip an entire subtree once it's known none of its
+// intervals can reach far enough to overlap. height is maintained
+// alongside maxEnd so insert/delete can rebalance in O(log n), keeping
+// the O(log n) conflict-detection guarantee even when meetings are
+// booked in chronological order (the case a plain BST degenerates on).
+type intervalNode struct {
+	meeting     *Meeting
+	maxEnd      time.Time
+	height      int
+	left, right *intervalNode
+}
+
+// height returns n's cached subtree height, treating nil as height 0.
+func height(n *intervalNode) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// balanceFactor is the AVL balance of n: positive means left-heavy.
+func balanceFactor(n *intervalNode) int {
+	return height(n.left) - height(n.right)
+}
+
+// update recomputes n's height and maxEnd from its children; callers
+// must run it bottom-up after any change to n.left or n.right.
+func (n *intervalNode) update() {
+	n.height = 1 + maxInt(height(n.left), height(n.right))
+	n.recomputeMaxEnd()
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// rotateRight performs a standard AVL right rotation around n and
+// returns the new subtree root.
+func rotateRight(n *intervalNode) *intervalNode {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// rotateLeft performs a standard AVL left rotation around n and
+// returns the new subtree root.
+func rotateLeft(n *intervalNode) *intervalNode {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// rebalance updates n's height/maxEnd and, if n has drifted outside the
+// AVL [-1, 1] balance range, rotates it back into range. It returns the
+// (possibly new) subtree root.
+func rebalance(n *intervalNode) *intervalNode {
+	n.update()
+	switch bf := balanceFactor(n); {
+	case bf > 1:
+		if balanceFactor(n.left) < 0 {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	case bf < -1:
+		if balanceFactor(n.right) > 0 {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	default:
+		return n
+	}
+}
+
+// less orders meetings by StartTime, breaking ties by MeetingID so two
+// meetings booked at the exact same instant still have a total order.
+func less(a, b *Meeting) bool {
+	if a.StartTime.Equal(b.StartTime) {
+		return a.MeetingID < b.MeetingID
+	}
+	return a.StartTime.Before(b.StartTime)
+}
+
+// insertInterval adds meeting into the subtree rooted at n and returns
+// the (possibly new) subtree root, rebalancing and updating maxEnd on
+// the way back up.
+func insertInterval(n *intervalNode, meeting *Meeting) *intervalNode {
+	if n == nil {
+		return &intervalNode{meeting: meeting, maxEnd: meeting.EndTime, height: 1}
+	}
+	if less(meeting, n.meeting) {
+		n.left = insertInterval(n.left, meeting)
+	} else {
+		n.right = insertInterval(n.right, meeting)
+	}
+	return rebalance(n)
+}
+
+// removeInterval deletes meeting from the subtree rooted at n and
+// returns the (possibly new) subtree root, rebalancing and recomputing
+// maxEnd on the way back up.
+func removeInterval(n *intervalNode, meeting *Meeting) *intervalNode {
+	if n == nil {
+		return nil
+	}
+	switch {
+	case meeting.MeetingID == n.meeting.MeetingID:
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.meeting = successor.meeting
+		n.right = removeInterval(n.right, successor.meeting)
+	case less(meeting, n.meeting):
+		n.left = removeInterval(n.left, meeting)
+	default:
+		n.right = removeInterval(n.right, meeting)
+	}
+	return rebalance(n)
+}
+
+func (n *intervalNode) recomputeMaxEnd() {
+	n.maxEnd = n.meeting.EndTime
+	if n.left != nil && n.left.maxEnd.After(n.maxEnd) {
+		n.maxEnd = n.left.maxEnd
+	}
+	if n.right != nil && n.right.maxEnd.After(n.maxEnd) {
+		n.maxEnd = n.right.maxEnd
+	}
+}
+
+func overlapsSlot(n *intervalNode, slot *TimeSlot) bool {
+	meetingSlot := &TimeSlot{StartTime: n.meeting.StartTime, EndTime: n.meeting.EndTime}
+	return meetingSlot.Overlaps(slot)
+}
+
+// hasOverlap reports whether any interval in the subtree rooted at n
+// overlaps slot, pruning a subtree entirely once its maxEnd can no
+// longer reach slot.StartTime.
+func hasOverlap(n *intervalNode, slot *TimeSlot) bool {
+	if n == nil || !n.maxEnd.After(slot.StartTime) {
+		return false
+	}
+	if hasOverlap(n.left, slot) {
+		return true
+	}
+	if overlapsSlot(n, slot) {
+		return true
+	}
+	if n.meeting.StartTime.Before(slot.EndTime) {
+		return hasOverlap(n.right, slot)
+	}
+	return false
+}
+
+// collectOverlaps appends every meeting in the subtree rooted at n whose
+// interval intersects slot, using the same maxEnd pruning as hasOverlap.
+func collectOverlaps(n *intervalNode, slot *TimeSlot, out *[]*Meeting) {
+	if n == nil || !n.maxEnd.After(slot.StartTime) {
+		return
+	}
+	collectOverlaps(n.left, slot, out)
+	if overlapsSlot(n, slot) {
+		*out = append(*out, n.meeting)
+	}
+	if n.meeting.StartTime.Before(slot.EndTime) {
+		collectOverlaps(n.right, slot, out)
+	}
+}
+
+// inOrder appends every meeting in the subtree rooted at n in StartTime
+// order.
+func inOrder(n *intervalNode, out *[]*Meeting) {
+	if n == nil {
+		return
+	}
+	inOrder(n.left, out)
+	*out = append(*out, n.meeting)
+	inOrder(n.right, out)
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"time"
+
+	"meeting-schedular/storage"
 )
 
 // ==================== Interfaces ====================
@@ -147,31 +152,51 @@ func (mr *MeetingRoom) GetRoomID() int {
 	return mr.MeetingRoomID
 }
 
+// CancelMeeting frees meetingID's slot in this room's calendar.
+func (mr *MeetingRoom) CancelMeeting(meetingID int) (*Meeting, bool) {
+	return mr.Calendar.CancelMeeting(meetingID)
+}
+
+// FindOverlaps returns every meeting booked in this room that overlaps slot.
+func (mr *MeetingRoom) FindOverlaps(slot *TimeSlot) []*Meeting {
+	return mr.Calendar.FindOverlaps(slot)
+}
+
+// NextFreeSlot returns the first slot of length dur, at or after after,
+// that's free in this room.
+func (mr *MeetingRoom) NextFreeSlot(after time.Time, dur time.Duration) *TimeSlot {
+	return mr.Calendar.NextFreeSlot(after, dur)
+}
+
+// MeetingsBetween returns every meeting booked in this room that
+// overlaps [t1, t2).
+func (mr *MeetingRoom) MeetingsBetween(t1, t2 time.Time) []*Meeting {
+	return mr.Calendar.MeetingsBetween(t1, t2)
+}
+
 // ==================== Calendar ====================
+
+// Calendar tracks a room's bookings in an augmented interval tree (see
+// interval_tree.go) instead of a flat map, so conflict detection and
+// range queries no longer have to scan every booking in the room.
 type Calendar struct {
-	ScheduledMeetings map[int]*Meeting
-	MeetingRoomID     int
-	mu                sync.Mutex
+	root          *intervalNode
+	byID          map[int]*Meeting
+	MeetingRoomID int
+	mu            sync.Mutex
 }
 
 func NewCalendar(meetingRoomId int) *Calendar {
 	return &Calendar{
-		MeetingRoomID:     meetingRoomId,
-		ScheduledMeetings: make(map[int]*Meeting),
+		MeetingRoomID: meetingRoomId,
+		byID:          make(map[int]*Meeting),
 	}
 }
 
 func (c *Calendar) IsSlotAvailable(slot *TimeSlot) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-
-	for _, meeting := range c.ScheduledMeetings {
-		meetingSlot := &TimeSlot{StartTime: meeting.StartTime, EndTime: meeting.EndTime}
-		if meetingSlot.Overlaps(slot) {
-			return false
-		}
-	}
-	return true
+	return !hasOverlap(c.root, slot)
 }
 
 func (c *Calendar) ScheduleMeeting(meeting *Meeting) error {
@@ -179,19 +204,70 @@ func (c *Calendar) ScheduleMeeting(meeting *Meeting) error {
 	defer c.mu.Unlock()
 
 	meetingSlot := &TimeSlot{StartTime: meeting.StartTime, EndTime: meeting.EndTime}
-
-	// Check Conflicts
-	for _, existingMeeting := range c.ScheduledMeetings {
-		existingSlot := &TimeSlot{StartTime: existingMeeting.StartTime, EndTime: existingMeeting.EndTime}
-		if existingSlot.Overlaps(meetingSlot) {
-			return errors.New("time slot conflicts")
-		}
+	if hasOverlap(c.root, meetingSlot) {
+		return errors.New("time slot conflicts")
 	}
 
-	c.ScheduledMeetings[meeting.MeetingID] = meeting
+	c.root = insertInterval(c.root, meeting)
+	c.byID[meeting.MeetingID] = meeting
 	return nil
 }
 
+// CancelMeeting removes meetingID from the calendar, freeing its slot,
+// and reports whether a meeting with that ID was found.
+func (c *Calendar) CancelMeeting(meetingID int) (*Meeting, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	meeting, ok := c.byID[meetingID]
+	if !ok {
+		return nil, false
+	}
+	c.root = removeInterval(c.root, meeting)
+	delete(c.byID, meetingID)
+	return meeting, true
+}
+
+// FindOverlaps returns every scheduled meeting whose interval intersects slot.
+func (c *Calendar) FindOverlaps(slot *TimeSlot) []*Meeting {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []*Meeting
+	collectOverlaps(c.root, slot, &out)
+	return out
+}
+
+// MeetingsBetween returns every scheduled meeting whose interval
+// intersects [t1, t2).
+func (c *Calendar) MeetingsBetween(t1, t2 time.Time) []*Meeting {
+	return c.FindOverlaps(&TimeSlot{StartTime: t1, EndTime: t2})
+}
+
+// NextFreeSlot returns the first slot of length dur, starting at or
+// after after, that doesn't overlap any scheduled meeting. It walks the
+// calendar in StartTime order looking for a gap wide enough for dur.
+func (c *Calendar) NextFreeSlot(after time.Time, dur time.Duration) *TimeSlot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var ordered []*Meeting
+	inOrder(c.root, &ordered)
+
+	candidate := after
+	for _, m := range ordered {
+		if !m.EndTime.After(candidate) {
+			continue // this meeting ends before our candidate slot even starts
+		}
+		if m.StartTime.Sub(candidate) >= dur {
+			return NewTimeSlot(candidate, dur)
+		}
+		if m.EndTime.After(candidate) {
+			candidate = m.EndTime
+		}
+	}
+	return NewTimeSlot(candidate, dur)
+}
+
 // ==================== RoomBookingStrategy ====================
 type RoomBookingStrategy interface {
 	BookRoom(rooms []*MeetingRoom, slot *TimeSlot, participantsCount int) *MeetingRoom
@@ -217,15 +293,34 @@ type MeetingSchedular struct {
 	MeetingRooms        []*MeetingRoom
 	HistoryMeetings     []*Meeting
 	RoomBookingStrategy RoomBookingStrategy
+	waitlist            *Waitlist
+	repo                storage.MeetingRepo
 	mu                  sync.RWMutex
 	meetingCounter      int
+	waitlistCounter     int
 }
 
 func NewMeetingSchedular(strategy RoomBookingStrategy) *MeetingSchedular {
+	return NewMeetingSchedularWithWaitlistPolicy(strategy, NewFIFOWaitlistPolicy())
+}
+
+// NewMeetingSchedularWithWaitlistPolicy is NewMeetingSchedular with an
+// explicit policy for deciding which waiting request claims a freed slot.
+func NewMeetingSchedularWithWaitlistPolicy(strategy RoomBookingStrategy, waitlistPolicy WaitlistPolicy) *MeetingSchedular {
+	return NewMeetingSchedularWithRepo(strategy, waitlistPolicy, storage.NewInMemoryMeetingRepo())
+}
+
+// NewMeetingSchedularWithRepo is NewMeetingSchedularWithWaitlistPolicy
+// with an explicit MeetingRepo, so scheduled meetings survive a restart
+// instead of living only in HistoryMeetings. Pass
+// storage.NewInMemoryMeetingRepo() for the old zero-dependency behavior.
+func NewMeetingSchedularWithRepo(strategy RoomBookingStrategy, waitlistPolicy WaitlistPolicy, repo storage.MeetingRepo) *MeetingSchedular {
 	return &MeetingSchedular{
 		MeetingRooms:        make([]*MeetingRoom, 0),
 		HistoryMeetings:     make([]*Meeting, 0),
 		RoomBookingStrategy: strategy,
+		waitlist:            NewWaitlist(waitlistPolicy),
+		repo:                repo,
 		meetingCounter:      1,
 	}
 }
@@ -240,7 +335,13 @@ func (ms *MeetingSchedular) AddMeetingRoom(room *MeetingRoom) {
 	ms.MeetingRooms = append(ms.MeetingRooms, room)
 }
 
-func (ms *MeetingSchedular) ScheduleMeeting(title, description string, startTime time.Time, duration time.Duration, host NotificationObserver, participants []NotificationObserver) (*Meeting, error) {
+// ScheduleMeeting tries to book a room immediately. If none is free, the
+// request is enqueued on the waitlist instead of simply failing: the
+// returned WaitlistTicket lets the caller poll or block on Promoted()
+// for when a later cancellation frees a slot that fits. maxWait bounds
+// how long the request stays on the waitlist before it's treated as
+// expired; pass 0 to wait indefinitely.
+func (ms *MeetingSchedular) ScheduleMeeting(title, description string, startTime time.Time, duration time.Duration, host NotificationObserver, participants []NotificationObserver, maxWait time.Duration) (*Meeting, *WaitlistTicket, error) {
 	slot := NewTimeSlot(startTime, duration)
 	participantsCount := len(participants) + 1
 
@@ -250,14 +351,11 @@ func (ms *MeetingSchedular) ScheduleMeeting(title, description string, startTime
 	ms.mu.RUnlock()
 
 	if room == nil {
-		return nil, errors.New("no available room found for the given time slot")
+		return nil, ms.enqueueWaitlist(title, description, startTime, duration, host, participants, maxWait), nil
 	}
 
 	// create meeting
-	ms.mu.Lock()
-	meeting := NewMeeting(ms.meetingCounter, room.MeetingRoomID, title, description, startTime, startTime.Add(duration), host)
-	ms.meetingCounter++
-	ms.mu.Unlock()
+	meeting := NewMeeting(ms.nextMeetingID(), room.MeetingRoomID, title, description, startTime, startTime.Add(duration), host)
 
 	// add participants
 	for _, p := range participants {
@@ -266,7 +364,7 @@ func (ms *MeetingSchedular) ScheduleMeeting(title, description string, startTime
 
 	// book the room
 	if err := room.BookMeetingRoom(meeting); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// add to history
@@ -274,13 +372,163 @@ func (ms *MeetingSchedular) ScheduleMeeting(title, description string, startTime
 	ms.HistoryMeetings = append(ms.HistoryMeetings, meeting)
 	ms.mu.Unlock()
 
-	return meeting, nil
+	ms.persist(meeting)
+
+	return meeting, nil, nil
+}
+
+func (ms *MeetingSchedular) nextMeetingID() int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	id := ms.meetingCounter
+	ms.meetingCounter++
+	return id
+}
+
+// persist mirrors meeting into the repo, if its host and every
+// participant resolve to a *User - see MeetingRepo's doc comment for why
+// that's the limit. Logs rather than fails the caller on a write error,
+// matching inmemorycache.Cache's appendSet.
+func (ms *MeetingSchedular) persist(meeting *Meeting) {
+	host, ok := meeting.Host.(*User)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "meeting-schedular: skipping persistence for meeting %d: host is not a *User\n", meeting.MeetingID)
+		return
+	}
+	participants := make([]storage.ParticipantRef, 0, len(meeting.Participants))
+	for _, p := range meeting.Participants {
+		u, ok := p.(*User)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "meeting-schedular: skipping persistence for meeting %d: participant is not a *User\n", meeting.MeetingID)
+			return
+		}
+		participants = append(participants, storage.ParticipantRef{UserID: u.UserID})
+	}
+
+	state := storage.MeetingState{
+		MeetingID:     meeting.MeetingID,
+		MeetingRoomID: meeting.MeetingRoomID,
+		Title:         meeting.Title,
+		Description:   meeting.Description,
+		StartTime:     meeting.StartTime,
+		EndTime:       meeting.EndTime,
+		HostID:        host.UserID,
+		Participants:  participants,
+	}
+	if err := ms.repo.Save(context.Background(), state); err != nil {
+		fmt.Fprintf(os.Stderr, "meeting-schedular: failed to persist meeting %d: %v\n", meeting.MeetingID, err)
+	}
+}
+
+// enqueueWaitlist records a booking request that couldn't be scheduled
+// immediately and returns the ticket its caller can poll or wait on.
+func (ms *MeetingSchedular) enqueueWaitlist(title, description string, startTime time.Time, duration time.Duration, host NotificationObserver, participants []NotificationObserver, maxWait time.Duration) *WaitlistTicket {
+	ms.mu.Lock()
+	ms.waitlistCounter++
+	requestID := fmt.Sprintf("WL-%d", ms.waitlistCounter)
+	ms.mu.Unlock()
+
+	var maxWaitUntil time.Time
+	if maxWait > 0 {
+		maxWaitUntil = time.Now().Add(maxWait)
+	}
+
+	ticket := newWaitlistTicket(requestID)
+	ms.waitlist.enqueue(&WaitlistEntry{
+		RequestID:    requestID,
+		Title:        title,
+		Desc:         description,
+		Start:        startTime,
+		Duration:     duration,
+		Host:         host,
+		Participants: participants,
+		EnqueuedAt:   time.Now(),
+		MaxWaitUntil: maxWaitUntil,
+		ticket:       ticket,
+	})
+	return ticket
+}
+
+// CancelMeeting cancels meetingID, freeing its slot in whichever room's
+// calendar holds it, then tries to promote the best-fit waitlist entry
+// into that freed slot. The meeting itself stays in HistoryMeetings for
+// audit purposes.
+func (ms *MeetingSchedular) CancelMeeting(meetingID int) error {
+	ms.mu.RLock()
+	rooms := make([]*MeetingRoom, len(ms.MeetingRooms))
+	copy(rooms, ms.MeetingRooms)
+	ms.mu.RUnlock()
+
+	for _, room := range rooms {
+		meeting, ok := room.CancelMeeting(meetingID)
+		if !ok {
+			continue
+		}
+		if err := ms.repo.Delete(context.Background(), meetingID); err != nil {
+			fmt.Fprintf(os.Stderr, "meeting-schedular: failed to delete persisted meeting %d: %v\n", meetingID, err)
+		}
+		freedSlot := &TimeSlot{StartTime: meeting.StartTime, EndTime: meeting.EndTime}
+		ms.tryPromoteWaitlist(room, freedSlot)
+		return nil
+	}
+	return fmt.Errorf("meeting %d not found", meetingID)
+}
+
+// tryPromoteWaitlist looks for the waitlist's best-fit entry for a slot
+// that just freed up in room, books it if one fits, removes it from the
+// waitlist, and notifies its ticket and participants through the
+// existing NotifyUsers path.
+func (ms *MeetingSchedular) tryPromoteWaitlist(room *MeetingRoom, freedSlot *TimeSlot) {
+	candidates := ms.waitlist.candidatesFor(freedSlot, room.Capacity, time.Now())
+	chosen := ms.waitlist.policy.SelectNext(candidates)
+	if chosen == nil {
+		return
+	}
+
+	meeting := NewMeeting(ms.nextMeetingID(), room.MeetingRoomID, chosen.Title, chosen.Desc, chosen.Start, chosen.Start.Add(chosen.Duration), chosen.Host)
+	for _, p := range chosen.Participants {
+		meeting.AddParticipants(p)
+	}
+	if err := room.BookMeetingRoom(meeting); err != nil {
+		// Someone else claimed the slot in the meantime; leave the entry
+		// queued for the next promotion attempt.
+		return
+	}
+
+	ms.mu.Lock()
+	ms.HistoryMeetings = append(ms.HistoryMeetings, meeting)
+	ms.mu.Unlock()
+
+	ms.persist(meeting)
+	ms.waitlist.remove(chosen)
+	chosen.ticket.promote(meeting)
+	ms.Notify(meeting)
 }
 
 // ---------------------------------------------------------------------------------------------
 
 func main() {
-	// new meeting schedular
+	// `go run . migrate up|down|status` manages schema_migrations against
+	// whatever DATABASE_DRIVER/DATABASE_URL point at - see
+	// shopping-cart-deisgn/migrate.go for the same mechanism.
+	if driver := os.Getenv("DATABASE_DRIVER"); driver != "" {
+		db, err := sql.Open(driver, os.Getenv("DATABASE_URL"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: open %s: %v\n", driver, err)
+			os.Exit(1)
+		}
+		defer db.Close()
+		if handled, err := runMigrateCLI(os.Args, db); handled {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	// new meeting schedular; NewMeetingSchedularWithRepo(strategy, policy,
+	// storage.NewGormMeetingRepo(db)) would make it durable across restarts.
 	meetingSchedular := NewMeetingSchedular(NewFCFSRoomBookingStrategy())
 
 	// Rooms
@@ -303,30 +551,36 @@ func main() {
 	// Concurrent booking attempt
 	go func() {
 		defer wg.Done()
-		meeting, err := meetingSchedular.ScheduleMeeting(
+		meeting, ticket, err := meetingSchedular.ScheduleMeeting(
 			"Design Discussion",
 			"LLD interview prep",
 			start,
 			30*time.Minute,
 			host,
 			[]NotificationObserver{u1, u2},
+			0,
 		)
 		if err != nil {
 			fmt.Println("Booking 1 failed:", err)
 			return
 		}
+		if meeting == nil {
+			fmt.Println("Booking 1 waitlisted, ticket:", ticket.RequestID)
+			return
+		}
 		meetingSchedular.Notify(meeting)
 	}()
 
 	// go func() {
 	// 	defer wg.Done()
-	// 	_, err := meetingSchedular.ScheduleMeeting(
+	// 	_, _, err := meetingSchedular.ScheduleMeeting(
 	// 		"Parallel Booking",
 	// 		"Conflict test",
 	// 		start,
 	// 		30*time.Minute,
 	// 		host,
 	// 		[]NotificationObserver{u1},
+	// 		0,
 	// 	)
 	// 	if err != nil {
 	// 		fmt.Println("Booking 2 failed:", err)
@@ -334,4 +588,42 @@ func main() {
 	// }()
 
 	wg.Wait()
+
+	// Waitlist + auto-reallocation: fill both rooms for the same slot so
+	// a third request has nowhere to go and falls onto the waitlist;
+	// cancelling one booking frees a slot and promotes the waiting
+	// request straight into it.
+	fmt.Println("\n=== Waitlist ===")
+	u3 := NewUser(4, "Priya", "priya@gmail.com", "4444")
+	u4 := NewUser(5, "Dev", "dev@gmail.com", "5555")
+	waitlistStart := start.Add(2 * time.Hour)
+
+	meetingA, _, _ := meetingSchedular.ScheduleMeeting(
+		"Room Filler A", "", waitlistStart, 30*time.Minute, host, nil, 0,
+	)
+	meetingB, _, _ := meetingSchedular.ScheduleMeeting(
+		"Room Filler B", "", waitlistStart, 30*time.Minute, host, []NotificationObserver{u1, u2}, 0,
+	)
+	fmt.Printf("Filled both rooms at %v: meetingA=%v meetingB=%v\n", waitlistStart, meetingA != nil, meetingB != nil)
+
+	waitMeeting, ticket, err := meetingSchedular.ScheduleMeeting(
+		"Design Review", "Needs a room", waitlistStart, 30*time.Minute, host,
+		[]NotificationObserver{u3, u4}, time.Hour,
+	)
+	if err != nil {
+		fmt.Println("Booking 3 failed:", err)
+	} else if waitMeeting == nil {
+		fmt.Println("Booking 3 waitlisted, ticket:", ticket.RequestID)
+	}
+
+	fmt.Printf("Cancelling meeting %d to free its room\n", meetingA.MeetingID)
+	if err := meetingSchedular.CancelMeeting(meetingA.MeetingID); err != nil {
+		fmt.Println("Cancel failed:", err)
+	}
+	select {
+	case promoted := <-ticket.Promoted():
+		fmt.Printf("Waitlisted request promoted into meeting %d\n", promoted.MeetingID)
+	default:
+		fmt.Println("Waitlisted request still pending")
+	}
 }
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserRow is the GORM row backing UserRepo, following the same
+// gorm.Model-embedding convention as model.User/model.Ride in
+// go-cab-booking-system.
+type UserRow struct {
+	gorm.Model
+	UserRefID int `gorm:"uniqueIndex"`
+	Name      string
+	Email     string `gorm:"uniqueIndex"`
+	Phone     string
+}
+
+// MeetingRow and ParticipantRow together back MeetingRepo: one
+// MeetingRow per meeting, with its participants in a one-to-many
+// ParticipantRow table.
+type MeetingRow struct {
+	gorm.Model
+	MeetingRefID  int `gorm:"uniqueIndex"`
+	MeetingRoomID int
+	Title         string
+	Description   string
+	StartTime     time.Time
+	EndTime       time.Time
+	HostUserID    int
+	Participants  []ParticipantRow `gorm:"foreignKey:MeetingRowID"`
+}
+
+type ParticipantRow struct {
+	gorm.Model
+	MeetingRowID uint
+	UserID       int
+}
+
+// GormMeetingRepo is the production MeetingRepo, backed by Postgres or
+// SQLite through GORM's respective drivers depending on how db was
+// opened.
+type GormMeetingRepo struct {
+	db *gorm.DB
+}
+
+func NewGormMeetingRepo(db *gorm.DB) *GormMeetingRepo {
+	return &GormMeetingRepo{db: db}
+}
+
+func (r *GormMeetingRepo) Save(ctx context.Context, m MeetingState) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := MeetingRow{
+			MeetingRefID:  m.MeetingID,
+			MeetingRoomID: m.MeetingRoomID,
+			Title:         m.Title,
+			Description:   m.Description,
+			StartTime:     m.StartTime,
+			EndTime:       m.EndTime,
+			HostUserID:    m.HostID,
+		}
+		if err := tx.Where(MeetingRow{MeetingRefID: m.MeetingID}).Assign(row).FirstOrCreate(&row).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("meeting_row_id = ?", row.ID).Delete(&ParticipantRow{}).Error; err != nil {
+			return err
+		}
+		participants := make([]ParticipantRow, len(m.Participants))
+		for i, p := range m.Participants {
+			participants[i] = ParticipantRow{MeetingRowID: row.ID, UserID: p.UserID}
+		}
+		if len(participants) == 0 {
+			return nil
+		}
+		return tx.Create(&participants).Error
+	})
+}
+
+func (r *GormMeetingRepo) FindByID(ctx context.Context, id int) (MeetingState, error) {
+	var row MeetingRow
+	err := r.db.WithContext(ctx).Preload("Participants").Where("meeting_ref_id = ?", id).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return MeetingState{}, ErrNotFound
+	}
+	if err != nil {
+		return MeetingState{}, err
+	}
+	return meetingStateFromRow(row), nil
+}
+
+func (r *GormMeetingRepo) Delete(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Where("meeting_ref_id = ?", id).Delete(&MeetingRow{}).Error
+}
+
+func (r *GormMeetingRepo) All(ctx context.Context) ([]MeetingState, error) {
+	var rows []MeetingRow
+	if err := r.db.WithContext(ctx).Preload("Participants").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]MeetingState, len(rows))
+	for i, row := range rows {
+		out[i] = meetingStateFromRow(row)
+	}
+	return out, nil
+}
+
+func meetingStateFromRow(row MeetingRow) MeetingState {
+	participants := make([]ParticipantRef, len(row.Participants))
+	for i, p := range row.Participants {
+		participants[i] = ParticipantRef{UserID: p.UserID}
+	}
+	return MeetingState{
+		MeetingID:     row.MeetingRefID,
+		MeetingRoomID: row.MeetingRoomID,
+		Title:         row.Title,
+		Description:   row.Description,
+		StartTime:     row.StartTime,
+		EndTime:       row.EndTime,
+		HostID:        row.HostUserID,
+		Participants:  participants,
+	}
+}
+
+// GormUserRepo is the production UserRepo.
+type GormUserRepo struct {
+	db *gorm.DB
+}
+
+func NewGormUserRepo(db *gorm.DB) *GormUserRepo {
+	return &GormUserRepo{db: db}
+}
+
+func (r *GormUserRepo) Save(ctx context.Context, u UserState) error {
+	row := UserRow{UserRefID: u.UserID, Name: u.Name, Email: u.Email, Phone: u.Phone}
+	return r.db.WithContext(ctx).Where(UserRow{UserRefID: u.UserID}).Assign(row).FirstOrCreate(&row).Error
+}
+
+func (r *GormUserRepo) FindByID(ctx context.Context, id int) (UserState, error) {
+	var row UserRow
+	err := r.db.WithContext(ctx).Where("user_ref_id = ?", id).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return UserState{}, ErrNotFound
+	}
+	if err != nil {
+		return UserState{}, err
+	}
+	return UserState{UserID: row.UserRefID, Name: row.Name, Email: row.Email, Phone: row.Phone}, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryMeetingRepo is the default MeetingRepo, used when no database
+// is wired up.
+type InMemoryMeetingRepo struct {
+	mu       sync.Mutex
+	meetings map[int]MeetingState
+}
+
+func NewInMemoryMeetingRepo() *InMemoryMeetingRepo {
+	return &InMemoryMeetingRepo{meetings: make(map[int]MeetingState)}
+}
+
+func (r *InMemoryMeetingRepo) Save(ctx context.Context, m MeetingState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.meetings[m.MeetingID] = m
+	return nil
+}
+
+func (r *InMemoryMeetingRepo) FindByID(ctx context.Context, id int) (MeetingState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.meetings[id]
+	if !ok {
+		return MeetingState{}, ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *InMemoryMeetingRepo) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.meetings, id)
+	return nil
+}
+
+func (r *InMemoryMeetingRepo) All(ctx context.Context) ([]MeetingState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]MeetingState, 0, len(r.meetings))
+	for _, m := range r.meetings {
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// InMemoryUserRepo is the default UserRepo, used when no database is
+// wired up.
+type InMemoryUserRepo struct {
+	mu    sync.Mutex
+	users map[int]UserState
+}
+
+func NewInMemoryUserRepo() *InMemoryUserRepo {
+	return &InMemoryUserRepo{users: make(map[int]UserState)}
+}
+
+func (r *InMemoryUserRepo) Save(ctx context.Context, u UserState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[u.UserID] = u
+	return nil
+}
+
+func (r *InMemoryUserRepo) FindByID(ctx context.Context, id int) (UserState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return UserState{}, ErrNotFound
+	}
+	return u, nil
+}
@@ -0,0 +1,63 @@
+// Package storage gives MeetingSchedular a durable system of record
+// instead of the in-process HistoryMeetings slice it started with.
+//
+// WHY: same tradeoff as shopping-cart-deisgn/storage and
+// splitwise/eventstore - Repository interfaces let a caller swap in a
+// real database while the in-memory implementation keeps `go run .`
+// working with zero external dependencies.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by a repo when the requested row doesn't exist.
+var ErrNotFound = errors.New("storage: not found")
+
+// ParticipantRef identifies a meeting's host or a participant by the
+// User they resolve to. MeetingState can only record participants that
+// are backed by a *User today - see MeetingRepo's doc comment.
+type ParticipantRef struct {
+	UserID int
+}
+
+// MeetingState is everything a Meeting needs to resume after a restart.
+type MeetingState struct {
+	MeetingID     int
+	MeetingRoomID int
+	Title         string
+	Description   string
+	StartTime     time.Time
+	EndTime       time.Time
+	HostID        int
+	Participants  []ParticipantRef
+}
+
+// UserState mirrors the meeting-schedular domain's User.
+type UserState struct {
+	UserID int
+	Name   string
+	Email  string
+	Phone  string
+}
+
+// MeetingRepo persists MeetingState rows.
+//
+// Meeting.Host/Participants are NotificationObserver interface values,
+// not plain data, so only meetings whose host and participants are all
+// *User can be persisted today - MeetingSchedular skips the repo write
+// (logging instead) for any other NotificationObserver implementation.
+type MeetingRepo interface {
+	Save(ctx context.Context, m MeetingState) error
+	FindByID(ctx context.Context, id int) (MeetingState, error)
+	Delete(ctx context.Context, id int) error
+	All(ctx context.Context) ([]MeetingState, error)
+}
+
+// UserRepo persists UserState rows.
+type UserRepo interface {
+	Save(ctx context.Context, u UserState) error
+	FindByID(ctx context.Context, id int) (UserState, error)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// WaitlistEntry is a booking request that couldn't be scheduled
+// immediately and is waiting for a room to free up.
+type WaitlistEntry struct {
+	RequestID    string
+	Title        string
+	Desc         string
+	Start        time.Time
+	Duration     time.Duration
+	Host         NotificationObserver
+	Participants []NotificationObserver
+	EnqueuedAt   time.Time
+	MaxWaitUntil time.Time // zero value means it never expires
+	Priority     int
+
+	ticket *WaitlistTicket
+}
+
+func (e *WaitlistEntry) participantsCount() int {
+	return len(e.Participants) + 1
+}
+
+// expired reports whether MaxWaitUntil has passed.
+func (e *WaitlistEntry) expired(now time.Time) bool {
+	return !e.MaxWaitUntil.IsZero() && now.After(e.MaxWaitUntil)
+}
+
+// WaitlistStatus is the lifecycle state of a WaitlistTicket.
+type WaitlistStatus int
+
+const (
+	WaitlistPending WaitlistStatus = iota
+	WaitlistPromoted
+	WaitlistExpired
+)
+
+// WaitlistTicket is what ScheduleMeeting hands back when it falls back to
+// the waitlist instead of booking immediately. A caller can either poll
+// Status/Meeting or block on Promoted() for the channel to fire once
+// tryPromoteWaitlist books this entry.
+type WaitlistTicket struct {
+	RequestID string
+
+	mu       sync.Mutex
+	status   WaitlistStatus
+	meeting  *Meeting
+	notifyCh chan *Meeting
+}
+
+func newWaitlistTicket(requestID string) *WaitlistTicket {
+	return &WaitlistTicket{
+		RequestID: requestID,
+		status:    WaitlistPending,
+		notifyCh:  make(chan *Meeting, 1),
+	}
+}
+
+func (t *WaitlistTicket) Status() WaitlistStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+func (t *WaitlistTicket) Meeting() *Meeting {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.meeting
+}
+
+// Promoted returns a channel that receives the booked Meeting exactly
+// once, when tryPromoteWaitlist promotes this ticket's entry.
+func (t *WaitlistTicket) Promoted() <-chan *Meeting {
+	return t.notifyCh
+}
+
+func (t *WaitlistTicket) promote(meeting *Meeting) {
+	t.mu.Lock()
+	t.status = WaitlistPromoted
+	t.meeting = meeting
+	t.mu.Unlock()
+	t.notifyCh <- meeting
+}
+
+func (t *WaitlistTicket) expire() {
+	t.mu.Lock()
+	t.status = WaitlistExpired
+	t.mu.Unlock()
+	close(t.notifyCh)
+}
+
+// ==================== WaitlistPolicy ====================
+
+// WaitlistPolicy picks which waiting entry, among those already filtered
+// down to ones that fit a freed slot, should claim it.
+type WaitlistPolicy interface {
+	SelectNext(candidates []*WaitlistEntry) *WaitlistEntry
+}
+
+// FIFOWaitlistPolicy promotes whoever has been waiting longest.
+type FIFOWaitlistPolicy struct{}
+
+func NewFIFOWaitlistPolicy() *FIFOWaitlistPolicy { return &FIFOWaitlistPolicy{} }
+
+func (FIFOWaitlistPolicy) SelectNext(candidates []*WaitlistEntry) *WaitlistEntry {
+	if len(candidates) == 0 {
+		return nil
+	}
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.EnqueuedAt.Before(best.EnqueuedAt) {
+			best = c
+		}
+	}
+	return best
+}
+
+// PriorityWaitlistPolicy promotes the highest-Priority entry - e.g. set
+// from the host's role by the caller constructing the entry - breaking
+// ties FIFO.
+type PriorityWaitlistPolicy struct{}
+
+func NewPriorityWaitlistPolicy() *PriorityWaitlistPolicy { return &PriorityWaitlistPolicy{} }
+
+func (PriorityWaitlistPolicy) SelectNext(candidates []*WaitlistEntry) *WaitlistEntry {
+	if len(candidates) == 0 {
+		return nil
+	}
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.Priority > best.Priority ||
+			(c.Priority == best.Priority && c.EnqueuedAt.Before(best.EnqueuedAt)) {
+			best = c
+		}
+	}
+	return best
+}
+
+// ShortestDurationFirstWaitlistPolicy promotes whoever needs the room for
+// the least time, so the freed slot is more likely to also satisfy
+// whoever is still behind them in line.
+type ShortestDurationFirstWaitlistPolicy struct{}
+
+func NewShortestDurationFirstWaitlistPolicy() *ShortestDurationFirstWaitlistPolicy {
+	return &ShortestDurationFirstWaitlistPolicy{}
+}
+
+func (ShortestDurationFirstWaitlistPolicy) SelectNext(candidates []*WaitlistEntry) *WaitlistEntry {
+	if len(candidates) == 0 {
+		return nil
+	}
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.Duration < best.Duration {
+			best = c
+		}
+	}
+	return best
+}
+
+// ==================== Waitlist ====================
+
+// Waitlist holds every booking request that couldn't be scheduled
+// immediately, to be promoted later via MeetingSchedular.tryPromoteWaitlist.
+type Waitlist struct {
+	mu      sync.Mutex
+	entries []*WaitlistEntry
+	policy  WaitlistPolicy
+}
+
+func NewWaitlist(policy WaitlistPolicy) *Waitlist {
+	if policy == nil {
+		policy = NewFIFOWaitlistPolicy()
+	}
+	return &Waitlist{policy: policy}
+}
+
+func (w *Waitlist) enqueue(entry *WaitlistEntry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.entries = append(w.entries, entry)
+}
+
+// remove deletes entry from the waitlist, used once it's been promoted.
+// Expired entries are not removed: candidatesFor skips them, and their
+// tickets stay WaitlistPending.
+func (w *Waitlist) remove(entry *WaitlistEntry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, e := range w.entries {
+		if e == entry {
+			w.entries = append(w.entries[:i], w.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// candidatesFor returns every non-expired entry whose [Start,
+// Start+Duration) fits entirely inside freedSlot and whose
+// participantsCount is within capacity.
+func (w *Waitlist) candidatesFor(freedSlot *TimeSlot, capacity int, now time.Time) []*WaitlistEntry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var out []*WaitlistEntry
+	for _, e := range w.entries {
+		if e.expired(now) {
+			continue
+		}
+		if e.Start.Before(freedSlot.StartTime) || e.Start.Add(e.Duration).After(freedSlot.EndTime) {
+			continue
+		}
+		if e.participantsCount() > capacity {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
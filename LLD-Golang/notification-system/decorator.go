@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// =====================================================
+// Rate limiting, inbox persistence, and fakes as Service decorators
+// Modeled on the caerus notifications service: cross-cutting behavior
+// wraps whatever Service ServiceFactory.GetService (chunk8-1)
+// hands back, rather than being baked into each concrete provider.
+// =====================================================
+
+// AppNotificationsRateLimit enforces a per-(appID, userID) quota over a
+// sliding window, shared across every RateLimitedService that points at
+// it.
+type AppNotificationsRateLimit struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	sends  map[string][]time.Time
+}
+
+func NewAppNotificationsRateLimit(limit int, window time.Duration) *AppNotificationsRateLimit {
+	return &AppNotificationsRateLimit{limit: limit, window: window, sends: make(map[string][]time.Time)}
+}
+
+// Allow records a send attempt for (appID, userID) and reports whether it
+// fits within the quota, discarding any recorded sends that have already
+// fallen outside the window.
+func (r *AppNotificationsRateLimit) Allow(appID, userID string) bool {
+	key := appID + ":" + userID
+	now := time.Now()
+	cutoff := now.Add(-r.window)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.sends[key][:0]
+	for _, t := range r.sends[key] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) >= r.limit {
+		r.sends[key] = kept
+		return false
+	}
+	r.sends[key] = append(kept, now)
+	return true
+}
+
+// RateLimitedService rejects a Send that would exceed Limiter's quota for
+// (AppID, UserID), otherwise delegating straight through to Service.
+type RateLimitedService struct {
+	Service Service
+	Limiter *AppNotificationsRateLimit
+	AppID   string
+	UserID  string
+}
+
+func (s *RateLimitedService) Name() string { return s.Service.Name() }
+
+func (s *RateLimitedService) Send(ctx context.Context, subject, message string) error {
+	if !s.Limiter.Allow(s.AppID, s.UserID) {
+		return fmt.Errorf("rate limit: app %q user %q exceeded quota", s.AppID, s.UserID)
+	}
+	return s.Service.Send(ctx, subject, message)
+}
+
+// InboxMessage is one persisted record of an outgoing send.
+type InboxMessage struct {
+	AppID   string
+	UserID  string
+	Subject string
+	Message string
+	SentAt  time.Time
+}
+
+// InboxStore persists InboxMessages, pluggable so InboxService can back
+// onto either an in-memory store or a real database.
+type InboxStore interface {
+	Save(ctx context.Context, msg InboxMessage) error
+}
+
+// InMemoryInboxStore is the default InboxStore for the demo.
+type InMemoryInboxStore struct {
+	mu       sync.Mutex
+	messages []InboxMessage
+}
+
+func NewInMemoryInboxStore() *InMemoryInboxStore {
+	return &InMemoryInboxStore{}
+}
+
+func (s *InMemoryInboxStore) Save(ctx context.Context, msg InboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *InMemoryInboxStore) All() []InboxMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]InboxMessage, len(s.messages))
+	copy(out, s.messages)
+	return out
+}
+
+// SQLInboxStore persists InboxMessages to a SQL-backed inbox table. The
+// demo never opens a real *sql.DB, but the type is wired against the
+// standard database/sql interface so any driver can be plugged in without
+// InboxService changing.
+type SQLInboxStore struct {
+	DB *sql.DB
+}
+
+func NewSQLInboxStore(db *sql.DB) *SQLInboxStore {
+	return &SQLInboxStore{DB: db}
+}
+
+func (s *SQLInboxStore) Save(ctx context.Context, msg InboxMessage) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO inbox (app_id, user_id, subject, message, sent_at) VALUES (?, ?, ?, ?, ?)`,
+		msg.AppID, msg.UserID, msg.Subject, msg.Message, msg.SentAt)
+	if err != nil {
+		return fmt.Errorf("sql inbox store: save: %w", err)
+	}
+	return nil
+}
+
+// InboxService persists every outgoing message to Store after a
+// successful Send, so it never records a message that never went out.
+type InboxService struct {
+	Service Service
+	Store   InboxStore
+	AppID   string
+	UserID  string
+}
+
+func (s *InboxService) Name() string { return s.Service.Name() }
+
+func (s *InboxService) Send(ctx context.Context, subject, message string) error {
+	if err := s.Service.Send(ctx, subject, message); err != nil {
+		return err
+	}
+	return s.Store.Save(ctx, InboxMessage{
+		AppID:   s.AppID,
+		UserID:  s.UserID,
+		Subject: subject,
+		Message: message,
+		SentAt:  time.Now(),
+	})
+}
+
+// FakeService short-circuits delivery entirely, recording what would have
+// been sent instead of calling any real provider - for tests that want to
+// exercise the rest of a call path without actually dispatching.
+type FakeService struct {
+	Name_ string
+
+	mu   sync.Mutex
+	Sent []FakeSend
+}
+
+// FakeSend is one call FakeService recorded instead of delivering.
+type FakeSend struct {
+	Subject string
+	Message string
+}
+
+func (s *FakeService) Name() string { return s.Name_ }
+
+func (s *FakeService) Send(ctx context.Context, subject, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Sent = append(s.Sent, FakeSend{Subject: subject, Message: message})
+	return nil
+}
+
+// Options toggles the cross-cutting behavior BuildNotifier composes
+// around a Service, plus the (AppID, UserID) pair the decorators key on.
+type Options struct {
+	Fake      bool
+	InboxSave bool
+	AppID     string
+	UserID    string
+}
+
+// Dependencies supplies the shared state the decorators need: a
+// RateLimit is only applied if non-nil, and InboxSave only takes effect
+// if Inbox is also non-nil.
+type Dependencies struct {
+	RateLimit *AppNotificationsRateLimit
+	Inbox     InboxStore
+}
+
+// BuildNotifier resolves the Service registered under name via
+// ServiceFactory and wraps it with the decorator chain in a fixed
+// order - Fake, then RateLimit, then Inbox, then the real sender - so
+// behavior is predictable regardless of which Options are set. Fake
+// short-circuits before the chain is even built: a faked send is never
+// rate-limited or persisted to the inbox, since nothing real happened.
+//
+// Named BuildNotifier rather than NewNotifier to avoid colliding with
+// chunk8-1's NewNotifier(services ...Service) *Notifier, which builds an
+// unrelated type (the multi-channel fan-out Notifier, not a decorator
+// chain around a single Service).
+func BuildNotifier(name string, opts Options, deps Dependencies) (Service, error) {
+	factory := ServiceFactory{}
+	base, err := factory.GetService(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Fake {
+		return &FakeService{Name_: base.Name()}, nil
+	}
+
+	svc := base
+	if opts.InboxSave && deps.Inbox != nil {
+		svc = &InboxService{Service: svc, Store: deps.Inbox, AppID: opts.AppID, UserID: opts.UserID}
+	}
+	if deps.RateLimit != nil {
+		svc = &RateLimitedService{Service: svc, Limiter: deps.RateLimit, AppID: opts.AppID, UserID: opts.UserID}
+	}
+	return svc, nil
+}
@@ -73,12 +73,12 @@ type NotificationChannel interface {
 type SMSNotificationChannel struct{}
 
 func (s *SMSNotificationChannel) Send(notification *Notification)  { fmt.Println(notification) }
-func (s *SMSNotificationChannel) GetType() NotificationChannelType { return EMAIL }
+func (s *SMSNotificationChannel) GetType() NotificationChannelType { return SMS }
 
 type EmailNotificationChannel struct{}
 
 func (e *EmailNotificationChannel) Send(notification *Notification)  { fmt.Println(notification) }
-func (e *EmailNotificationChannel) GetType() NotificationChannelType { return SMS }
+func (e *EmailNotificationChannel) GetType() NotificationChannelType { return EMAIL }
 
 type NotificationChannelFactory struct {
 	NotificationChannels map[NotificationChannelType]NotificationChannel
@@ -138,7 +138,3 @@ func (s *Subscription) RemoveChannel(channel NotificationChannel) {
 		}
 	}
 }
-
-func (s *Subscription) Update(orderId string, event OrderEventType) {
-
-}
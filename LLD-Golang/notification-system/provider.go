@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// =====================================================
+// Provider registry + retry/circuit-breaker middleware
+// Redesigns chunk8-1's name -> Service lookup (kept as ServiceFactory)
+// around a (channel, vendor) -> Provider registry, so a caller can swap
+// e.g. sms:twilio for sms:nexmo without touching any call site.
+// =====================================================
+
+// Message is what a Provider actually transmits - the same shape as
+// Service's (subject, message string) pair, just named for this layer's
+// own vocabulary.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Provider is a single vendor integration for one channel (e.g.
+// "email"/"smtp" or "sms"/"twilio"). HealthCheck lets a circuit breaker
+// or operator probe for provider availability independently of sending.
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+	HealthCheck(ctx context.Context) error
+}
+
+var providerRegistry = struct {
+	mu        sync.Mutex
+	providers map[string]Provider
+}{providers: make(map[string]Provider)}
+
+func providerKey(channel, vendor string) string { return channel + ":" + vendor }
+
+// RegisterProvider makes p available to NotificationFactory under
+// (channel, vendor), e.g. RegisterProvider("email", "sendgrid", p).
+func RegisterProvider(channel, vendor string, p Provider) {
+	providerRegistry.mu.Lock()
+	defer providerRegistry.mu.Unlock()
+	providerRegistry.providers[providerKey(channel, vendor)] = p
+}
+
+// CircuitBreakerConfig configures CircuitBreaker: it opens after
+// FailureThreshold consecutive failures and allows one trial call again
+// once Cooldown has elapsed.
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker guards a single Provider from being hammered while it's
+// failing: once FailureThreshold consecutive failures have been recorded
+// it opens and rejects calls outright until Cooldown has passed, then
+// lets exactly one half-open trial call through to decide whether to
+// close again or re-open.
+type CircuitBreaker struct {
+	mu                  sync.Mutex
+	cfg                 CircuitBreakerConfig
+	state               circuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	halfOpenTrial       bool // true while a half-open trial call is in flight
+}
+
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{cfg: cfg}
+}
+
+// Allow reports whether a call should be attempted right now. While
+// circuitHalfOpen it admits only one in-flight trial call at a time -
+// every other concurrent caller is rejected until RecordResult clears
+// halfOpenTrial - so a recovering provider isn't hammered again before
+// the trial's outcome is known.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case circuitClosed:
+		return true
+	case circuitHalfOpen:
+		if cb.halfOpenTrial {
+			return false
+		}
+		cb.halfOpenTrial = true
+		return true
+	default: // circuitOpen
+		if time.Since(cb.openedAt) < cb.cfg.Cooldown {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		cb.halfOpenTrial = true
+		return true
+	}
+}
+
+// RecordResult updates the breaker's state after a call completes.
+func (cb *CircuitBreaker) RecordResult(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.halfOpenTrial = false
+	if err == nil {
+		cb.consecutiveFailures = 0
+		cb.state = circuitClosed
+		return
+	}
+	cb.consecutiveFailures++
+	if cb.state == circuitHalfOpen || cb.consecutiveFailures >= cb.cfg.FailureThreshold {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// ProviderConfig configures the middleware NotificationFactory wraps a
+// Provider with. Retry reuses RetryPolicy (pubsub.go's exponential
+// backoff with jitter) since its fields and delay() method are just data
+// about attempt spacing, not tied to NotificationChannel.
+type ProviderConfig struct {
+	Retry          RetryPolicy
+	CircuitBreaker CircuitBreakerConfig
+}
+
+// middlewareService adapts a Provider to the Service interface, retrying
+// per cfg.Retry. Its CircuitBreaker is consulted once before each Send
+// and updated once with the Send's final outcome, after all retries.
+type middlewareService struct {
+	provider Provider
+	retry    RetryPolicy
+	breaker  *CircuitBreaker
+}
+
+func (s *middlewareService) Name() string { return s.provider.Name() }
+
+func (s *middlewareService) Send(ctx context.Context, subject, message string) error {
+	if !s.breaker.Allow() {
+		return fmt.Errorf("provider %s: circuit open", s.provider.Name())
+	}
+
+	msg := Message{Subject: subject, Body: message}
+	var lastErr error
+	for attempt := 0; attempt < s.retry.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(s.retry.delay(attempt))
+		}
+		if lastErr = s.provider.Send(ctx, msg); lastErr == nil {
+			s.breaker.RecordResult(nil)
+			return nil
+		}
+	}
+	s.breaker.RecordResult(lastErr)
+	return fmt.Errorf("provider %s: exhausted %d attempts: %w", s.provider.Name(), s.retry.MaxAttempts, lastErr)
+}
+
+// NotificationFactory resolves the Provider registered for (channel,
+// vendor) and wraps it in retry + circuit-breaker middleware per cfg,
+// returning a Service. This reclaims the NotificationFactory name from
+// chunk8-1 (whose struct is now ServiceFactory) since this request's own
+// title frames it as a redesign of the same factory concept, just keyed
+// by (channel, vendor) instead of a bare name, and backed by Providers
+// instead of pre-configured Service instances.
+func NotificationFactory(channel, vendor string, cfg ProviderConfig) (Service, error) {
+	providerRegistry.mu.Lock()
+	provider, ok := providerRegistry.providers[providerKey(channel, vendor)]
+	providerRegistry.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("notification factory: no provider registered for %s:%s", channel, vendor)
+	}
+	return &middlewareService{provider: provider, retry: cfg.Retry, breaker: NewCircuitBreaker(cfg.CircuitBreaker)}, nil
+}
+
+// FailoverService tries primary, then each secondary in order, stopping
+// at the first successful Send.
+type FailoverService struct {
+	primary     Service
+	secondaries []Service
+}
+
+// NewFailoverNotification lets one channel cascade across vendors on
+// failure, e.g. primary email:sendgrid falling back to email:smtp.
+func NewFailoverNotification(primary Service, secondaries ...Service) Service {
+	return &FailoverService{primary: primary, secondaries: secondaries}
+}
+
+func (s *FailoverService) Name() string { return s.primary.Name() }
+
+func (s *FailoverService) Send(ctx context.Context, subject, message string) error {
+	chain := append([]Service{s.primary}, s.secondaries...)
+	var lastErr error
+	for _, svc := range chain {
+		if err := svc.Send(ctx, subject, message); err == nil {
+			return nil
+		} else {
+			lastErr = err
+		}
+	}
+	return fmt.Errorf("failover: all %d providers failed: %w", len(chain), lastErr)
+}
+
+// stubProvider simulates a vendor send and health check, the same way
+// EmailService/SMSService/etc. in notifier.go simulate delivery - none
+// of these vendors are actually reachable from this sandbox.
+type stubProvider struct {
+	name string
+}
+
+func newStubProvider(channel, vendor string) *stubProvider {
+	return &stubProvider{name: providerKey(channel, vendor)}
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) Send(ctx context.Context, msg Message) error {
+	fmt.Printf("[provider %s] subject=%q body=%q\n", p.name, msg.Subject, msg.Body)
+	return nil
+}
+
+func (p *stubProvider) HealthCheck(ctx context.Context) error { return nil }
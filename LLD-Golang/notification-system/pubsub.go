@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Topic is a generic publish/subscribe channel: publishers call Publish,
+// and every Subscribe'd handler sees each event unless its queue was full
+// at that moment (see Publish).
+// WHY: ExpenseManager-style callers shouldn't block on a slow subscriber, so
+// each subscriber gets its own bounded channel and worker goroutine instead
+// of being called synchronously from Publish.
+type Topic[T any] struct {
+	mu   sync.Mutex
+	subs map[*topicSub[T]]struct{}
+}
+
+type topicSub[T any] struct {
+	handler func(T)
+	queue   chan T
+	stop    chan struct{}
+}
+
+func NewTopic[T any]() *Topic[T] {
+	return &Topic[T]{subs: make(map[*topicSub[T]]struct{})}
+}
+
+// Subscribe registers handler to run on its own goroutine, fed by a bounded
+// queue of size `buffer`. Returns a token to pass to Unsubscribe.
+func (t *Topic[T]) Subscribe(handler func(T), buffer int) *topicSub[T] {
+	sub := &topicSub[T]{
+		handler: handler,
+		queue:   make(chan T, buffer),
+		stop:    make(chan struct{}),
+	}
+	go func() {
+		for {
+			select {
+			case evt := <-sub.queue:
+				sub.handler(evt)
+			case <-sub.stop:
+				return
+			}
+		}
+	}()
+
+	t.mu.Lock()
+	t.subs[sub] = struct{}{}
+	t.mu.Unlock()
+	return sub
+}
+
+func (t *Topic[T]) Unsubscribe(sub *topicSub[T]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.subs[sub]; ok {
+		close(sub.stop)
+		delete(t.subs, sub)
+	}
+}
+
+// Publish hands evt to every subscriber's queue without blocking on slow
+// consumers - a full queue drops the event for that subscriber rather than
+// stalling the publisher.
+func (t *Topic[T]) Publish(evt T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for sub := range t.subs {
+		select {
+		case sub.queue <- evt:
+		default:
+			// WHY: a wedged NotificationChannel.Send must never backpressure
+			// ExpenseManager.AddExpense; dropping here is the trade-off.
+		}
+	}
+}
+
+// ExpenseEventType mirrors OrderEventType's style for the expense-tracking
+// side of this demo's pub/sub wiring.
+type ExpenseEventType int
+
+const (
+	EXPENSE_CREATED ExpenseEventType = iota
+	EXPENSE_SETTLED
+)
+
+// ExpenseEvent is published whenever an expense is created or settled.
+// WHY: gives Subscription something concrete to fan out to
+// NotificationChannels, the same way OrderEventType drives Update today.
+type ExpenseEvent struct {
+	Type         ExpenseEventType
+	ExpenseID    string
+	PayerID      string
+	Participants []string
+}
+
+// ExpenseTopic is the process-wide bus Subscription listens on.
+// WHY: a package-level singleton keeps the demo wiring simple, mirroring
+// the singleton managers used elsewhere in this codebase's sibling modules.
+var ExpenseTopic = NewTopic[ExpenseEvent]()
+
+// RetryPolicy is exponential backoff with jitter around NotificationChannel.Send.
+// WHY: a flaky SMS/email provider shouldn't lose a notification on the
+// first transient failure, but also shouldn't retry forever.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay * time.Duration(1<<uint(attempt))
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(d) + 1))
+	return d/2 + jitter/2
+}
+
+// DeadLetter records a notification that exhausted its retries.
+// WHY: ops needs to see what never got delivered instead of it silently
+// vanishing after the last retry fails.
+type DeadLetter struct {
+	Notification *Notification
+	LastErr      error
+	Attempts     int
+}
+
+// DeadLetterQueue is a simple in-memory capture point for failed sends.
+// A production build would persist these the same way splitwise persists
+// domain events, but this demo keeps it in-process.
+type DeadLetterQueue struct {
+	mu    sync.Mutex
+	items []DeadLetter
+}
+
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{}
+}
+
+func (q *DeadLetterQueue) Capture(dl DeadLetter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, dl)
+}
+
+func (q *DeadLetterQueue) All() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]DeadLetter, len(q.items))
+	copy(out, q.items)
+	return out
+}
+
+// DefaultDeadLetterQueue is the sink RetrySend uses when the caller doesn't
+// supply its own queue.
+var DefaultDeadLetterQueue = NewDeadLetterQueue()
+
+// RetrySend sends notification through channel, retrying per policy, and
+// capturing it in dlq if every attempt fails.
+func RetrySend(channel NotificationChannel, notification *Notification, policy RetryPolicy, dlq *DeadLetterQueue) error {
+	var lastErr error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(policy.delay(attempt))
+		}
+		if err := trySend(channel, notification); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	dlq.Capture(DeadLetter{Notification: notification, LastErr: lastErr, Attempts: policy.MaxAttempts})
+	return fmt.Errorf("retrysend: exhausted %d attempts: %w", policy.MaxAttempts, lastErr)
+}
+
+// trySend wraps channel.Send, which this demo's channels never actually
+// fail - recover lets a future flaky channel implementation participate in
+// the same retry path without changing NotificationChannel's signature.
+func trySend(channel NotificationChannel, notification *Notification) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("channel send panicked: %v", r)
+		}
+	}()
+	channel.Send(notification)
+	return nil
+}
+
+// Update fans out an OrderEventType notification to every channel this
+// subscription is registered for.
+// WHY: this is the method ExpenseTopic subscribers call; it's also what a
+// direct caller uses for the order-event flow the OrderEventType constants
+// already model.
+func (s *Subscription) Update(orderId string, event OrderEventType) {
+	policy := DefaultRetryPolicy()
+	for _, channel := range s.Channels {
+		notification := NewNotification(orderId, s.Member.MemberID, fmt.Sprintf("order %s event %d", orderId, event), channel.GetType(), event)
+		if err := RetrySend(channel, notification, policy, DefaultDeadLetterQueue); err != nil {
+			fmt.Println("Update: notification dead-lettered:", err)
+		}
+	}
+}
+
+// HandleExpenseEvent maps an ExpenseEvent onto the existing OrderEventType
+// vocabulary and forwards it to Update.
+// WHY: ExpenseTopic is the publish side; this is the subscribe side that
+// an ExpenseManager-style caller's event ends up driving.
+func (s *Subscription) HandleExpenseEvent(evt ExpenseEvent) {
+	orderEvent := ORDERED
+	if evt.Type == EXPENSE_SETTLED {
+		orderEvent = DELIVERED
+	}
+	s.Update(evt.ExpenseID, orderEvent)
+}
+
+// PublishExpenseCreated is what an expense-tracking caller (e.g. an
+// ExpenseManager.AddExpense) invokes once a new expense is recorded.
+// WHY: keeps the publish call a one-liner so the caller doesn't need to
+// know Subscription even listens on ExpenseTopic.
+func PublishExpenseCreated(expenseID, payerID string, participants []string) {
+	ExpenseTopic.Publish(ExpenseEvent{
+		Type:         EXPENSE_CREATED,
+		ExpenseID:    expenseID,
+		PayerID:      payerID,
+		Participants: participants,
+	})
+}
+
+func main() {
+	member := NewMember(CUSTOMER, "animesh")
+	member.MemberID = "m1"
+
+	sub := NewSubscription("sub1", member, []NotificationChannel{
+		&EmailNotificationChannel{},
+		&SMSNotificationChannel{},
+	})
+
+	// Every Subscription on the expense topic fans out to its channels.
+	ExpenseTopic.Subscribe(sub.HandleExpenseEvent, 16)
+
+	PublishExpenseCreated("e1", member.MemberID, []string{member.MemberID})
+
+	time.Sleep(50 * time.Millisecond) // WHY: let the async subscriber drain before main exits
+	fmt.Println("dead letters:", len(DefaultDeadLetterQueue.All()))
+
+	// --- Multi-channel fan-out Notifier ---
+	email := NewEmailService("smtp.example.com", 587, "notify@example.com", "hunter2")
+	email.AddReceivers("customer@example.com")
+	sms := NewSMSService("ACxxxx", "authtoken", "+15550100")
+	sms.AddReceivers("+15550101")
+	RegisterService("email", email)
+	RegisterService("sms", sms)
+
+	factory := ServiceFactory{}
+	emailSvc, _ := factory.GetService("email")
+	smsSvc, _ := factory.GetService("sms")
+	notifier := NewNotifier(emailSvc, smsSvc)
+	if err := notifier.Send(context.Background(), "Order shipped", "Your order e1 has shipped"); err != nil {
+		fmt.Println("notifier: some channels failed:", err)
+	}
+
+	// --- Template-driven TemplatedNotification ---
+	templates := NewTemplateRegistry()
+	templates.Register(PurposeAuthenticationOTP, ChannelSMS,
+		template.Must(template.New("otp.sms").Parse("Your OTP is {{.DetailBody}}")))
+
+	otp := &TemplatedNotification{
+		UserID:     member.MemberID,
+		Title:      "Your OTP code",
+		DetailBody: "482913",
+		Purpose:    PurposeAuthenticationOTP,
+		NotifData:  SMSNotification{PhoneNumber: "+15550101"},
+	}
+	otpSender := NewTemplatedSender(ChannelSMS, smsSvc, templates)
+	if err := otpSender.Send(context.Background(), otp); err != nil {
+		fmt.Println("templated sender: send failed:", err)
+	}
+
+	// --- Decorated Notifier: rate-limited + inbox-persisting ---
+	deps := Dependencies{
+		RateLimit: NewAppNotificationsRateLimit(2, time.Minute),
+		Inbox:     NewInMemoryInboxStore(),
+	}
+	decorated, err := BuildNotifier("email", Options{InboxSave: true, AppID: "app1", UserID: member.MemberID}, deps)
+	if err != nil {
+		fmt.Println("build notifier:", err)
+	} else if err := decorated.Send(context.Background(), "Welcome", "Thanks for signing up"); err != nil {
+		fmt.Println("decorated notifier: send failed:", err)
+	}
+
+	// --- Provider registry + retry/circuit-breaker + failover ---
+	RegisterProvider("email", "sendgrid", newStubProvider("email", "sendgrid"))
+	RegisterProvider("email", "smtp", newStubProvider("email", "smtp"))
+
+	providerCfg := ProviderConfig{
+		Retry:          RetryPolicy{MaxAttempts: 2, BaseDelay: 50 * time.Millisecond, MaxDelay: 200 * time.Millisecond},
+		CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 3, Cooldown: 30 * time.Second},
+	}
+	primary, err := NotificationFactory("email", "sendgrid", providerCfg)
+	if err != nil {
+		fmt.Println("notification factory:", err)
+		return
+	}
+	fallback, err := NotificationFactory("email", "smtp", providerCfg)
+	if err != nil {
+		fmt.Println("notification factory:", err)
+		return
+	}
+	failover := NewFailoverNotification(primary, fallback)
+	if err := failover.Send(context.Background(), "Password reset", "Click here to reset your password"); err != nil {
+		fmt.Println("failover notification: send failed:", err)
+	}
+}
@@ -4,32 +4,31 @@ import (
 	"time"
 )
 
-const baseCharge = 100.00
-
 type ParkingTicket struct {
 	EntryTime   time.Time
 	ExitTime    time.Time
 	Vehicle     VehicleInterface
 	Spot        *ParkingSpot
+	Pricing     PricingStrategy
 	TotalCharge float64
 }
 
-func NewParkingTicket(vehicle VehicleInterface, spot *ParkingSpot) *ParkingTicket {
-	return &ParkingTicket{EntryTime: time.Now(), ExitTime: time.Time{}, Vehicle: vehicle, Spot: spot, TotalCharge: 0.00}
+func NewParkingTicket(vehicle VehicleInterface, spot *ParkingSpot, pricing PricingStrategy) *ParkingTicket {
+	return &ParkingTicket{EntryTime: time.Now(), ExitTime: time.Time{}, Vehicle: vehicle, Spot: spot, Pricing: pricing, TotalCharge: 0.00}
 }
 
 func (p *ParkingTicket) SetExitTime(exitTime time.Time) {
 	p.ExitTime = exitTime
 }
 
+// CalculateTotalCharge delegates to the ticket's PricingStrategy. If
+// the ticket hasn't been exited yet, it previews the charge as if the
+// vehicle left right now.
 func (p *ParkingTicket) CalculateTotalCharge() float64 {
-	if p.ExitTime == (time.Time{}) {
-		p.TotalCharge = baseCharge
-		return p.TotalCharge
+	exitTime := p.ExitTime
+	if exitTime == (time.Time{}) {
+		exitTime = time.Now()
 	}
-	duration := p.ExitTime.Sub(p.EntryTime)
-	hours := duration.Hours()
-	additionalCharge := hours * p.Vehicle.GetVehicleCost()
-	p.TotalCharge = baseCharge + additionalCharge
+	p.TotalCharge = p.Pricing.Charge(p.EntryTime, exitTime, p.Vehicle)
 	return p.TotalCharge
 }
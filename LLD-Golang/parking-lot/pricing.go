@@ -0,0 +1,174 @@
+package main
+
+import "time"
+
+// PricingStrategy decides how much a completed (or in-progress) stay
+// should be charged, given entry/exit time and the parked vehicle
+// (Strategy Pattern) - replaces ParkingTicket's old hardcoded
+// baseCharge + hours*vehicleCost formula.
+type PricingStrategy interface {
+	Charge(entry, exit time.Time, vehicle VehicleInterface) float64
+}
+
+// FlatHourly uses ParkingTicket's original formula: a fixed base charge
+// plus the vehicle's per-hour cost for the full duration. A stay no
+// longer than GracePeriod is charged 0.
+type FlatHourly struct {
+	BaseCharge  float64
+	GracePeriod time.Duration
+}
+
+func NewFlatHourly(baseCharge float64, gracePeriod time.Duration) *FlatHourly {
+	return &FlatHourly{BaseCharge: baseCharge, GracePeriod: gracePeriod}
+}
+
+func (f *FlatHourly) Charge(entry, exit time.Time, vehicle VehicleInterface) float64 {
+	duration := exit.Sub(entry)
+	if duration <= f.GracePeriod {
+		return 0
+	}
+	return f.BaseCharge + duration.Hours()*vehicle.GetVehicleCost()
+}
+
+// Slab is one rate band of a SlabPricing strategy: the hours of a stay
+// falling in [From, To) are charged at RatePerHour plus a one-time
+// FlatFee for entering the slab at all. To == 0 means "unbounded" - use
+// it on the last slab of a ladder to give it an open-ended top rate
+// (e.g. an overnight rate with no further ceiling).
+type Slab struct {
+	From, To    float64
+	RatePerHour float64
+	FlatFee     float64
+}
+
+// SlabPricing charges a stay across successive rate bands, e.g. first
+// hour flat, next three hours discounted, everything beyond that at an
+// overnight rate.
+type SlabPricing struct {
+	Slabs       []Slab
+	GracePeriod time.Duration
+}
+
+func NewSlabPricing(slabs []Slab, gracePeriod time.Duration) *SlabPricing {
+	return &SlabPricing{Slabs: slabs, GracePeriod: gracePeriod}
+}
+
+func (s *SlabPricing) Charge(entry, exit time.Time, vehicle VehicleInterface) float64 {
+	duration := exit.Sub(entry)
+	if duration <= s.GracePeriod {
+		return 0
+	}
+	hours := duration.Hours()
+	total := 0.0
+	for _, slab := range s.Slabs {
+		if hours <= slab.From {
+			break
+		}
+		upper := slab.To
+		if upper == 0 || upper > hours {
+			upper = hours
+		}
+		hoursInSlab := upper - slab.From
+		if hoursInSlab <= 0 {
+			continue
+		}
+		total += slab.FlatFee + hoursInSlab*slab.RatePerHour
+	}
+	return total
+}
+
+// HourRange is a [StartHour, EndHour) window in 24h clock time (e.g.
+// 18 to 22 for "6pm-10pm"). StartHour > EndHour is treated as wrapping
+// past midnight (e.g. 22 to 2).
+type HourRange struct {
+	StartHour, EndHour int
+	Multiplier         float64
+}
+
+func inHourRange(hour, start, end int) bool {
+	if start <= end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// TimeOfDaySurge wraps a base PricingStrategy and surcharges it for
+// whatever portion of the stay falls inside a configured hour range.
+// Rather than re-invoking Base per hour segment (which would re-apply
+// any fixed fee the base strategy charges once per stay), it charges
+// the base strategy exactly once for the whole stay and scales that by
+// the duration-weighted average of the matching multipliers, so a stay
+// that straddles a surge window's boundary is only surcharged for the
+// fraction of hours actually inside it.
+type TimeOfDaySurge struct {
+	Base   PricingStrategy
+	Ranges []HourRange
+}
+
+func NewTimeOfDaySurge(base PricingStrategy, ranges ...HourRange) *TimeOfDaySurge {
+	return &TimeOfDaySurge{Base: base, Ranges: ranges}
+}
+
+func (t *TimeOfDaySurge) Charge(entry, exit time.Time, vehicle VehicleInterface) float64 {
+	base := t.Base.Charge(entry, exit, vehicle)
+	return base * t.weightedMultiplier(entry, exit)
+}
+
+func (t *TimeOfDaySurge) weightedMultiplier(entry, exit time.Time) float64 {
+	totalHours := exit.Sub(entry).Hours()
+	if totalHours <= 0 {
+		return 1.0
+	}
+	weighted := 0.0
+	cursor := entry
+	for cursor.Before(exit) {
+		segmentEnd := cursor.Truncate(time.Hour).Add(time.Hour)
+		if segmentEnd.After(exit) {
+			segmentEnd = exit
+		}
+		weighted += segmentEnd.Sub(cursor).Hours() * t.multiplierAt(cursor)
+		cursor = segmentEnd
+	}
+	return weighted / totalHours
+}
+
+func (t *TimeOfDaySurge) multiplierAt(at time.Time) float64 {
+	hour := at.Hour()
+	for _, r := range t.Ranges {
+		if inHourRange(hour, r.StartHour, r.EndHour) {
+			return r.Multiplier
+		}
+	}
+	return 1.0
+}
+
+// DailyCap wraps a base PricingStrategy and caps the charge at
+// MaxPerDay for each calendar day the stay touches, re-applying the cap
+// fresh as the stay rolls into the next day.
+type DailyCap struct {
+	Base      PricingStrategy
+	MaxPerDay float64
+}
+
+func NewDailyCap(base PricingStrategy, maxPerDay float64) *DailyCap {
+	return &DailyCap{Base: base, MaxPerDay: maxPerDay}
+}
+
+func (d *DailyCap) Charge(entry, exit time.Time, vehicle VehicleInterface) float64 {
+	total := 0.0
+	cursor := entry
+	for cursor.Before(exit) {
+		midnight := time.Date(cursor.Year(), cursor.Month(), cursor.Day(), 0, 0, 0, 0, cursor.Location()).AddDate(0, 0, 1)
+		segmentEnd := midnight
+		if segmentEnd.After(exit) {
+			segmentEnd = exit
+		}
+		dayCharge := d.Base.Charge(cursor, segmentEnd, vehicle)
+		if dayCharge > d.MaxPerDay {
+			dayCharge = d.MaxPerDay
+		}
+		total += dayCharge
+		cursor = segmentEnd
+	}
+	return total
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var (
+	txBucket           = []byte("transactions")
+	requestIndexBucket = []byte("tx_by_request_id")
+	gatewayIndexBucket = []byte("tx_by_gateway_tx_id")
+)
+
+// BboltTxRepo is the production TransactionRepository, backed by a local
+// bbolt file so the control tower's lifecycle survives a restart -
+// unlike shopping-cart-deisgn/meeting-schedular (which went relational
+// via GORM), a payment ledger is natively a single-writer KV workload,
+// so bbolt is the better fit here.
+type BboltTxRepo struct {
+	db *bbolt.DB
+}
+
+func NewBboltTxRepo(db *bbolt.DB) (*BboltTxRepo, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		for _, b := range [][]byte{txBucket, requestIndexBucket, gatewayIndexBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BboltTxRepo{db: db}, nil
+}
+
+func (r *BboltTxRepo) Save(tx *Transaction) error {
+	tx.CreatedAt = time.Now()
+	tx.UpdatedAt = time.Now()
+	return r.db.Update(func(btx *bbolt.Tx) error {
+		data, err := json.Marshal(tx)
+		if err != nil {
+			return err
+		}
+		if err := btx.Bucket(txBucket).Put([]byte(tx.ID), data); err != nil {
+			return err
+		}
+		if tx.RequestID != "" {
+			if err := btx.Bucket(requestIndexBucket).Put([]byte(tx.RequestID), []byte(tx.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *BboltTxRepo) UpdateStatus(txID string, status TransactionStatus) error {
+	return r.db.Update(func(btx *bbolt.Tx) error {
+		b := btx.Bucket(txBucket)
+		data := b.Get([]byte(txID))
+		if data == nil {
+			return errors.New("transaction not found")
+		}
+		var t Transaction
+		if err := json.Unmarshal(data, &t); err != nil {
+			return err
+		}
+		t.Status = status
+		t.UpdatedAt = time.Now()
+		out, err := json.Marshal(&t)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(txID), out)
+	})
+}
+
+// RecordAttempt folds the GatewayUsed/GatewayTransactionID update and
+// the gatewayTxID -> txID reverse-index write into a single bbolt
+// transaction, matching InMemoryTxRepo.RecordAttempt's contract.
+func (r *BboltTxRepo) RecordAttempt(txID, gatewayName, gatewayTxID string) error {
+	return r.db.Update(func(btx *bbolt.Tx) error {
+		b := btx.Bucket(txBucket)
+		data := b.Get([]byte(txID))
+		if data == nil {
+			return errors.New("transaction not found")
+		}
+		var t Transaction
+		if err := json.Unmarshal(data, &t); err != nil {
+			return err
+		}
+		t.GatewayUsed = gatewayName
+		t.GatewayTransactionID = gatewayTxID
+		t.Status = Initiated
+		t.UpdatedAt = time.Now()
+		out, err := json.Marshal(&t)
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(txID), out); err != nil {
+			return err
+		}
+		return btx.Bucket(gatewayIndexBucket).Put([]byte(gatewayTxID), []byte(txID))
+	})
+}
+
+func (r *BboltTxRepo) GetByID(txID string) (*Transaction, error) {
+	var t Transaction
+	err := r.db.View(func(btx *bbolt.Tx) error {
+		data := btx.Bucket(txBucket).Get([]byte(txID))
+		if data == nil {
+			return errors.New("transaction not found")
+		}
+		return json.Unmarshal(data, &t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *BboltTxRepo) GetByRequestID(requestID string) (*Transaction, error) {
+	var txID []byte
+	r.db.View(func(btx *bbolt.Tx) error {
+		txID = btx.Bucket(requestIndexBucket).Get([]byte(requestID))
+		return nil
+	})
+	if txID == nil {
+		return nil, errors.New("transaction not found")
+	}
+	return r.GetByID(string(txID))
+}
+
+func (r *BboltTxRepo) GetByGatewayTxID(gatewayTxID string) (*Transaction, error) {
+	var txID []byte
+	r.db.View(func(btx *bbolt.Tx) error {
+		txID = btx.Bucket(gatewayIndexBucket).Get([]byte(gatewayTxID))
+		return nil
+	})
+	if txID == nil {
+		return nil, errors.New("transaction not found")
+	}
+	return r.GetByID(string(txID))
+}
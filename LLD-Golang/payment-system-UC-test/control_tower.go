@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	ErrPaymentInFlight   = errors.New("payment: attempt already in flight")
+	ErrPaymentTerminal   = errors.New("payment: already settled")
+	ErrAlreadyPaid       = errors.New("payment: already paid for this request")
+	ErrInvalidTransition = errors.New("payment: invalid state transition")
+)
+
+var terminalStatuses = map[TransactionStatus]bool{Success: true, Failed: true}
+
+var validTransitions = map[TransactionStatus]map[TransactionStatus]bool{
+	Pending:    {Initiated: true, Processing: true, Failed: true},
+	Initiated:  {Processing: true, Success: true, Failed: true},
+	Processing: {Success: true, Failed: true},
+}
+
+// ControlTower owns Transaction lifecycle transitions, modeled on lnd's
+// channeldb control tower: every status change is validated against a
+// strict state machine so a webhook retry can't flip a settled payment
+// back to in-flight, and Success/Failed are terminal and never
+// overwritten.
+type ControlTower struct {
+	mu   sync.Mutex
+	repo TransactionRepository
+}
+
+func NewControlTower(repo TransactionRepository) *ControlTower {
+	return &ControlTower{repo: repo}
+}
+
+// RegisterAttempt records that gatewayName/gatewayTxID now owns the
+// in-flight attempt for txID, transitioning Pending -> Initiated. It
+// fails with ErrPaymentInFlight if an attempt is already outstanding
+// and ErrPaymentTerminal if the payment already settled.
+func (ct *ControlTower) RegisterAttempt(txID, gatewayName, gatewayTxID string) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	tx, err := ct.repo.GetByID(txID)
+	if err != nil {
+		return err
+	}
+	if terminalStatuses[tx.Status] {
+		return ErrPaymentTerminal
+	}
+	if tx.Status != Pending {
+		return ErrPaymentInFlight
+	}
+	return ct.repo.RecordAttempt(txID, gatewayName, gatewayTxID)
+}
+
+// Transition validates and applies a status change, returning
+// ErrPaymentTerminal if tx already settled or ErrInvalidTransition for
+// any jump the state machine doesn't allow.
+func (ct *ControlTower) Transition(txID string, next TransactionStatus) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	tx, err := ct.repo.GetByID(txID)
+	if err != nil {
+		return err
+	}
+	if terminalStatuses[tx.Status] {
+		return ErrPaymentTerminal
+	}
+	if !validTransitions[tx.Status][next] {
+		return ErrInvalidTransition
+	}
+	return ct.repo.UpdateStatus(txID, next)
+}
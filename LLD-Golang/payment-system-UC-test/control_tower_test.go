@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func newTestControlTower() (*ControlTower, *InMemoryTxRepo, *Transaction) {
+	repo := NewInMemoryTxRepo()
+	ct := NewControlTower(repo)
+	tx := &Transaction{ID: "tx1", RequestID: "req1", Status: Pending}
+	repo.Save(tx)
+	return ct, repo, tx
+}
+
+func TestControlTowerRejectsInvalidTransition(t *testing.T) {
+	ct, _, tx := newTestControlTower()
+
+	// Pending can't jump straight to Success - only Initiated/Processing/Failed.
+	if err := ct.Transition(tx.ID, Success); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("Transition(Pending->Success) = %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestControlTowerRejectsAttemptOnceInFlight(t *testing.T) {
+	ct, _, tx := newTestControlTower()
+
+	if err := ct.RegisterAttempt(tx.ID, "payu", "gw1"); err != nil {
+		t.Fatalf("first RegisterAttempt: %v", err)
+	}
+	if err := ct.RegisterAttempt(tx.ID, "paytm", "gw2"); !errors.Is(err, ErrPaymentInFlight) {
+		t.Fatalf("second RegisterAttempt = %v, want ErrPaymentInFlight", err)
+	}
+}
+
+func TestControlTowerTerminalStatesAreNonReversible(t *testing.T) {
+	ct, repo, tx := newTestControlTower()
+
+	if err := ct.RegisterAttempt(tx.ID, "payu", "gw1"); err != nil {
+		t.Fatalf("RegisterAttempt: %v", err)
+	}
+	if err := ct.Transition(tx.ID, Processing); err != nil {
+		t.Fatalf("Transition(Initiated->Processing): %v", err)
+	}
+	if err := ct.Transition(tx.ID, Success); err != nil {
+		t.Fatalf("Transition(Processing->Success): %v", err)
+	}
+
+	// A late, out-of-order webhook replay trying to walk the payment
+	// back to Processing (or flip it to Failed) must never succeed.
+	if err := ct.Transition(tx.ID, Processing); !errors.Is(err, ErrPaymentTerminal) {
+		t.Fatalf("Transition(Success->Processing) = %v, want ErrPaymentTerminal", err)
+	}
+	if err := ct.Transition(tx.ID, Failed); !errors.Is(err, ErrPaymentTerminal) {
+		t.Fatalf("Transition(Success->Failed) = %v, want ErrPaymentTerminal", err)
+	}
+	if err := ct.RegisterAttempt(tx.ID, "razorpay", "gw3"); !errors.Is(err, ErrPaymentTerminal) {
+		t.Fatalf("RegisterAttempt on settled tx = %v, want ErrPaymentTerminal", err)
+	}
+
+	got, err := repo.GetByID(tx.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != Success {
+		t.Fatalf("final status = %v, want %v", got.Status, Success)
+	}
+}
+
+// TestControlTowerConcurrentTransitionsNeverOverwriteTerminal hammers
+// Transition(Success)/Transition(Failed) from many goroutines racing off
+// the same Processing transaction. ControlTower serializes every call
+// under its own mutex, so exactly one racer should observe the
+// non-terminal state and win; every other racer - regardless of which
+// target status it was trying to set - must be rejected with
+// ErrPaymentTerminal once the first winner lands.
+func TestControlTowerConcurrentTransitionsNeverOverwriteTerminal(t *testing.T) {
+	ct, repo, tx := newTestControlTower()
+	if err := ct.RegisterAttempt(tx.ID, "payu", "gw1"); err != nil {
+		t.Fatalf("RegisterAttempt: %v", err)
+	}
+	if err := ct.Transition(tx.ID, Processing); err != nil {
+		t.Fatalf("Transition(Initiated->Processing): %v", err)
+	}
+
+	const racers = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	successes := 0
+
+	for i := 0; i < racers; i++ {
+		target := Success
+		if i%2 == 0 {
+			target = Failed
+		}
+		wg.Add(1)
+		go func(target TransactionStatus) {
+			defer wg.Done()
+			if err := ct.Transition(tx.ID, target); err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrPaymentTerminal) {
+				t.Errorf("Transition(%v) = %v, want nil or ErrPaymentTerminal", target, err)
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Fatalf("got %d successful transitions to a terminal state, want exactly 1", successes)
+	}
+
+	got, err := repo.GetByID(tx.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !terminalStatuses[got.Status] {
+		t.Fatalf("final status %v is not terminal", got.Status)
+	}
+
+	// The settled state must still reject further transitions after the race.
+	if err := ct.Transition(tx.ID, Processing); !errors.Is(err, ErrPaymentTerminal) {
+		t.Fatalf("post-race Transition = %v, want ErrPaymentTerminal", err)
+	}
+}
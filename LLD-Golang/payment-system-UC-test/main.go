@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand/v2"
+	"net/http"
 	"sync"
 	"time"
 )
@@ -49,12 +50,16 @@ const (
 	Success    TransactionStatus = "SUCCESS"
 	Failed     TransactionStatus = "FAILED"
 	Processing TransactionStatus = "PROCESSING"
+	// Reversed marks a shard that had succeeded but whose parent MPP
+	// transaction ultimately failed, so its amount needs refunding.
+	Reversed TransactionStatus = "REVERSED"
 )
 
 // Core Domain Entities -----------------------------------------
 
 type Transaction struct {
 	ID                   string
+	RequestID            string // client-supplied idempotency key
 	UserType             UserType
 	Amount               float64
 	PaymentMethod        PaymentMethod
@@ -72,6 +77,10 @@ type Gateway interface {
 	GetStatus(gatewayTxID string) (*GatewayStatusResponse, error)
 	SupportedMethods() []PaymentMethod
 	Name() string
+	// VerifyWebhook checks rawBody against the signature carried in
+	// headers using this gateway's shared secret, so WebhookServer never
+	// has to know how a given gateway signs its callbacks.
+	VerifyWebhook(rawBody []byte, headers http.Header) error
 }
 
 // GatewayResponse from initiation
@@ -86,9 +95,26 @@ type GatewayStatusResponse struct {
 	Status      string `json:"status"` // SUCCESS, FAILED, etc.
 }
 
+// gatewayStatusToTransactionStatus maps a gateway's raw status string
+// (as returned by GetStatus and carried in webhook payloads) onto our
+// own TransactionStatus vocabulary. Anything not explicitly SUCCESS or
+// FAILED is treated as still in flight.
+func gatewayStatusToTransactionStatus(gatewayStatus string) TransactionStatus {
+	switch gatewayStatus {
+	case "SUCCESS":
+		return Success
+	case "FAILED":
+		return Failed
+	default:
+		return Processing
+	}
+}
+
 // Concrete Gateways ------------------------------------------------
 
-type PayU struct{}
+type PayU struct {
+	secret string
+}
 
 func (p *PayU) Initiate(tx *Transaction) (*GatewayResponse, error) {
 	if !contains(p.SupportedMethods(), tx.PaymentMethod) {
@@ -112,9 +138,15 @@ func (p *PayU) SupportedMethods() []PaymentMethod {
 
 func (p *PayU) Name() string { return "PayU" }
 
+func (p *PayU) VerifyWebhook(rawBody []byte, headers http.Header) error {
+	return verifyHMACSignature(rawBody, headers.Get("X-PayU-Signature"), p.secret)
+}
+
 // ------------------------------------------------
 
-type Paytm struct{}
+type Paytm struct {
+	secret string
+}
 
 func (pt *Paytm) Initiate(tx *Transaction) (*GatewayResponse, error) {
 	if !contains(pt.SupportedMethods(), tx.PaymentMethod) {
@@ -138,9 +170,15 @@ func (pt *Paytm) SupportedMethods() []PaymentMethod {
 
 func (pt *Paytm) Name() string { return "Paytm" }
 
+func (pt *Paytm) VerifyWebhook(rawBody []byte, headers http.Header) error {
+	return verifyHMACSignature(rawBody, headers.Get("X-Paytm-Signature"), pt.secret)
+}
+
 // ------------------------------------------------
 
-type RazorPay struct{}
+type RazorPay struct {
+	secret string
+}
 
 func (r *RazorPay) Initiate(tx *Transaction) (*GatewayResponse, error) {
 	if !contains(r.SupportedMethods(), tx.PaymentMethod) {
@@ -164,6 +202,10 @@ func (r *RazorPay) SupportedMethods() []PaymentMethod {
 
 func (r *RazorPay) Name() string { return "RazorPay" }
 
+func (r *RazorPay) VerifyWebhook(rawBody []byte, headers http.Header) error {
+	return verifyHMACSignature(rawBody, headers.Get("X-RazorPay-Signature"), r.secret)
+}
+
 // Helper: generateID generates a unique ID
 func generateID() string {
 	return fmt.Sprintf("%d", time.Now().UnixNano())
@@ -181,134 +223,133 @@ func contains(methods []PaymentMethod, method PaymentMethod) bool {
 
 // Gateway Factory (Factory Pattern) --------------------------------------------
 
-type GatewayFactory struct{}
+type GatewayFactory struct {
+	secrets map[string]string // gateway name -> HMAC webhook secret
+}
 
-func NewGatewayFactory() *GatewayFactory {
-	return &GatewayFactory{}
+func NewGatewayFactory(secrets map[string]string) *GatewayFactory {
+	return &GatewayFactory{secrets: secrets}
 }
 
 func (gf *GatewayFactory) CreateGateway(name string) Gateway {
 	switch name {
 	case "PayU":
-		return &PayU{}
+		return &PayU{secret: gf.secrets[name]}
 	case "Paytm":
-		return &Paytm{}
+		return &Paytm{secret: gf.secrets[name]}
 	case "RazorPay":
-		return &RazorPay{}
+		return &RazorPay{secret: gf.secrets[name]}
 	default:
 		return nil
 	}
 }
 
-// Metrics and Gateway Selection Logic --------------------------------------------
-
-type Metrics struct {
-	successRates map[string]map[PaymentMethod]float64 // gateway -> method -> rate
-	mu           sync.RWMutex
-	totalTxs     map[string]map[PaymentMethod]int // for updating rates
-	successTxs   map[string]map[PaymentMethod]int
-}
-
-func NewMetrics() *Metrics {
-	return &Metrics{
-		successRates: make(map[string]map[PaymentMethod]float64),
-		totalTxs:     make(map[string]map[PaymentMethod]int),
-		successTxs:   make(map[string]map[PaymentMethod]int),
-	}
-}
-
-func (m *Metrics) UpdateSuccess(gateway string, method PaymentMethod, isSuccess bool) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if m.totalTxs[gateway] == nil {
-		m.totalTxs[gateway] = make(map[PaymentMethod]int)
-		m.successTxs[gateway] = make(map[PaymentMethod]int)
-		m.successRates[gateway] = make(map[PaymentMethod]float64)
-	}
-
-	m.totalTxs[gateway][method]++
-	if isSuccess {
-		m.successTxs[gateway][method]++
-	}
-
-	total := m.totalTxs[gateway][method]
-	success := m.successTxs[gateway][method]
-	if total > 0 {
-		m.successRates[gateway][method] = float64(success) / float64(total)
-	} else {
-		m.successRates[gateway][method] = 0.5 // Default
-	}
-}
-
-func (m *Metrics) GetSuccessRate(gateway string, method PaymentMethod) float64 {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	if rates, ok := m.successRates[gateway]; ok {
-		if rate, ok := rates[method]; ok {
-			return rate
-		}
-	}
-	return 0.5 // Default
-}
+// Gateway Selection Logic --------------------------------------------
+// Success estimation is now MissionControl's job (mission_control.go) -
+// a plain lifetime success-rate Metrics type let an early 1/1 gateway
+// beat a 95/100 one forever and never forgot a stale failure.
 
 // router ----------------------------------------------
 
 type Router interface {
 	SelectGateway(method PaymentMethod) (Gateway, error)
+	// SelectGatewayExcluding is SelectGateway with a set of gateway names
+	// to skip - used when re-sharding an MPP payment's failed portion
+	// onto a different gateway. A nil exclude behaves like SelectGateway.
+	SelectGatewayExcluding(method PaymentMethod, exclude map[string]bool) (Gateway, error)
 }
 
 type DynamicRouter struct {
-	factory  *GatewayFactory
-	metrics  *Metrics
-	gateways []string // Available gateways
+	factory        *GatewayFactory
+	missionControl *MissionControl
+	gateways       []string // Available gateways
 }
 
-func NewDynamicRouter(factory *GatewayFactory, metrics *Metrics, gateways []string) *DynamicRouter {
+func NewDynamicRouter(factory *GatewayFactory, missionControl *MissionControl, gateways []string) *DynamicRouter {
 	return &DynamicRouter{
-		factory:  factory,
-		metrics:  metrics,
-		gateways: gateways,
+		factory:        factory,
+		missionControl: missionControl,
+		gateways:       gateways,
 	}
 }
 
+// SelectGateway picks the supporting gateway with the highest live
+// probability, breaking near-ties with a weighted-random pick so
+// traffic doesn't stampede whichever gateway happens to sort first.
 func (dr *DynamicRouter) SelectGateway(method PaymentMethod) (Gateway, error) {
-	bestGateway := ""
-	bestRate := -1.0
+	return dr.SelectGatewayExcluding(method, nil)
+}
 
+func (dr *DynamicRouter) SelectGatewayExcluding(method PaymentMethod, exclude map[string]bool) (Gateway, error) {
+	var candidates []gatewayCandidate
 	for _, gwName := range dr.gateways {
+		if exclude[gwName] {
+			continue
+		}
 		gw := dr.factory.CreateGateway(gwName)
 		if gw == nil || !contains(gw.SupportedMethods(), method) {
 			continue
 		}
-		rate := dr.metrics.GetSuccessRate(gw.Name(), method)
-		if rate > bestRate {
-			bestRate = rate
-			bestGateway = gw.Name()
+		candidates = append(candidates, gatewayCandidate{
+			name: gw.Name(),
+			prob: dr.missionControl.Probability(gw.Name(), method),
+		})
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("no suitable gateway found")
+	}
+
+	best := candidates[0].prob
+	for _, c := range candidates[1:] {
+		if c.prob > best {
+			best = c.prob
 		}
 	}
 
-	if bestGateway == "" {
-		return nil, errors.New("no suitable gateway found")
+	const tieEpsilon = 0.02
+	var tied []gatewayCandidate
+	for _, c := range candidates {
+		if best-c.prob <= tieEpsilon {
+			tied = append(tied, c)
+		}
 	}
 
-	return dr.factory.CreateGateway(bestGateway), nil
+	chosen := tied[0].name
+	if len(tied) > 1 {
+		chosen = weightedRandomPick(tied)
+	}
+	return dr.factory.CreateGateway(chosen), nil
 }
 
 // TransactionRepository (Repository Pattern for persistence)
+//
+// RecordAttempt exists (rather than having ControlTower mutate
+// GatewayUsed/GatewayTransactionID on a pointer it got from GetByID)
+// because BboltTxRepo's GetByID unmarshals a fresh copy on every call -
+// only an explicit repo method that writes back is guaranteed to
+// persist across both backends.
 type TransactionRepository interface {
 	Save(tx *Transaction) error
 	UpdateStatus(txID string, status TransactionStatus) error
+	RecordAttempt(txID, gatewayName, gatewayTxID string) error
 	GetByID(txID string) (*Transaction, error)
+	GetByRequestID(requestID string) (*Transaction, error)
+	GetByGatewayTxID(gatewayTxID string) (*Transaction, error)
 }
 
 type InMemoryTxRepo struct {
-	txs map[string]*Transaction
-	mu  sync.RWMutex
+	txs           map[string]*Transaction
+	byRequestID   map[string]string
+	byGatewayTxID map[string]string
+	mu            sync.RWMutex
 }
 
 func NewInMemoryTxRepo() *InMemoryTxRepo {
-	return &InMemoryTxRepo{txs: make(map[string]*Transaction)}
+	return &InMemoryTxRepo{
+		txs:           make(map[string]*Transaction),
+		byRequestID:   make(map[string]string),
+		byGatewayTxID: make(map[string]string),
+	}
 }
 
 func (r *InMemoryTxRepo) Save(tx *Transaction) error {
@@ -317,6 +358,9 @@ func (r *InMemoryTxRepo) Save(tx *Transaction) error {
 	tx.CreatedAt = time.Now()
 	tx.UpdatedAt = time.Now()
 	r.txs[tx.ID] = tx
+	if tx.RequestID != "" {
+		r.byRequestID[tx.RequestID] = tx.ID
+	}
 	return nil
 }
 
@@ -331,6 +375,21 @@ func (r *InMemoryTxRepo) UpdateStatus(txID string, status TransactionStatus) err
 	return errors.New("transaction not found")
 }
 
+func (r *InMemoryTxRepo) RecordAttempt(txID, gatewayName, gatewayTxID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx, ok := r.txs[txID]
+	if !ok {
+		return errors.New("transaction not found")
+	}
+	tx.GatewayUsed = gatewayName
+	tx.GatewayTransactionID = gatewayTxID
+	tx.Status = Initiated
+	tx.UpdatedAt = time.Now()
+	r.byGatewayTxID[gatewayTxID] = txID
+	return nil
+}
+
 func (r *InMemoryTxRepo) GetByID(txID string) (*Transaction, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -340,30 +399,70 @@ func (r *InMemoryTxRepo) GetByID(txID string) (*Transaction, error) {
 	return nil, errors.New("transaction not found")
 }
 
+func (r *InMemoryTxRepo) GetByRequestID(requestID string) (*Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	txID, ok := r.byRequestID[requestID]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+	return r.txs[txID], nil
+}
+
+func (r *InMemoryTxRepo) GetByGatewayTxID(gatewayTxID string) (*Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	txID, ok := r.byGatewayTxID[gatewayTxID]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+	return r.txs[txID], nil
+}
+
 // PaymentService (Facade/Orchestrator)
 type PaymentService struct {
-	repo    TransactionRepository
-	router  Router
-	metrics *Metrics
+	repo           TransactionRepository
+	shardRepo      ShardRepository
+	router         Router
+	missionControl *MissionControl
+	controlTower   *ControlTower
+	mppRetryBudget int // max re-shard rounds for a failed MPP portion
 }
 
-func NewPaymentService(repo TransactionRepository, router Router, metrics *Metrics) *PaymentService {
+func NewPaymentService(repo TransactionRepository, shardRepo ShardRepository, router Router, missionControl *MissionControl, controlTower *ControlTower, mppRetryBudget int) *PaymentService {
 	return &PaymentService{
-		repo:    repo,
-		router:  router,
-		metrics: metrics,
+		repo:           repo,
+		shardRepo:      shardRepo,
+		router:         router,
+		missionControl: missionControl,
+		controlTower:   controlTower,
+		mppRetryBudget: mppRetryBudget,
 	}
 }
 
-// InitiateTransaction
-func (ps *PaymentService) InitiateTransaction(userType UserType, amount float64, method PaymentMethod) (*Transaction, error) {
+// InitiateTransaction. requestID is a client-supplied idempotency key:
+// a duplicate call (e.g. a webhook-triggered retry) returns the
+// existing Transaction instead of creating a new one, and ErrAlreadyPaid
+// if that existing transaction already succeeded.
+func (ps *PaymentService) InitiateTransaction(userType UserType, amount float64, method PaymentMethod, requestID string) (*Transaction, error) {
+	if existing, err := ps.repo.GetByRequestID(requestID); err == nil {
+		if existing.Status == Success {
+			return existing, ErrAlreadyPaid
+		}
+		return existing, nil
+	}
+
 	tx := &Transaction{
 		ID:            generateID(),
+		RequestID:     requestID,
 		UserType:      userType,
 		Amount:        amount,
 		PaymentMethod: method,
 		Status:        Pending,
 	}
+	if err := ps.repo.Save(tx); err != nil {
+		return nil, err
+	}
 
 	gw, err := ps.router.SelectGateway(method)
 	if err != nil {
@@ -375,11 +474,7 @@ func (ps *PaymentService) InitiateTransaction(userType UserType, amount float64,
 		return nil, err
 	}
 
-	tx.GatewayUsed = gw.Name()
-	tx.GatewayTransactionID = resp.GatewayTxID
-	tx.Status = Initiated
-
-	if err := ps.repo.Save(tx); err != nil {
+	if err := ps.RegisterAttempt(tx.ID, gw.Name(), resp.GatewayTxID); err != nil {
 		return nil, err
 	}
 
@@ -389,31 +484,29 @@ func (ps *PaymentService) InitiateTransaction(userType UserType, amount float64,
 	return tx, nil
 }
 
-// UpdateStatus (Webhook or Poll handler)
-func (ps *PaymentService) UpdateStatus(gatewayTxID string, gatewayStatus string) error {
-	// In real: Find tx by gatewayTxID (assume we have a reverse index)
-	// For LLD, assume we pass txID or query repo
-	// Mock: Assume we have txID from context
+// RegisterAttempt delegates to the ControlTower, which is the actual
+// lifecycle authority; it's exposed here too since callers talk to
+// PaymentService, not ControlTower, directly.
+func (ps *PaymentService) RegisterAttempt(txID, gatewayName, gatewayTxID string) error {
+	return ps.controlTower.RegisterAttempt(txID, gatewayName, gatewayTxID)
+}
 
-	var status TransactionStatus
-	switch gatewayStatus {
-	case "SUCCESS":
-		status = Success
-	case "FAILED":
-		status = Failed
-	default:
-		status = Processing
+// UpdateStatus (Webhook or Poll handler) resolves gatewayTxID to its
+// transaction via the repo's reverse index, then routes the new status
+// through ControlTower so a webhook can never move a settled payment.
+func (ps *PaymentService) UpdateStatus(gatewayTxID string, gatewayStatus string) error {
+	tx, err := ps.repo.GetByGatewayTxID(gatewayTxID)
+	if err != nil {
+		return err
 	}
 
-	// Assume txID is derived or passed; in real, use a map or DB query
-	// For demo, skip full impl
-	if err := ps.repo.UpdateStatus("dummy_tx_id", status); err != nil { // Replace with actual
+	status := gatewayStatusToTransactionStatus(gatewayStatus)
+
+	if err := ps.controlTower.Transition(tx.ID, status); err != nil {
 		return err
 	}
 
-	// Update metrics (need gateway name and method; assume derived)
-	// ps.metrics.UpdateSuccess("RazorPay", Card, status == Success)
-
+	ps.missionControl.RecordResult(tx.GatewayUsed, tx.PaymentMethod, status == Success, time.Now())
 	return nil
 }
 
@@ -422,24 +515,18 @@ func (ps *PaymentService) pollStatus(tx *Transaction) {
 	gw := ps.router.(*DynamicRouter).factory.CreateGateway(tx.GatewayUsed)
 	for i := 0; i < 12; i++ { // 1 min
 		time.Sleep(5 * time.Second)
-		if tx.Status != Pending && tx.Status != Initiated {
-			return
-		}
 		resp, err := gw.GetStatus(tx.GatewayTransactionID)
 		if err != nil {
 			continue
 		}
-		var newStatus TransactionStatus
-		switch resp.Status {
-		case "SUCCESS":
-			newStatus = Success
-		case "FAILED":
-			newStatus = Failed
-		default:
-			newStatus = Processing
+		newStatus := gatewayStatusToTransactionStatus(resp.Status)
+		if err := ps.controlTower.Transition(tx.ID, newStatus); err != nil {
+			if errors.Is(err, ErrPaymentTerminal) {
+				return // already settled by a webhook - nothing left to do
+			}
+			continue
 		}
-		ps.repo.UpdateStatus(tx.ID, newStatus)
-		ps.metrics.UpdateSuccess(tx.GatewayUsed, tx.PaymentMethod, newStatus == Success)
+		ps.missionControl.RecordResult(tx.GatewayUsed, tx.PaymentMethod, newStatus == Success, time.Now())
 		if newStatus == Success || newStatus == Failed {
 			return
 		}
@@ -448,14 +535,21 @@ func (ps *PaymentService) pollStatus(tx *Transaction) {
 
 // Example Usage (main for demo)
 func main() {
-	factory := NewGatewayFactory()
-	metrics := NewMetrics()
+	secrets := map[string]string{"PayU": "payu-secret", "Paytm": "paytm-secret", "RazorPay": "razorpay-secret"}
+	factory := NewGatewayFactory(secrets)
+	missionControl := NewMissionControl(10*time.Minute, 2*time.Minute, 0.5, 0.1)
 	repo := NewInMemoryTxRepo()
-	router := NewDynamicRouter(factory, metrics, []string{"PayU", "Paytm", "RazorPay"})
-	service := NewPaymentService(repo, router, metrics)
+	shardRepo := NewInMemoryShardRepo()
+	controlTower := NewControlTower(repo)
+	router := NewDynamicRouter(factory, missionControl, []string{"PayU", "Paytm", "RazorPay"})
+	service := NewPaymentService(repo, shardRepo, router, missionControl, controlTower, 2)
+
+	notifier := &MockNotificationService{}
+	webhookServer := NewWebhookServer(factory, repo, service, notifier, 5*time.Minute)
+	go http.ListenAndServe(":8081", webhookServer.Handler())
 
 	// Simulate init
-	tx, err := service.InitiateTransaction(Customer, 100.0, Card)
+	tx, err := service.InitiateTransaction(Customer, 100.0, Card, "req-1")
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
@@ -468,4 +562,12 @@ func main() {
 	// Get updated
 	updatedTx, _ := repo.GetByID(tx.ID)
 	fmt.Printf("Updated: %+v\n", updatedTx)
+
+	// Simulate a multi-part payment split across up to 3 shards/gateways
+	mppTx, err := service.InitiateMPPTransaction(Customer, 250.0, Card, 3)
+	if err != nil {
+		fmt.Printf("MPP error: %v\n", err)
+		return
+	}
+	fmt.Printf("MPP initiated: %+v\n", mppTx)
 }
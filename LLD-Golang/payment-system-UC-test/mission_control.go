@@ -0,0 +1,152 @@
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// attemptResult is one recorded outcome for a (gateway, PaymentMethod)
+// pair, newest appended last.
+type attemptResult struct {
+	at      time.Time
+	success bool
+}
+
+// methodStats tracks the rolling attempt history and last-penalty time
+// for a single (gateway, PaymentMethod) pair.
+type methodStats struct {
+	attempts    []attemptResult
+	lastPenalty time.Time
+}
+
+// MissionControl estimates a live success probability per (gateway,
+// PaymentMethod) pair, modeled on lnd's routing/mission control: rather
+// than a plain lifetime success rate (where an early 1/1 gateway beats a
+// 95/100 one forever), the observed rate over a bounded recent history
+// decays back toward a configurable apriori as it goes stale, and a
+// fresh failure floors the pair's probability for a cooldown window
+// instead of permanently blacklisting it.
+type MissionControl struct {
+	mu         sync.RWMutex
+	stats      map[string]map[PaymentMethod]*methodStats
+	halfLife   time.Duration
+	apriori    float64
+	cooldown   time.Duration
+	floor      float64
+	maxHistory int
+	now        func() time.Time
+}
+
+// NewMissionControl builds a MissionControl. halfLife controls how fast
+// a stale observed rate decays back toward apriori; cooldown is how long
+// a fresh failure floors the pair's probability at floor.
+func NewMissionControl(halfLife, cooldown time.Duration, apriori, floor float64) *MissionControl {
+	return &MissionControl{
+		stats:      make(map[string]map[PaymentMethod]*methodStats),
+		halfLife:   halfLife,
+		apriori:    apriori,
+		cooldown:   cooldown,
+		floor:      floor,
+		maxHistory: 50,
+		now:        time.Now,
+	}
+}
+
+func (mc *MissionControl) statsFor(gateway string, method PaymentMethod) *methodStats {
+	byMethod, ok := mc.stats[gateway]
+	if !ok {
+		byMethod = make(map[PaymentMethod]*methodStats)
+		mc.stats[gateway] = byMethod
+	}
+	s, ok := byMethod[method]
+	if !ok {
+		s = &methodStats{}
+		byMethod[method] = s
+	}
+	return s
+}
+
+// RecordResult records one attempt's outcome for (gateway, method) at
+// the given time, capping the retained history at maxHistory entries.
+func (mc *MissionControl) RecordResult(gateway string, method PaymentMethod, success bool, at time.Time) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	s := mc.statsFor(gateway, method)
+	s.attempts = append(s.attempts, attemptResult{at: at, success: success})
+	if len(s.attempts) > mc.maxHistory {
+		s.attempts = s.attempts[len(s.attempts)-mc.maxHistory:]
+	}
+	if !success {
+		s.lastPenalty = at
+	}
+}
+
+// Probability returns the live success probability for (gateway,
+// method): p = a*priorSuccessRate + (1-a)*apriori, where
+// priorSuccessRate is the plain success ratio over the retained history
+// and a = exp(-Δt/halfLife) decays that ratio back toward apriori as Δt
+// (time since the most recent attempt) grows. A failure within the last
+// cooldown window floors the result instead, regardless of the ratio.
+func (mc *MissionControl) Probability(gateway string, method PaymentMethod) float64 {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	byMethod, ok := mc.stats[gateway]
+	if !ok {
+		return mc.apriori
+	}
+	s, ok := byMethod[method]
+	if !ok || len(s.attempts) == 0 {
+		return mc.apriori
+	}
+
+	now := mc.now()
+	if !s.lastPenalty.IsZero() && now.Sub(s.lastPenalty) < mc.cooldown {
+		return mc.floor
+	}
+
+	successes := 0
+	for _, a := range s.attempts {
+		if a.success {
+			successes++
+		}
+	}
+	priorSuccessRate := float64(successes) / float64(len(s.attempts))
+
+	lastAttempt := s.attempts[len(s.attempts)-1].at
+	dt := now.Sub(lastAttempt)
+	a := math.Exp(-dt.Seconds() / mc.halfLife.Seconds())
+
+	return a*priorSuccessRate + (1-a)*mc.apriori
+}
+
+// gatewayCandidate pairs a gateway name with its estimated probability,
+// used by DynamicRouter.SelectGateway to break near-ties.
+type gatewayCandidate struct {
+	name string
+	prob float64
+}
+
+// weightedRandomPick picks among tied candidates with probability
+// proportional to their estimated success rate, so near-equal gateways
+// share traffic instead of one winning every tie forever.
+func weightedRandomPick(tied []gatewayCandidate) string {
+	total := 0.0
+	for _, c := range tied {
+		total += c.prob
+	}
+	if total <= 0 {
+		return tied[rand.IntN(len(tied))].name
+	}
+	r := rand.Float64() * total
+	for _, c := range tied {
+		r -= c.prob
+		if r <= 0 {
+			return c.name
+		}
+	}
+	return tied[len(tied)-1].name
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Shard is one slice of a multi-part payment (MPP): a parent
+// Transaction split across up to maxShards independently-routed
+// attempts, each with its own gateway and lifecycle, modeled on lnd's
+// multi-shard payment state machine.
+type Shard struct {
+	ID                   string
+	TransactionID        string
+	Amount               float64
+	PaymentMethod        PaymentMethod
+	GatewayUsed          string
+	GatewayTransactionID string
+	Status               TransactionStatus
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// ShardRepository (Repository Pattern, mirrors TransactionRepository)
+type ShardRepository interface {
+	Save(s *Shard) error
+	UpdateStatus(shardID string, status TransactionStatus) error
+	GetByID(shardID string) (*Shard, error)
+	GetByTransactionID(txID string) ([]*Shard, error)
+}
+
+type InMemoryShardRepo struct {
+	byID          map[string]*Shard
+	byTransaction map[string][]*Shard
+	mu            sync.RWMutex
+}
+
+func NewInMemoryShardRepo() *InMemoryShardRepo {
+	return &InMemoryShardRepo{
+		byID:          make(map[string]*Shard),
+		byTransaction: make(map[string][]*Shard),
+	}
+}
+
+func (r *InMemoryShardRepo) Save(s *Shard) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = now
+	}
+	s.UpdatedAt = now
+	r.byID[s.ID] = s
+	r.byTransaction[s.TransactionID] = append(r.byTransaction[s.TransactionID], s)
+	return nil
+}
+
+func (r *InMemoryShardRepo) UpdateStatus(shardID string, status TransactionStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.byID[shardID]
+	if !ok {
+		return errors.New("shard not found")
+	}
+	s.Status = status
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *InMemoryShardRepo) GetByID(shardID string) (*Shard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.byID[shardID]
+	if !ok {
+		return nil, errors.New("shard not found")
+	}
+	return s, nil
+}
+
+func (r *InMemoryShardRepo) GetByTransactionID(txID string) ([]*Shard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*Shard(nil), r.byTransaction[txID]...), nil
+}
+
+// InitiateMPPTransaction splits amount into up to maxShards shards and
+// routes each independently through Router.SelectGateway, so different
+// shards can land on different gateways. It returns once every shard has
+// been initiated; final aggregation (success, resharding, reversal)
+// happens in the background the same way InitiateTransaction's
+// pollStatus does.
+func (ps *PaymentService) InitiateMPPTransaction(userType UserType, amount float64, method PaymentMethod, maxShards int) (*Transaction, error) {
+	if maxShards < 1 {
+		maxShards = 1
+	}
+	tx := &Transaction{
+		ID:            generateID(),
+		UserType:      userType,
+		Amount:        amount,
+		PaymentMethod: method,
+		Status:        Pending,
+	}
+	if err := ps.repo.Save(tx); err != nil {
+		return nil, err
+	}
+
+	shards := make([]*Shard, 0, maxShards)
+	for _, shardAmount := range splitAmount(amount, maxShards) {
+		shards = append(shards, ps.initiateOneShard(tx, shardAmount, method, nil))
+	}
+
+	go ps.resolveMPP(tx, shards, method)
+
+	return tx, nil
+}
+
+// splitAmount divides amount into n shards of roughly equal size, with
+// the last shard absorbing any rounding remainder.
+func splitAmount(amount float64, n int) []float64 {
+	if n < 1 {
+		n = 1
+	}
+	base := roundCents(amount / float64(n))
+	amounts := make([]float64, n)
+	running := 0.0
+	for i := 0; i < n-1; i++ {
+		amounts[i] = base
+		running += base
+	}
+	amounts[n-1] = roundCents(amount - running)
+	return amounts
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// initiateOneShard creates and initiates a single shard, routing away
+// from any gateway name present in exclude - used both for a shard's
+// first attempt (exclude is nil) and for re-sharding a failed portion
+// onto a different gateway.
+func (ps *PaymentService) initiateOneShard(tx *Transaction, amount float64, method PaymentMethod, exclude map[string]bool) *Shard {
+	shard := &Shard{
+		ID:            generateID(),
+		TransactionID: tx.ID,
+		Amount:        amount,
+		PaymentMethod: method,
+		Status:        Pending,
+	}
+
+	gw, err := ps.router.SelectGatewayExcluding(method, exclude)
+	if err != nil {
+		shard.Status = Failed
+		ps.shardRepo.Save(shard)
+		return shard
+	}
+
+	shardTx := &Transaction{ID: shard.ID, Amount: amount, PaymentMethod: method, Status: Pending}
+	resp, err := gw.Initiate(shardTx)
+	if err != nil {
+		shard.Status = Failed
+		ps.shardRepo.Save(shard)
+		return shard
+	}
+
+	shard.GatewayUsed = gw.Name()
+	shard.GatewayTransactionID = resp.GatewayTxID
+	shard.Status = Initiated
+	ps.shardRepo.Save(shard)
+	return shard
+}
+
+// resolveMPP waits for every shard's polling to finish (see awaitShards),
+// then aggregates, counting any shard not in Success as failed: the
+// parent succeeds only once its successful shards sum to
+// the full requested amount. On partial failure it re-shards the failed
+// portion onto a different gateway, up to ps.mppRetryBudget rounds; once
+// that budget is exhausted it marks the parent Failed and reverses every
+// shard that had succeeded so callers can trigger refunds.
+func (ps *PaymentService) resolveMPP(tx *Transaction, shards []*Shard, method PaymentMethod) {
+	// Pending -> Processing directly: an MPP parent has no single gateway
+	// attempt of its own, so it never goes through RegisterAttempt/Initiated.
+	ps.controlTower.Transition(tx.ID, Processing)
+
+	for attempt := 0; ; attempt++ {
+		ps.awaitShards(shards)
+
+		succeeded := 0.0
+		var failed []*Shard
+		exclude := make(map[string]bool)
+		for _, s := range shards {
+			if s.Status == Success {
+				succeeded += s.Amount
+			} else {
+				failed = append(failed, s)
+			}
+			if s.GatewayUsed != "" {
+				exclude[s.GatewayUsed] = true
+			}
+		}
+
+		if succeeded >= tx.Amount-0.005 {
+			ps.controlTower.Transition(tx.ID, Success)
+			return
+		}
+
+		if len(failed) == 0 || attempt >= ps.mppRetryBudget {
+			break
+		}
+
+		reshard := make([]*Shard, 0, len(failed))
+		for _, s := range failed {
+			reshard = append(reshard, ps.initiateOneShard(tx, s.Amount, method, exclude))
+		}
+		shards = reshard
+	}
+
+	ps.controlTower.Transition(tx.ID, Failed)
+	ps.reverseSucceededShards(tx)
+}
+
+// awaitShards blocks until every shard has a terminal status or pollShard
+// has given up on it after its 12 polls, polling each shard's gateway
+// independently and concurrently.
+func (ps *PaymentService) awaitShards(shards []*Shard) {
+	var wg sync.WaitGroup
+	for _, s := range shards {
+		if s.Status == Success || s.Status == Failed {
+			continue // couldn't even be initiated, or already resolved
+		}
+		wg.Add(1)
+		go func(s *Shard) {
+			defer wg.Done()
+			ps.pollShard(s)
+		}(s)
+	}
+	wg.Wait()
+}
+
+// pollShard mirrors PaymentService.pollStatus for a single Shard instead
+// of a parent Transaction, feeding MissionControl so shard outcomes
+// improve future routing the same way whole-transaction ones do.
+func (ps *PaymentService) pollShard(s *Shard) {
+	gw := ps.router.(*DynamicRouter).factory.CreateGateway(s.GatewayUsed)
+	if gw == nil {
+		ps.shardRepo.UpdateStatus(s.ID, Failed)
+		s.Status = Failed
+		return
+	}
+	for i := 0; i < 12; i++ { // 1 min, matching pollStatus
+		time.Sleep(5 * time.Second)
+		resp, err := gw.GetStatus(s.GatewayTransactionID)
+		if err != nil {
+			continue
+		}
+		newStatus := gatewayStatusToTransactionStatus(resp.Status)
+		ps.shardRepo.UpdateStatus(s.ID, newStatus)
+		s.Status = newStatus
+		ps.missionControl.RecordResult(s.GatewayUsed, s.PaymentMethod, newStatus == Success, time.Now())
+		if newStatus == Success || newStatus == Failed {
+			return
+		}
+	}
+}
+
+// reverseSucceededShards issues reverse UpdateStatus calls for shards
+// that had succeeded before the parent MPP transaction was ultimately
+// failed, so callers can trigger refunds for exactly those shards.
+func (ps *PaymentService) reverseSucceededShards(tx *Transaction) {
+	shards, err := ps.shardRepo.GetByTransactionID(tx.ID)
+	if err != nil {
+		return
+	}
+	for _, s := range shards {
+		if s.Status == Success {
+			ps.shardRepo.UpdateStatus(s.ID, Reversed)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NotificationService is the downstream sink for transaction status
+// changes, mirroring swiggy-cart-design's NotificationService
+// interface so status-change notifications follow the same shape
+// across the LLD-Golang projects.
+type NotificationService interface {
+	SendNotification(message string) error
+}
+
+// MockNotificationService is a demo implementation that just prints.
+type MockNotificationService struct{}
+
+func (m *MockNotificationService) SendNotification(message string) error {
+	fmt.Printf("[Notification] %s\n", message)
+	return nil
+}
+
+// WebhookEvent is the payload shape every gateway's callback is parsed
+// into once its signature has been verified.
+type WebhookEvent struct {
+	GatewayTxID string    `json:"gateway_tx_id"`
+	Status      string    `json:"status"`
+	EventID     string    `json:"event_id"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+func verifyHMACSignature(rawBody []byte, signatureHex, secret string) error {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(rawBody)
+	expected := mac.Sum(nil)
+	sig, err := hex.DecodeString(signatureHex)
+	if err != nil || !hmac.Equal(sig, expected) {
+		return errors.New("webhook: signature mismatch")
+	}
+	return nil
+}
+
+// WebhookServer exposes one HTTP handler per payment gateway. Each
+// request is signature-verified, checked against a replay window,
+// deduplicated by (gateway, gatewayTxID, eventID), and - following the
+// "publish only if changed" pattern from the Formance payments
+// ingester - only forwarded to PaymentService.UpdateStatus and
+// NotificationService when the status actually differs from what's
+// already persisted, so a replayed or out-of-order webhook is a cheap
+// no-op rather than a duplicate notification.
+type WebhookServer struct {
+	factory      *GatewayFactory
+	repo         TransactionRepository
+	service      *PaymentService
+	notifier     NotificationService
+	replayWindow time.Duration
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func NewWebhookServer(factory *GatewayFactory, repo TransactionRepository, service *PaymentService, notifier NotificationService, replayWindow time.Duration) *WebhookServer {
+	return &WebhookServer{
+		factory:      factory,
+		repo:         repo,
+		service:      service,
+		notifier:     notifier,
+		replayWindow: replayWindow,
+		seen:         make(map[string]bool),
+	}
+}
+
+// Handler returns the mux routing /webhook/payu, /webhook/paytm and
+// /webhook/razorpay to their respective gateway handlers.
+func (ws *WebhookServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook/payu", ws.handleFor("PayU"))
+	mux.HandleFunc("/webhook/paytm", ws.handleFor("Paytm"))
+	mux.HandleFunc("/webhook/razorpay", ws.handleFor("RazorPay"))
+	return mux
+}
+
+func (ws *WebhookServer) handleFor(gatewayName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		gw := ws.factory.CreateGateway(gatewayName)
+		if gw == nil {
+			http.Error(w, "unknown gateway", http.StatusInternalServerError)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "cannot read body", http.StatusBadRequest)
+			return
+		}
+
+		if err := gw.VerifyWebhook(body, r.Header); err != nil {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var event WebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "malformed payload", http.StatusBadRequest)
+			return
+		}
+
+		if time.Since(event.Timestamp) > ws.replayWindow {
+			http.Error(w, "event too old", http.StatusUnauthorized)
+			return
+		}
+
+		dedupeKey := gatewayName + "|" + event.GatewayTxID + "|" + event.EventID
+		if ws.markSeen(dedupeKey) {
+			w.WriteHeader(http.StatusOK) // duplicate delivery: cheap no-op ack
+			return
+		}
+
+		tx, err := ws.repo.GetByGatewayTxID(event.GatewayTxID)
+		if err != nil {
+			http.Error(w, "unknown transaction", http.StatusNotFound)
+			return
+		}
+
+		newStatus := gatewayStatusToTransactionStatus(event.Status)
+		if tx.Status == newStatus {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		if err := ws.service.UpdateStatus(event.GatewayTxID, event.Status); err != nil {
+			// Out-of-order or already-settled events are expected here,
+			// not server errors: the control tower already refused the
+			// bad transition, so there's nothing left for the webhook to do.
+			if errors.Is(err, ErrPaymentTerminal) || errors.Is(err, ErrInvalidTransition) {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ws.notifier.SendNotification(fmt.Sprintf("Transaction %s is now %s", tx.ID, newStatus))
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (ws *WebhookServer) markSeen(key string) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.seen[key] {
+		return true
+	}
+	ws.seen[key] = true
+	return false
+}
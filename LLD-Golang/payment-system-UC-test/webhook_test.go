@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "payu-secret"
+
+func signWebhookBody(t *testing.T, secret string, body []byte) string {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newTestWebhookServer wires a WebhookServer against a fresh
+// in-memory transaction repo, with a single Processing transaction
+// already registered against gatewayTxID "payu_gw1" - the state every
+// test case in this file starts from.
+func newTestWebhookServer(t *testing.T) (*WebhookServer, *InMemoryTxRepo, string) {
+	t.Helper()
+	repo := NewInMemoryTxRepo()
+	ct := NewControlTower(repo)
+	mc := NewMissionControl(time.Minute, time.Minute, 0.9, 0.1)
+	service := NewPaymentService(repo, nil, nil, mc, ct, 1)
+
+	tx := &Transaction{ID: "tx1", RequestID: "req1", Status: Pending}
+	if err := repo.Save(tx); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := ct.RegisterAttempt(tx.ID, "PayU", "payu_gw1"); err != nil {
+		t.Fatalf("RegisterAttempt: %v", err)
+	}
+	if err := ct.Transition(tx.ID, Processing); err != nil {
+		t.Fatalf("Transition to Processing: %v", err)
+	}
+
+	factory := NewGatewayFactory(map[string]string{"PayU": testWebhookSecret})
+	ws := NewWebhookServer(factory, repo, service, &MockNotificationService{}, time.Hour)
+	return ws, repo, tx.ID
+}
+
+func postWebhook(t *testing.T, ws *WebhookServer, event WebhookEvent, secret string, badSignature bool) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	sig := signWebhookBody(t, secret, body)
+	if badSignature {
+		sig = "00" // well-formed hex, but wrong value
+	}
+	req := httptest.NewRequest(http.MethodPost, "/webhook/payu", bytes.NewReader(body))
+	req.Header.Set("X-PayU-Signature", sig)
+	rec := httptest.NewRecorder()
+	ws.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookRejectsSignatureMismatch(t *testing.T) {
+	ws, repo, txID := newTestWebhookServer(t)
+
+	event := WebhookEvent{GatewayTxID: "payu_gw1", Status: "SUCCESS", EventID: "evt1", Timestamp: time.Now()}
+	rec := postWebhook(t, ws, event, testWebhookSecret, true /* badSignature */)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	tx, err := repo.GetByID(txID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if tx.Status != Processing {
+		t.Fatalf("status after bad signature = %v, want unchanged %v", tx.Status, Processing)
+	}
+}
+
+func TestWebhookOutOfOrderDoesNotRegress(t *testing.T) {
+	ws, repo, txID := newTestWebhookServer(t)
+
+	success := WebhookEvent{GatewayTxID: "payu_gw1", Status: "SUCCESS", EventID: "evt1", Timestamp: time.Now()}
+	if rec := postWebhook(t, ws, success, testWebhookSecret, false); rec.Code != http.StatusOK {
+		t.Fatalf("SUCCESS webhook status = %d, want 200", rec.Code)
+	}
+
+	// A late-arriving PROCESSING event for the same gatewayTxID must not
+	// walk the now-settled transaction backwards.
+	stale := WebhookEvent{GatewayTxID: "payu_gw1", Status: "PROCESSING", EventID: "evt0", Timestamp: time.Now()}
+	if rec := postWebhook(t, ws, stale, testWebhookSecret, false); rec.Code != http.StatusOK {
+		t.Fatalf("stale PROCESSING webhook status = %d, want 200 (accepted as a no-op)", rec.Code)
+	}
+
+	tx, err := repo.GetByID(txID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if tx.Status != Success {
+		t.Fatalf("status after out-of-order replay = %v, want %v", tx.Status, Success)
+	}
+}
+
+func TestWebhookDuplicateDeliveryIsCheapNoOp(t *testing.T) {
+	ws, repo, txID := newTestWebhookServer(t)
+
+	event := WebhookEvent{GatewayTxID: "payu_gw1", Status: "SUCCESS", EventID: "evt1", Timestamp: time.Now()}
+	if rec := postWebhook(t, ws, event, testWebhookSecret, false); rec.Code != http.StatusOK {
+		t.Fatalf("first delivery status = %d, want 200", rec.Code)
+	}
+
+	tx, err := repo.GetByID(txID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	firstUpdatedAt := tx.UpdatedAt
+
+	// Same (gateway, gatewayTxID, eventID) delivered again must be a
+	// dedup no-op: it shouldn't touch the transaction a second time.
+	if rec := postWebhook(t, ws, event, testWebhookSecret, false); rec.Code != http.StatusOK {
+		t.Fatalf("duplicate delivery status = %d, want 200", rec.Code)
+	}
+
+	tx, err = repo.GetByID(txID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !tx.UpdatedAt.Equal(firstUpdatedAt) {
+		t.Fatalf("duplicate delivery updated the transaction again: %v != %v", tx.UpdatedAt, firstUpdatedAt)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// pendingEntry is an in-flight delivery waiting on Ack/Nack, or on its
+// AckTimeout deadline, whichever comes first.
+type pendingEntry struct {
+	msg      *Message
+	deadline time.Time
+}
+
+// ack clears the pending-ack entry for ackID, marking msg's delivery as
+// successfully processed.
+func (b *Broker) ack(sub *Subscriber, ackID string) {
+	sub.mu.Lock()
+	delete(sub.pendingAcks, ackID)
+	sub.mu.Unlock()
+}
+
+// nack is the explicit-Nack entry point; ack-timeout expiry goes through
+// the same redeliverOrDeadLetter path via scanExpiredAcks.
+func (b *Broker) nack(sub *Subscriber, ackID string) {
+	sub.mu.Lock()
+	entry, ok := sub.pendingAcks[ackID]
+	if ok {
+		delete(sub.pendingAcks, ackID)
+	}
+	sub.mu.Unlock()
+	if !ok {
+		return
+	}
+	b.redeliverOrDeadLetter(sub, entry.msg)
+}
+
+// redeliverOrDeadLetter bumps msg's RedeliveryCount and either schedules it
+// for redelivery after a backed-off NackRedeliveryDelay, or - once
+// MaxRedeliveries is exceeded - routes it to "dlq.<topic>".
+func (b *Broker) redeliverOrDeadLetter(sub *Subscriber, msg *Message) {
+	msg.RedeliveryCount++
+	if msg.RedeliveryCount > b.MaxRedeliveries {
+		b.deadLetter(msg)
+		return
+	}
+
+	delay := b.redeliveryDelay(msg.RedeliveryCount)
+	msg.DeliverAfter = time.Now().Add(delay)
+	time.AfterFunc(delay, func() {
+		sub.Signal(msg)
+	})
+}
+
+// redeliveryDelay doubles NackRedeliveryDelay per attempt, capped at 30
+// minutes, so a consumer that's down for a while doesn't get hammered with
+// retries the moment it comes back.
+func (b *Broker) redeliveryDelay(attempt int) time.Duration {
+	delay := b.NackRedeliveryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+	}
+	const maxDelay = 30 * time.Minute
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// deadLetter republishes msg, unchanged, onto "dlq.<topic>" once it has
+// exhausted every redelivery attempt.
+func (b *Broker) deadLetter(msg *Message) {
+	dlqTopic := "dlq." + msg.Topic
+	fmt.Printf("Message %q on %s exceeded max redeliveries (%d), routing to %s\n",
+		msg.MessageID, msg.Topic, msg.RedeliveryCount, dlqTopic)
+	b.PublishMessage(&Message{
+		Topic:     dlqTopic,
+		Body:      msg.Body,
+		Timestamp: time.Now(),
+		MessageID: msg.MessageID,
+		Attrs:     msg.Attrs,
+	})
+}
+
+// startAckScanner runs a background sweep for expired pending-acks across
+// every subscriber, treating an expired AckTimeout the same as an explicit
+// Nack - the consumer that never called Ack/Nack effectively crashed.
+func (b *Broker) startAckScanner() {
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			b.scanExpiredAcks()
+		}
+	}()
+}
+
+func (b *Broker) scanExpiredAcks() {
+	b.mu.RLock()
+	subs := make([]*Subscriber, 0, len(b.Subscribers))
+	for _, sub := range b.Subscribers {
+		subs = append(subs, sub)
+	}
+	b.mu.RUnlock()
+
+	now := time.Now()
+	for _, sub := range subs {
+		sub.mu.Lock()
+		var expired []*pendingEntry
+		for ackID, entry := range sub.pendingAcks {
+			if now.After(entry.deadline) {
+				expired = append(expired, entry)
+				delete(sub.pendingAcks, ackID)
+			}
+		}
+		sub.mu.Unlock()
+
+		for _, entry := range expired {
+			b.redeliverOrDeadLetter(sub, entry.msg)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDuplicate is returned by PublishMessage when the message's MessageID
+// has already been seen on that topic within the dedup TTL.
+var ErrDuplicate = errors.New("pubsub: duplicate message")
+
+type timeCacheEntry struct {
+	id         string
+	insertedAt time.Time
+}
+
+// TimeCache remembers message IDs for a bounded TTL so a producer's retried
+// (or redelivered) message can be recognized and dropped instead of
+// fanned out twice. WHY a FIFO queue alongside the map: insertion order is
+// also expiry order, so sweeping expired entries is just popping the front
+// of the queue instead of scanning the whole map.
+type TimeCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	seen  map[string]*list.Element
+	queue *list.List // front = oldest
+}
+
+// NewTimeCache creates a cache that forgets an ID after ttl has passed
+// since it was first seen.
+func NewTimeCache(ttl time.Duration) *TimeCache {
+	return &TimeCache{
+		ttl:   ttl,
+		seen:  make(map[string]*list.Element),
+		queue: list.New(),
+	}
+}
+
+// SeenOrAdd sweeps expired entries, then reports whether id was already
+// present; if not, it records id as seen starting now.
+func (c *TimeCache) SeenOrAdd(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweepLocked()
+
+	if _, ok := c.seen[id]; ok {
+		return true
+	}
+
+	elem := c.queue.PushBack(&timeCacheEntry{id: id, insertedAt: time.Now()})
+	c.seen[id] = elem
+	return false
+}
+
+func (c *TimeCache) sweepLocked() {
+	cutoff := time.Now().Add(-c.ttl)
+	for {
+		front := c.queue.Front()
+		if front == nil {
+			return
+		}
+		entry := front.Value.(*timeCacheEntry)
+		if entry.insertedAt.After(cutoff) {
+			return
+		}
+		c.queue.Remove(front)
+		delete(c.seen, entry.id)
+	}
+}
+
+// ----------------------------------------------------------
+// Broker integration
+
+// defaultDedupTTL is the window Publish remembers a MessageID for when
+// dedup is enabled via NewBrokerWithDedup.
+const defaultDedupTTL = 60 * time.Second
+
+// NewBrokerWithDedup is NewBroker with MessageID-based publish
+// deduplication turned on: a Message published twice with the same
+// MessageID (on the same topic) within ttl is a no-op the second time.
+func NewBrokerWithDedup(ttl time.Duration) *Broker {
+	b := NewBroker()
+	b.dedupTTL = ttl
+	b.dedup = make(map[string]*TimeCache)
+	return b
+}
+
+// dedupCache returns (creating if necessary) the per-topic TimeCache used
+// to detect duplicate MessageIDs. WHY per-topic: a global cache would make
+// one noisy topic evict IDs from a quiet one far sooner than its TTL
+// promises.
+func (b *Broker) dedupCache(topic string) *TimeCache {
+	b.dedupMu.Lock()
+	defer b.dedupMu.Unlock()
+	cache, ok := b.dedup[topic]
+	if !ok {
+		cache = NewTimeCache(b.dedupTTL)
+		b.dedup[topic] = cache
+	}
+	return cache
+}
+
+// checkDuplicate reports whether message should be dropped as a repeat of
+// one already published on its topic within the dedup TTL. Dedup only
+// applies when the broker was built with NewBrokerWithDedup and the
+// message carries a non-empty MessageID.
+func (b *Broker) checkDuplicate(message *Message) bool {
+	if b.dedupTTL <= 0 || message.MessageID == "" {
+		return false
+	}
+	return b.dedupCache(message.Topic).SeenOrAdd(message.MessageID)
+}
@@ -3,26 +3,71 @@ package main
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Message struct {
-	Topic     string
-	Body      string
-	Timestamp time.Time
-	MessageID string
+	Topic           string
+	Body            string
+	Timestamp       time.Time
+	MessageID       string
+	Attrs           map[string]interface{} // tags/attributes a query can filter on
+	RedeliveryCount int                    // how many times this message has been redelivered
+	DeliverAfter    time.Time              // set on redelivery: don't hand this out before then
+
+	// Ack bookkeeping, wired up by Subscriber.Listen at delivery time -
+	// deliberately unexported so they never round-trip through
+	// SpillToDiskPolicy's JSON encoding.
+	ackID     string
+	acked     int32
+	broker    *Broker
+	sourceSub *Subscriber
 }
 
 func NewMessage(topic, body string) *Message {
 	return &Message{
-		Topic: topic,
-		Body:  body,
+		Topic:     topic,
+		Body:      body,
+		Timestamp: time.Now(),
 	}
 }
 
+// NewMessageWithAttrs is NewMessage plus a tag/attribute map queries can
+// filter on (e.g. `priority>3 AND tags CONTAINS 'ai'`).
+func NewMessageWithAttrs(topic, body string, attrs map[string]interface{}) *Message {
+	msg := NewMessage(topic, body)
+	msg.Attrs = attrs
+	return msg
+}
+
 func (m *Message) GetTopic() string { return m.Topic }
 func (m *Message) GetBody() string  { return m.Body }
 
+// Ack confirms successful processing, clearing the message's pending-ack
+// entry so it's never redelivered. Safe to call at most once per delivery;
+// later calls (including a racing Nack) are no-ops.
+func (m *Message) Ack() {
+	if !atomic.CompareAndSwapInt32(&m.acked, 0, 1) {
+		return
+	}
+	if m.broker != nil && m.sourceSub != nil {
+		m.broker.ack(m.sourceSub, m.ackID)
+	}
+}
+
+// Nack signals failed processing, making the broker redeliver the message
+// after NackRedeliveryDelay (backing off per attempt) or, once
+// MaxRedeliveries is exceeded, route it to "dlq.<topic>" instead.
+func (m *Message) Nack() {
+	if !atomic.CompareAndSwapInt32(&m.acked, 0, 1) {
+		return
+	}
+	if m.broker != nil && m.sourceSub != nil {
+		m.broker.nack(m.sourceSub, m.ackID)
+	}
+}
+
 // ----------------------------------------------------------
 // Subscriber represents a subscriber in the pub-sub system.
 
@@ -31,7 +76,20 @@ type Subscriber struct {
 	Messages     chan *Message   // Messages channel
 	Topics       map[string]bool // topics the subscriber is subscribed to
 	Active       bool            // is the subscriber active
-	mu           sync.Mutex      // mutex for concurrent access
+	mu           sync.Mutex      // mutex for concurrent access, and for pendingAcks below
+	overflow     OverflowPolicy
+	dropped      int64 // atomic: messages OverflowPolicy couldn't place anywhere
+	spilled      int64 // atomic: messages OverflowPolicy wrote to disk
+	onDrop       func(msg *Message)
+
+	broker      *Broker
+	pendingAcks map[string]*pendingEntry
+	ackCounter  int64 // atomic
+
+	// Handler, if set, takes over delivery from Listen's default
+	// print-then-Ack behavior. It owns the message and must call msg.Ack()
+	// or msg.Nack() itself.
+	Handler func(msg *Message)
 }
 
 var subCounter int
@@ -44,13 +102,20 @@ func GenerateSubscriberID() string {
 	return fmt.Sprintf("sub-%d", subCounter)
 }
 
-func NewSubscriber() (string, *Subscriber) {
+// NewSubscriber creates a subscriber with a bounded Messages channel and the
+// OverflowPolicy that decides what happens once it fills up. A nil policy
+// defaults to DropNewestPolicy, matching the broker's original behavior.
+func NewSubscriber(bufferSize int, policy OverflowPolicy) (string, *Subscriber) {
+	if policy == nil {
+		policy = DropNewestPolicy{}
+	}
 	id := GenerateSubscriberID()
 	return id, &Subscriber{
 		SubscriberID: id,
-		Messages:     make(chan *Message, 100), // Fixed buffer size
+		Messages:     make(chan *Message, bufferSize),
 		Topics:       make(map[string]bool),
 		Active:       true,
+		overflow:     policy,
 	}
 }
 
@@ -87,23 +152,79 @@ func (s *Subscriber) Destruct() {
 
 func (s *Subscriber) Signal(msg *Message) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	if s.Active {
-		select {
-		case s.Messages <- msg:
-		default:
-			fmt.Printf("Subscriber %s: channel full, dropping message\n", s.SubscriberID)
+	active := s.Active
+	s.mu.Unlock()
+	if !active {
+		return
+	}
+
+	select {
+	case s.Messages <- msg:
+		return
+	default:
+	}
+
+	if s.overflow.OnFull(s, msg) {
+		if _, spilled := s.overflow.(*SpillToDiskPolicy); spilled {
+			atomic.AddInt64(&s.spilled, 1)
 		}
+		return
 	}
+
+	atomic.AddInt64(&s.dropped, 1)
+	if s.onDrop != nil {
+		s.onDrop(msg)
+	}
+	fmt.Printf("Subscriber %s: channel full, dropping message (policy=%T)\n", s.SubscriberID, s.overflow)
+}
+
+// deliver hands msg to the consumer and tracks it in pendingAcks until
+// Ack/Nack is called (or AckTimeout expires and the broker redelivers it
+// on the consumer's behalf).
+func (s *Subscriber) deliver(msg *Message) {
+	ackID := fmt.Sprintf("%s-%d", s.SubscriberID, atomic.AddInt64(&s.ackCounter, 1))
+	msg.ackID = ackID
+	msg.broker = s.broker
+	msg.sourceSub = s
+	atomic.StoreInt32(&msg.acked, 0)
+
+	if s.broker != nil {
+		s.mu.Lock()
+		if s.pendingAcks == nil {
+			s.pendingAcks = make(map[string]*pendingEntry)
+		}
+		s.pendingAcks[ackID] = &pendingEntry{msg: msg, deadline: time.Now().Add(s.broker.AckTimeout)}
+		s.mu.Unlock()
+	}
+
+	if s.Handler != nil {
+		s.Handler(msg)
+		return
+	}
+	fmt.Printf("Subscriber %s received message on topic: %s, body: %s\n",
+		s.SubscriberID, msg.GetTopic(), msg.GetBody())
+	msg.Ack()
 }
 
 func (s *Subscriber) Listen() {
-	for msg := range s.Messages {
-		// Process the message
-		fmt.Printf("Subscriber %s received message on topic: %s, body: %s\n",
-			s.SubscriberID, msg.GetTopic(), msg.GetBody())
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg, ok := <-s.Messages:
+			if !ok {
+				fmt.Printf("Subscriber %s: listener stopped\n", s.SubscriberID)
+				return
+			}
+			s.deliver(msg)
+		case <-ticker.C:
+			// Idle tick: give a spill-to-disk policy a chance to replay
+			// anything it queued while the channel was full.
+			if replayer, ok := s.overflow.(interface{ Replay(*Subscriber) }); ok {
+				replayer.Replay(s)
+			}
+		}
 	}
-	fmt.Printf("Subscriber %s: listener stopped\n", s.SubscriberID)
 }
 
 // ------------------------------------------------
@@ -115,19 +236,56 @@ type Broker struct {
 	Subscribers Subscribers            // map of subscriber ID to Subscriber
 	topics      map[string]Subscribers // map of topic to subscribers
 	mu          sync.RWMutex           // mutex for concurrent access
+	querySubs   map[string]*querySubscription
+	matchCache  *MatchCache
+	// OnDrop, if set, is called whenever a subscriber's OverflowPolicy
+	// gives up on a message outright (e.g. DropNewest/DropOldest).
+	OnDrop func(subscriberID string, msg *Message)
+
+	// Ack/redelivery configuration - see ack.go.
+	AckTimeout          time.Duration
+	NackRedeliveryDelay time.Duration
+	MaxRedeliveries     int
+
+	// Log-backed topics - see topiclog.go.
+	logMu        sync.Mutex
+	logs         map[string]*TopicLog
+	LogRetention LogRetention
+
+	// Publish deduplication - see dedup.go. dedupTTL <= 0 means disabled.
+	dedupMu  sync.Mutex
+	dedup    map[string]*TimeCache
+	dedupTTL time.Duration
 }
 
 func NewBroker() *Broker {
-	return &Broker{
-		Subscribers: make(Subscribers),
-		topics:      make(map[string]Subscribers),
+	b := &Broker{
+		Subscribers:         make(Subscribers),
+		topics:              make(map[string]Subscribers),
+		AckTimeout:          30 * time.Second,
+		NackRedeliveryDelay: time.Minute,
+		MaxRedeliveries:     5,
 	}
+	b.startAckScanner()
+	return b
 }
 
 func (b *Broker) AddSubscriber() *Subscriber {
+	return b.AddSubscriberWithPolicy(100, DropNewestPolicy{})
+}
+
+// AddSubscriberWithPolicy is AddSubscriber with an explicit buffer size and
+// OverflowPolicy instead of the default 100-slot DropNewestPolicy.
+func (b *Broker) AddSubscriberWithPolicy(bufferSize int, policy OverflowPolicy) *Subscriber {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	id, sub := NewSubscriber()
+	id, sub := NewSubscriber(bufferSize, policy)
+	sub.broker = b
+	sub.onDrop = func(msg *Message) {
+		if b.OnDrop != nil {
+			b.OnDrop(id, msg)
+		}
+	}
 	b.Subscribers[id] = sub
 
 	// Start listening to messages for this subscriber
@@ -136,12 +294,32 @@ func (b *Broker) AddSubscriber() *Subscriber {
 	return sub
 }
 
+// Stats reports cumulative dropped/spilled message counts across every
+// subscriber currently registered with the broker.
+func (b *Broker) Stats() BrokerStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var stats BrokerStats
+	for _, sub := range b.Subscribers {
+		stats.Dropped += atomic.LoadInt64(&sub.dropped)
+		stats.Spilled += atomic.LoadInt64(&sub.spilled)
+	}
+	return stats
+}
+
 func (b *Broker) RemoveSubscriber(sub *Subscriber) {
 	topics := sub.GetTopics()
 
 	for _, topic := range topics {
 		b.Unsubscribe(topic, sub)
 	}
+
+	b.logMu.Lock()
+	for _, log := range b.logs {
+		log.removeTailer(sub.SubscriberID)
+	}
+	b.logMu.Unlock()
+
 	sub.Destruct()
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -204,15 +382,26 @@ func (b *Broker) Unsubscribe(topic string, sub *Subscriber) {
 	}
 }
 
-func (b *Broker) Publish(topic string, msg string) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
+func (b *Broker) Publish(topic string, msg string) error {
+	return b.PublishMessage(NewMessage(topic, msg))
+}
 
-	message := NewMessage(topic, msg)
+// PublishMessage is Publish for a caller that already built a Message (e.g.
+// with attributes attached for query subscribers). It returns ErrDuplicate,
+// without fanning the message out, if dedup is enabled and message.MessageID
+// was already published on this topic within the dedup TTL.
+func (b *Broker) PublishMessage(message *Message) error {
+	if b.checkDuplicate(message) {
+		return ErrDuplicate
+	}
 
-	if subscribers, exists := b.topics[topic]; exists {
+	b.mu.RLock()
+	subscribers, exists := b.topics[message.Topic]
+	b.mu.RUnlock()
+
+	if exists {
 		fmt.Printf("Publishing to topic '%s': %s (to %d subscribers)\n",
-			topic, msg, len(subscribers))
+			message.Topic, message.Body, len(subscribers))
 
 		for _, sub := range subscribers {
 			go func(s *Subscriber) {
@@ -220,8 +409,12 @@ func (b *Broker) Publish(topic string, msg string) {
 			}(sub)
 		}
 	} else {
-		fmt.Printf("Topic '%s' has no subscribers, message dropped: %s\n", topic, msg)
+		fmt.Printf("Topic '%s' has no subscribers, message dropped: %s\n", message.Topic, message.Body)
 	}
+
+	b.publishToQuerySubs(message)
+	b.appendToLog(message)
+	return nil
 }
 
 func (b *Broker) GetTopics() []string {
@@ -301,6 +494,102 @@ func main() {
 		fmt.Printf("  - %s\n", id)
 	}
 
+	// Query subscription: matches by attributes instead of an exact topic.
+	fmt.Println("\n=== Query Subscription ===")
+	querySub := broker.AddSubscriber()
+	if err := broker.SubscribeQuery(querySub, "topic='news' AND priority>3"); err != nil {
+		fmt.Printf("invalid query: %v\n", err)
+	}
+	broker.PublishMessage(NewMessageWithAttrs("news", "Urgent market update", map[string]interface{}{"priority": 5}))
+	time.Sleep(100 * time.Millisecond)
+
+	// Overflow policy: a tiny buffer with DropOldest so a slow subscriber
+	// keeps only the freshest messages instead of blocking the publisher.
+	fmt.Println("\n=== Overflow Policy ===")
+	broker.OnDrop = func(subscriberID string, msg *Message) {
+		fmt.Printf("OnDrop: %s lost message on topic %s\n", subscriberID, msg.Topic)
+	}
+	slowSub := broker.AddSubscriberWithPolicy(1, DropOldestPolicy{})
+	broker.Subscribe("alerts", slowSub)
+	for i := 0; i < 3; i++ {
+		broker.Publish("alerts", fmt.Sprintf("alert #%d", i))
+	}
+	time.Sleep(100 * time.Millisecond)
+	stats := broker.Stats()
+	fmt.Printf("Broker stats: dropped=%d spilled=%d\n", stats.Dropped, stats.Spilled)
+
+	// Consumer-acknowledged delivery: the first delivery is nacked and
+	// comes back after the backoff delay instead of being lost.
+	fmt.Println("\n=== Ack/Nack Redelivery ===")
+	broker.AckTimeout = 2 * time.Second
+	broker.NackRedeliveryDelay = 200 * time.Millisecond
+	ackSub := broker.AddSubscriber()
+	seenOnce := false
+	ackSub.Handler = func(msg *Message) {
+		if !seenOnce {
+			seenOnce = true
+			fmt.Printf("Subscriber %s nacking message (redelivery #%d)\n", ackSub.SubscriberID, msg.RedeliveryCount)
+			msg.Nack()
+			return
+		}
+		fmt.Printf("Subscriber %s acking message (redelivery #%d)\n", ackSub.SubscriberID, msg.RedeliveryCount)
+		msg.Ack()
+	}
+	broker.Subscribe("billing", ackSub)
+	broker.Publish("billing", "invoice #42 ready")
+	time.Sleep(500 * time.Millisecond)
+
+	// Log-backed topic: a subscriber can join mid-stream and still see
+	// everything from the beginning, and an already-live subscriber can
+	// rewind to replay history.
+	fmt.Println("\n=== Log-Backed Topic with Cursor Subscriptions ===")
+	broker.Publish("audit", "user alice logged in")
+	broker.Publish("audit", "user alice changed password")
+	broker.Publish("audit", "user bob logged in")
+	time.Sleep(100 * time.Millisecond)
+	fmt.Printf("audit offsets: earliest=%d latest=%d\n", broker.EarliestOffset("audit"), broker.LatestOffset("audit"))
+
+	_, replaySub := NewSubscriber(10, nil)
+	replaySub.Handler = func(msg *Message) {
+		fmt.Printf("replaySub saw: %s\n", msg.Body)
+		msg.Ack()
+	}
+	if err := broker.SubscribeFrom("audit", replaySub, FromBeginning); err != nil {
+		fmt.Printf("subscribe from beginning failed: %v\n", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	_, tailSub := NewSubscriber(10, nil)
+	tailSub.Handler = func(msg *Message) {
+		fmt.Printf("tailSub saw: %s\n", msg.Body)
+		msg.Ack()
+	}
+	if err := broker.SubscribeFrom("audit", tailSub, FromLatest); err != nil {
+		fmt.Printf("subscribe from latest failed: %v\n", err)
+	}
+	broker.Publish("audit", "user bob logged out")
+	time.Sleep(100 * time.Millisecond)
+
+	fmt.Println("Seeking tailSub back to the beginning")
+	broker.Seek(tailSub, FromBeginning)
+	time.Sleep(100 * time.Millisecond)
+
+	// Publish deduplication: a retried MessageID within the TTL window is
+	// dropped instead of delivered twice.
+	fmt.Println("\n=== Publish Deduplication ===")
+	dedupBroker := NewBrokerWithDedup(time.Minute)
+	paymentsSub := dedupBroker.AddSubscriber()
+	dedupBroker.Subscribe("payments", paymentsSub)
+	first := &Message{Topic: "payments", Body: "charge $10", Timestamp: time.Now(), MessageID: "evt-1"}
+	retry := &Message{Topic: "payments", Body: "charge $10", Timestamp: time.Now(), MessageID: "evt-1"}
+	if err := dedupBroker.PublishMessage(first); err != nil {
+		fmt.Printf("unexpected error publishing evt-1: %v\n", err)
+	}
+	if err := dedupBroker.PublishMessage(retry); err != nil {
+		fmt.Printf("retry of evt-1 rejected as expected: %v\n", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
 	// Remove a subscriber
 	fmt.Println("\n=== Removing Subscriber ===")
 	broker.RemoveSubscriber(sub3)
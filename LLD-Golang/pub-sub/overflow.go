@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// BrokerStats is a point-in-time snapshot returned by Broker.Stats().
+type BrokerStats struct {
+	Dropped int64 // messages an OverflowPolicy discarded outright
+	Spilled int64 // messages an OverflowPolicy wrote to disk
+}
+
+// OverflowPolicy decides what happens to msg when sub's buffered Messages
+// channel is full. It returns whether msg was placed somewhere (the
+// channel, disk, or eventually delivered) - false means the message is
+// gone for good.
+type OverflowPolicy interface {
+	OnFull(sub *Subscriber, msg *Message) (accepted bool)
+}
+
+// DropNewestPolicy discards the incoming message, leaving the backlog
+// untouched. This is the broker's original (and still default) behavior.
+type DropNewestPolicy struct{}
+
+func (DropNewestPolicy) OnFull(_ *Subscriber, _ *Message) bool {
+	return false
+}
+
+// DropOldestPolicy evicts the head of the channel to make room for the
+// newest message. WHY: for a status feed where only the latest value
+// matters, keeping recent messages beats keeping old ones.
+type DropOldestPolicy struct{}
+
+func (DropOldestPolicy) OnFull(sub *Subscriber, msg *Message) bool {
+	select {
+	case <-sub.Messages:
+	default:
+	}
+	select {
+	case sub.Messages <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// BlockWithTimeoutPolicy waits up to Timeout for room to open up in the
+// channel before giving up. WHY: for a subscriber that's merely slow (not
+// stuck), a short wait avoids losing a message to a momentary burst.
+type BlockWithTimeoutPolicy struct {
+	Timeout time.Duration
+}
+
+func (p BlockWithTimeoutPolicy) OnFull(sub *Subscriber, msg *Message) bool {
+	select {
+	case sub.Messages <- msg:
+		return true
+	case <-time.After(p.Timeout):
+		return false
+	}
+}
+
+// SpillToDiskPolicy appends messages that don't fit in the channel to a
+// file as newline-delimited JSON, and replays them back onto the channel
+// once it drains. WHY: a subscriber that's merely backed up (not gone)
+// shouldn't lose messages just because it fell behind momentarily.
+type SpillToDiskPolicy struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewSpillToDiskPolicy(path string) *SpillToDiskPolicy {
+	return &SpillToDiskPolicy{Path: path}
+}
+
+func (p *SpillToDiskPolicy) OnFull(_ *Subscriber, msg *Message) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f, err := os.OpenFile(p.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return false
+	}
+	data = append(data, '\n')
+	_, err = f.Write(data)
+	return err == nil
+}
+
+// Replay pushes every spilled message back onto sub.Messages, best-effort,
+// and rewrites the spool file with whatever didn't fit this round.
+// Subscriber.Listen calls this on every idle tick so a drained channel
+// picks spilled messages back up without the publisher's help.
+func (p *SpillToDiskPolicy) Replay(sub *Subscriber) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := os.ReadFile(p.Path)
+	if err != nil || len(data) == 0 {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var remaining []*Message
+	for {
+		var msg Message
+		if err := decoder.Decode(&msg); err != nil {
+			break
+		}
+		if remaining != nil {
+			remaining = append(remaining, &msg)
+			continue
+		}
+		select {
+		case sub.Messages <- &msg:
+		default:
+			remaining = append(remaining, &msg)
+		}
+	}
+
+	if len(remaining) == 0 {
+		os.Remove(p.Path)
+		return
+	}
+
+	f, err := os.Create(p.Path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, msg := range remaining {
+		enc.Encode(msg)
+	}
+}
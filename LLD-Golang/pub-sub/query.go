@@ -0,0 +1,536 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ----------------------------------------------------------
+// Query AST
+//
+// A query is parsed once (at SubscribeQuery time) into a Node tree and
+// re-evaluated against every published message, so subscribers aren't
+// limited to exact-topic matching - e.g. `topic='news' AND priority>3`.
+
+type Node interface {
+	Match(msg *Message) bool
+}
+
+type AndNode struct {
+	Left, Right Node
+}
+
+func (n *AndNode) Match(msg *Message) bool {
+	return n.Left.Match(msg) && n.Right.Match(msg)
+}
+
+type OrNode struct {
+	Left, Right Node
+}
+
+func (n *OrNode) Match(msg *Message) bool {
+	return n.Left.Match(msg) || n.Right.Match(msg)
+}
+
+type NotNode struct {
+	Inner Node
+}
+
+func (n *NotNode) Match(msg *Message) bool {
+	return !n.Inner.Match(msg)
+}
+
+// Condition is a leaf of the query AST: `field op value`.
+type Condition struct {
+	Field string
+	Op    string
+	Value interface{}
+}
+
+// fieldValue resolves a condition's field against the message's builtins
+// (topic, body, timestamp) before falling back to its attribute map.
+func fieldValue(msg *Message, field string) (interface{}, bool) {
+	switch field {
+	case "topic":
+		return msg.Topic, true
+	case "body":
+		return msg.Body, true
+	case "timestamp":
+		return msg.Timestamp, true
+	}
+	if msg.Attrs == nil {
+		return nil, false
+	}
+	v, ok := msg.Attrs[field]
+	return v, ok
+}
+
+func (c *Condition) Match(msg *Message) bool {
+	actual, exists := fieldValue(msg, c.Field)
+	if c.Op == "EXISTS" {
+		return exists
+	}
+	if !exists {
+		return false
+	}
+
+	// Numeric comparison when both sides parse as numbers, string
+	// comparison otherwise - this is what lets `priority>3` and
+	// `topic='news'` both work through the same Condition type.
+	if af, aok := toFloat(actual); aok {
+		if vf, vok := toFloat(c.Value); vok {
+			return compareFloat(af, c.Op, vf)
+		}
+	}
+
+	as := fmt.Sprintf("%v", actual)
+	vs := fmt.Sprintf("%v", c.Value)
+	switch c.Op {
+	case "=":
+		return as == vs
+	case "!=":
+		return as != vs
+	case "CONTAINS":
+		return strings.Contains(as, vs)
+	case "<", "<=", ">", ">=":
+		return false // non-numeric values never satisfy an ordering op
+	default:
+		return false
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func compareFloat(a float64, op string, b float64) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "CONTAINS":
+		return false
+	default:
+		return false
+	}
+}
+
+// ----------------------------------------------------------
+// Hand-rolled recursive-descent parser
+//
+// Grammar:
+//   expr      := andExpr (OR andExpr)*
+//   andExpr   := unary (AND unary)*
+//   unary     := NOT unary | primary
+//   primary   := '(' expr ')' | condition
+//   condition := IDENT [op value]
+//   op        := '=' | '!=' | '<' | '<=' | '>' | '>=' | CONTAINS | EXISTS
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+type lexer struct {
+	src []rune
+	pos int
+}
+
+func newLexer(query string) *lexer {
+	return &lexer{src: []rune(query)}
+}
+
+func (l *lexer) peekRune() rune {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t') {
+		l.pos++
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF}, nil
+	}
+
+	r := l.src[l.pos]
+	switch {
+	case r == '(':
+		l.pos++
+		return token{kind: tokLParen, text: "("}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")"}, nil
+	case r == '\'':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.src) && l.src[l.pos] != '\'' {
+			l.pos++
+		}
+		if l.pos >= len(l.src) {
+			return token{}, fmt.Errorf("unterminated string literal")
+		}
+		s := string(l.src[start:l.pos])
+		l.pos++ // consume closing quote
+		return token{kind: tokString, text: s}, nil
+	case r == '=':
+		l.pos++
+		return token{kind: tokOp, text: "="}, nil
+	case r == '!' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '=':
+		l.pos += 2
+		return token{kind: tokOp, text: "!="}, nil
+	case r == '<':
+		l.pos++
+		if l.peekRune() == '=' {
+			l.pos++
+			return token{kind: tokOp, text: "<="}, nil
+		}
+		return token{kind: tokOp, text: "<"}, nil
+	case r == '>':
+		l.pos++
+		if l.peekRune() == '=' {
+			l.pos++
+			return token{kind: tokOp, text: ">="}, nil
+		}
+		return token{kind: tokOp, text: ">"}, nil
+	case isDigit(r):
+		start := l.pos
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		return token{kind: tokNumber, text: string(l.src[start:l.pos])}, nil
+	case isIdentStart(r):
+		start := l.pos
+		for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+			l.pos++
+		}
+		word := string(l.src[start:l.pos])
+		switch strings.ToUpper(word) {
+		case "AND":
+			return token{kind: tokAnd, text: word}, nil
+		case "OR":
+			return token{kind: tokOr, text: word}, nil
+		case "NOT":
+			return token{kind: tokNot, text: word}, nil
+		case "CONTAINS":
+			return token{kind: tokOp, text: "CONTAINS"}, nil
+		case "EXISTS":
+			return token{kind: tokOp, text: "EXISTS"}, nil
+		default:
+			return token{kind: tokIdent, text: word}, nil
+		}
+	default:
+		return token{}, fmt.Errorf("unexpected character %q at position %d", r, l.pos)
+	}
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+func isIdentStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+func isIdentPart(r rune) bool { return isIdentStart(r) || isDigit(r) }
+
+type queryParser struct {
+	lex *lexer
+	tok token
+}
+
+// ParseQuery compiles a query string into a Node tree.
+func ParseQuery(query string) (Node, error) {
+	p := &queryParser{lex: newLexer(query)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected trailing token %q", p.tok.text)
+	}
+	return node, nil
+}
+
+func (p *queryParser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = t
+	return nil
+}
+
+func (p *queryParser) parseExpr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &OrNode{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &AndNode{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (Node, error) {
+	if p.tok.kind == tokNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (Node, error) {
+	if p.tok.kind == tokLParen {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *queryParser) parseCondition() (Node, error) {
+	if p.tok.kind != tokIdent {
+		return nil, fmt.Errorf("expected field name, got %q", p.tok.text)
+	}
+	field := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokOp {
+		return nil, fmt.Errorf("expected operator after field %q", field)
+	}
+	op := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if op == "EXISTS" {
+		return &Condition{Field: field, Op: op}, nil
+	}
+
+	var value interface{}
+	switch p.tok.kind {
+	case tokString:
+		value = p.tok.text
+	case tokNumber:
+		f, err := strconv.ParseFloat(p.tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.tok.text)
+		}
+		value = f
+	default:
+		return nil, fmt.Errorf("expected value after operator %q", op)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	return &Condition{Field: field, Op: op, Value: value}, nil
+}
+
+// ----------------------------------------------------------
+// MatchCache
+//
+// Keyed by (queryID, topic). A query is only cacheable per-topic when every
+// condition it contains reaches no further than the `topic` builtin - e.g.
+// `topic='news'` always gives the same answer for a given topic regardless
+// of body/attrs, so the broker can skip evaluating the AST again on the
+// next publish to that topic. Anything that inspects body/attrs/timestamp
+// is evaluated fresh every time.
+type MatchCache struct {
+	mu    sync.Mutex
+	cache map[string]bool // key: queryID + "|" + topic
+}
+
+func NewMatchCache() *MatchCache {
+	return &MatchCache{cache: make(map[string]bool)}
+}
+
+func (c *MatchCache) key(queryID, topic string) string {
+	return queryID + "|" + topic
+}
+
+func (c *MatchCache) Get(queryID, topic string) (matched bool, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched, found = c.cache[c.key(queryID, topic)]
+	return matched, found
+}
+
+func (c *MatchCache) Set(queryID, topic string, matched bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[c.key(queryID, topic)] = matched
+}
+
+// topicOnlyFields reports whether every Condition in node only ever
+// references the "topic" builtin, making the query safe for MatchCache.
+func topicOnlyFields(node Node) bool {
+	switch n := node.(type) {
+	case *Condition:
+		return n.Field == "topic"
+	case *AndNode:
+		return topicOnlyFields(n.Left) && topicOnlyFields(n.Right)
+	case *OrNode:
+		return topicOnlyFields(n.Left) && topicOnlyFields(n.Right)
+	case *NotNode:
+		return topicOnlyFields(n.Inner)
+	default:
+		return false
+	}
+}
+
+// ----------------------------------------------------------
+// Broker query subscriptions
+
+type querySubscription struct {
+	queryID   string
+	sub       *Subscriber
+	node      Node
+	cacheable bool
+	rawQuery  string
+}
+
+// SubscribeQuery registers sub to receive any message matching query,
+// independent of topic subscriptions. query is parsed once here; Publish
+// re-evaluates the resulting AST (or a cached verdict) per message.
+func (b *Broker) SubscribeQuery(sub *Subscriber, query string) error {
+	node, err := ParseQuery(query)
+	if err != nil {
+		return fmt.Errorf("invalid query %q: %w", query, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.querySubs == nil {
+		b.querySubs = make(map[string]*querySubscription)
+	}
+	if b.matchCache == nil {
+		b.matchCache = NewMatchCache()
+	}
+	queryID := fmt.Sprintf("query-%d", len(b.querySubs)+1)
+	b.querySubs[queryID] = &querySubscription{
+		queryID:   queryID,
+		sub:       sub,
+		node:      node,
+		cacheable: topicOnlyFields(node),
+		rawQuery:  query,
+	}
+	return nil
+}
+
+// publishToQuerySubs fans msg out to every query subscription whose AST
+// matches it, in addition to plain topic subscribers.
+func (b *Broker) publishToQuerySubs(msg *Message) {
+	b.mu.RLock()
+	subs := make([]*querySubscription, 0, len(b.querySubs))
+	for _, qs := range b.querySubs {
+		subs = append(subs, qs)
+	}
+	cache := b.matchCache
+	b.mu.RUnlock()
+
+	for _, qs := range subs {
+		matched := false
+		if qs.cacheable && cache != nil {
+			if cached, found := cache.Get(qs.queryID, msg.Topic); found {
+				matched = cached
+			} else {
+				matched = qs.node.Match(msg)
+				cache.Set(qs.queryID, msg.Topic, matched)
+			}
+		} else {
+			matched = qs.node.Match(msg)
+		}
+		if matched {
+			go qs.sub.Signal(msg)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Cursor starting points understood by Broker.SubscribeFrom. Any
+// non-negative value is treated as an explicit offset instead.
+const (
+	FromBeginning int64 = -1
+	FromLatest    int64 = -2
+)
+
+// loggedMessage is one entry in a TopicLog.
+type loggedMessage struct {
+	Offset  int64
+	Message *Message
+}
+
+// liveTailer is a subscriber currently following a TopicLog, along with the
+// offset of the next message it hasn't seen yet.
+type liveTailer struct {
+	sub    *Subscriber
+	cursor int64
+}
+
+// TopicLog is an append-only, in-memory ring buffer of every message
+// published to one topic, tagged with a monotonically increasing Offset.
+// It turns the broker from fire-and-forget into something that supports
+// replay: a subscriber can start from the beginning, the latest offset, or
+// anywhere in between.
+//
+// WHY sync.Cond instead of a goroutine-per-publish: the original broker
+// spawned a fresh goroutine per subscriber on every single Publish call.
+// For a log-backed topic that doesn't scale with subscriber count, so
+// instead exactly one dispatcher goroutine per topic blocks on l.cond and
+// wakes whenever Append calls Broadcast, then fans the new messages out to
+// every live tailer itself.
+type TopicLog struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	messages    []loggedMessage
+	nextOffset  int64
+	maxMessages int
+	maxAge      time.Duration
+	tailers     map[string]*liveTailer
+}
+
+// NewTopicLog creates a log with the given retention. maxMessages <= 0
+// means unbounded count; maxAge <= 0 means entries never age out.
+func NewTopicLog(maxMessages int, maxAge time.Duration) *TopicLog {
+	l := &TopicLog{
+		maxMessages: maxMessages,
+		maxAge:      maxAge,
+		tailers:     make(map[string]*liveTailer),
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Append records msg at the next offset, trims anything retention says to
+// drop, and wakes the dispatcher goroutine to fan it out to live tailers.
+func (l *TopicLog) Append(msg *Message) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	offset := l.nextOffset
+	l.nextOffset++
+	l.messages = append(l.messages, loggedMessage{Offset: offset, Message: msg})
+	l.trimLocked()
+	l.cond.Broadcast()
+	return offset
+}
+
+func (l *TopicLog) trimLocked() {
+	if l.maxAge > 0 {
+		now := time.Now()
+		cut := 0
+		for cut < len(l.messages) && now.Sub(l.messages[cut].Message.Timestamp) > l.maxAge {
+			cut++
+		}
+		if cut > 0 {
+			l.messages = l.messages[cut:]
+		}
+	}
+	if l.maxMessages > 0 && len(l.messages) > l.maxMessages {
+		excess := len(l.messages) - l.maxMessages
+		l.messages = l.messages[excess:]
+	}
+}
+
+// EarliestOffset is the oldest offset retention hasn't trimmed yet. If the
+// log is empty, it equals the offset the next Append will use.
+func (l *TopicLog) EarliestOffset() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.messages) == 0 {
+		return l.nextOffset
+	}
+	return l.messages[0].Offset
+}
+
+// LatestOffset is the offset of the most recently appended message still
+// retained, or -1 if the log has never had anything appended.
+func (l *TopicLog) LatestOffset() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.nextOffset - 1
+}
+
+// fromLocked returns every retained message at or after startOffset. Caller
+// must hold l.mu.
+func (l *TopicLog) fromLocked(startOffset int64) []loggedMessage {
+	var out []loggedMessage
+	for _, lm := range l.messages {
+		if lm.Offset >= startOffset {
+			out = append(out, lm)
+		}
+	}
+	return out
+}
+
+// subscribeFrom registers sub as a live tailer of this log starting at
+// startOffset, replaying retained history up to "now" before returning -
+// the caller keeps receiving live updates afterward via the dispatcher.
+func (l *TopicLog) subscribeFrom(sub *Subscriber, startOffset int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	backlog := l.fromLocked(startOffset)
+	cursor := startOffset
+	for _, lm := range backlog {
+		sub.Signal(lm.Message)
+		cursor = lm.Offset + 1
+	}
+	l.tailers[sub.SubscriberID] = &liveTailer{sub: sub, cursor: cursor}
+}
+
+// seek repositions an existing live tailer, replaying anything retained
+// between the new offset and the log's current end immediately.
+func (l *TopicLog) seek(subscriberID string, offset int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, ok := l.tailers[subscriberID]
+	if !ok {
+		return
+	}
+	backlog := l.fromLocked(offset)
+	cursor := offset
+	for _, lm := range backlog {
+		t.sub.Signal(lm.Message)
+		cursor = lm.Offset + 1
+	}
+	t.cursor = cursor
+}
+
+func (l *TopicLog) removeTailer(subscriberID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.tailers, subscriberID)
+}
+
+// runDispatcher is the single per-topic goroutine that fans newly appended
+// messages out to every live tailer. It never returns - topic logs live as
+// long as the broker does, mirroring how topics themselves are never torn
+// down today.
+//
+// WHY drain-then-wait instead of wait-then-drain: "go log.runDispatcher()"
+// only schedules this goroutine, it doesn't run it immediately. An Append
+// (and its Broadcast) can land before this goroutine ever reaches its first
+// Wait, and Broadcast wakes only whoever's already waiting - a Wait call
+// that starts after the fact never sees it. Draining first means any
+// backlog appended before this goroutine got scheduled is still picked up
+// on its first pass, instead of being lost until the next Append.
+func (l *TopicLog) runDispatcher() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for {
+		for _, t := range l.tailers {
+			newMsgs := l.fromLocked(t.cursor)
+			for _, lm := range newMsgs {
+				t.sub.Signal(lm.Message)
+				t.cursor = lm.Offset + 1
+			}
+		}
+		l.cond.Wait()
+	}
+}
+
+// ----------------------------------------------------------
+// Broker integration
+
+// LogRetention controls how much history a lazily-created TopicLog keeps.
+type LogRetention struct {
+	MaxMessages int
+	MaxAge      time.Duration
+}
+
+// topicLog returns (creating if necessary) the log for topic, starting its
+// dispatcher goroutine the first time it's created.
+func (b *Broker) topicLog(topic string) *TopicLog {
+	b.logMu.Lock()
+	defer b.logMu.Unlock()
+	if b.logs == nil {
+		b.logs = make(map[string]*TopicLog)
+	}
+	log, ok := b.logs[topic]
+	if !ok {
+		log = NewTopicLog(b.LogRetention.MaxMessages, b.LogRetention.MaxAge)
+		b.logs[topic] = log
+		go log.runDispatcher()
+	}
+	return log
+}
+
+// appendToLog records message in its topic's log, creating that log (and
+// its dispatcher) on the topic's first publish, so a later SubscribeFrom
+// with FromBeginning can replay everything published since.
+func (b *Broker) appendToLog(message *Message) {
+	b.topicLog(message.Topic).Append(message)
+}
+
+// SubscribeFrom subscribes sub to topic starting at startOffset
+// (FromBeginning, FromLatest, or an explicit offset), replaying retained
+// history before switching to live tail.
+func (b *Broker) SubscribeFrom(topic string, sub *Subscriber, startOffset int64) error {
+	log := b.topicLog(topic)
+
+	from := startOffset
+	switch startOffset {
+	case FromBeginning:
+		from = log.EarliestOffset()
+	case FromLatest:
+		from = log.LatestOffset() + 1
+	}
+
+	log.subscribeFrom(sub, from)
+	return nil
+}
+
+// LatestOffset returns topic's most recent retained offset, or -1 if
+// nothing has been logged for it yet.
+func (b *Broker) LatestOffset(topic string) int64 {
+	return b.topicLog(topic).LatestOffset()
+}
+
+// EarliestOffset returns the oldest offset topic's log still retains.
+func (b *Broker) EarliestOffset(topic string) int64 {
+	return b.topicLog(topic).EarliestOffset()
+}
+
+// Seek repositions a live subscriber within every topic log it's tailing,
+// replaying anything retained between the new offset and the log's current
+// end immediately.
+func (b *Broker) Seek(sub *Subscriber, offset int64) {
+	b.logMu.Lock()
+	logs := make([]*TopicLog, 0, len(b.logs))
+	for _, log := range b.logs {
+		logs = append(logs, log)
+	}
+	b.logMu.Unlock()
+
+	for _, log := range logs {
+		log.seek(sub.SubscriberID, offset)
+	}
+}
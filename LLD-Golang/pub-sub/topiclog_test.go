@@ -0,0 +1,140 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// drainOffsets reads exactly n messages off sub.Messages (failing the test
+// on timeout) and returns their bodies in delivery order.
+func drainOffsets(t *testing.T, sub *Subscriber, n int) []string {
+	t.Helper()
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-sub.Messages:
+			out = append(out, msg.Body)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for message %d/%d", i+1, n)
+		}
+	}
+	return out
+}
+
+// TestSubscribeFromBeginningReplaysFullLog proves a subscriber that joins
+// after messages were already published can still replay every one of them
+// from offset 0, in order - the cursor/offset replay behavior the request
+// asks for.
+func TestSubscribeFromBeginningReplaysFullLog(t *testing.T) {
+	b := NewBroker()
+	const topic = "orders"
+
+	for _, body := range []string{"order-1", "order-2", "order-3"} {
+		if err := b.Publish(topic, body); err != nil {
+			t.Fatalf("Publish(%s): %v", body, err)
+		}
+	}
+
+	_, lateSub := NewSubscriber(10, nil)
+	if err := b.SubscribeFrom(topic, lateSub, FromBeginning); err != nil {
+		t.Fatalf("SubscribeFrom: %v", err)
+	}
+
+	got := drainOffsets(t, lateSub, 3)
+	want := []string{"order-1", "order-2", "order-3"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("message %d = %q, want %q (got %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+// TestSubscribeFromExplicitOffsetSkipsEarlierMessages proves an explicit
+// startOffset replays only messages at or after it, not the whole log.
+func TestSubscribeFromExplicitOffsetSkipsEarlierMessages(t *testing.T) {
+	b := NewBroker()
+	const topic = "orders"
+
+	// Publish three messages before anyone subscribes, to populate the log.
+	_, warmup := NewSubscriber(10, nil)
+	if err := b.SubscribeFrom(topic, warmup, FromBeginning); err != nil {
+		t.Fatalf("SubscribeFrom(warmup): %v", err)
+	}
+	for _, body := range []string{"order-1", "order-2", "order-3"} {
+		if err := b.Publish(topic, body); err != nil {
+			t.Fatalf("Publish(%s): %v", body, err)
+		}
+	}
+	drainOffsets(t, warmup, 3)
+
+	// A subscriber starting at offset 1 should only see order-2 and order-3.
+	_, lateSub := NewSubscriber(10, nil)
+	if err := b.SubscribeFrom(topic, lateSub, 1); err != nil {
+		t.Fatalf("SubscribeFrom(offset 1): %v", err)
+	}
+	got := drainOffsets(t, lateSub, 2)
+	want := []string{"order-2", "order-3"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("message %d = %q, want %q (got %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+// TestSubscribeFromLatestSkipsHistoryThenTailsLive proves FromLatest skips
+// everything already in the log and only delivers messages published after
+// the subscription is created.
+func TestSubscribeFromLatestSkipsHistoryThenTailsLive(t *testing.T) {
+	b := NewBroker()
+	const topic = "orders"
+
+	_, warmup := NewSubscriber(10, nil)
+	if err := b.SubscribeFrom(topic, warmup, FromBeginning); err != nil {
+		t.Fatalf("SubscribeFrom(warmup): %v", err)
+	}
+	if err := b.Publish(topic, "old-message"); err != nil {
+		t.Fatalf("Publish(old-message): %v", err)
+	}
+	drainOffsets(t, warmup, 1)
+
+	_, lateSub := NewSubscriber(10, nil)
+	if err := b.SubscribeFrom(topic, lateSub, FromLatest); err != nil {
+		t.Fatalf("SubscribeFrom(FromLatest): %v", err)
+	}
+	if err := b.Publish(topic, "new-message"); err != nil {
+		t.Fatalf("Publish(new-message): %v", err)
+	}
+
+	got := drainOffsets(t, lateSub, 1)
+	if got[0] != "new-message" {
+		t.Fatalf("message = %q, want %q (history should not have replayed)", got[0], "new-message")
+	}
+}
+
+// TestSeekRepositionsLiveSubscriber proves Seek can rewind an already-live
+// subscriber and have it replay from the new offset.
+func TestSeekRepositionsLiveSubscriber(t *testing.T) {
+	b := NewBroker()
+	const topic = "orders"
+
+	_, sub := NewSubscriber(10, nil)
+	if err := b.SubscribeFrom(topic, sub, FromBeginning); err != nil {
+		t.Fatalf("SubscribeFrom: %v", err)
+	}
+	for _, body := range []string{"order-1", "order-2", "order-3"} {
+		if err := b.Publish(topic, body); err != nil {
+			t.Fatalf("Publish(%s): %v", body, err)
+		}
+	}
+	drainOffsets(t, sub, 3)
+
+	// Rewind to offset 0: the whole log should replay again.
+	b.Seek(sub, 0)
+	got := drainOffsets(t, sub, 3)
+	want := []string{"order-1", "order-2", "order-3"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("replayed message %d = %q, want %q (got %v)", i, got[i], want[i], got)
+		}
+	}
+}
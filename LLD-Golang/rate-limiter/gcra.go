@@ -0,0 +1,76 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// GCRARateLimiter implements the Generic Cell Rate Algorithm: a
+// token-bucket equivalent that stores a single time.Time - the
+// theoretical arrival time (TAT) - per user instead of a token count.
+// WHY GCRA over the other four: O(1) memory per user with no background
+// goroutine (unlike LeakyBucketRateLimiter's ticker) and no growing
+// timestamp slice (unlike SlidingWindowRateLimiter).
+type GCRARateLimiter struct {
+	emissionInterval time.Duration // T: minimum time between admitted requests at the target rate
+	burstTolerance   time.Duration // tau: how far into the future TAT may run before a request is rejected
+
+	mu  sync.Mutex
+	tat time.Time // zero value admits the first burst of maxRequests requests
+}
+
+// NewGCRARateLimiter derives T = window/maxRequests and uses window
+// itself as the burst tolerance tau.
+func NewGCRARateLimiter(maxRequests int, window time.Duration) *GCRARateLimiter {
+	return &GCRARateLimiter{
+		emissionInterval: window / time.Duration(maxRequests),
+		burstTolerance:   window,
+	}
+}
+
+func (g *GCRARateLimiter) AllowRequest(userID string) bool {
+	ok, _, _ := g.Reserve(userID)
+	return ok
+}
+
+func (g *GCRARateLimiter) Reserve(_ string) (bool, Commit, Rollback) {
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tat := g.tat
+	if now.After(tat) {
+		tat = now
+	}
+	newTAT := tat.Add(g.emissionInterval)
+
+	if newTAT.Sub(now) > g.burstTolerance {
+		return false, noop, noop
+	}
+
+	previousTAT := g.tat
+	g.tat = newTAT
+
+	rollback := func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		// Only undo if nothing else has advanced tat since this
+		// reservation - otherwise we'd erase someone else's request too.
+		if g.tat.Equal(newTAT) {
+			g.tat = previousTAT
+		}
+	}
+	return true, noop, rollback
+}
+
+// RetryAfter reports how long a caller should wait before retrying, given
+// the limiter's current state: tat + T - tau - now, i.e. until the next
+// request's tat' would fall back within tau. It's only meaningful
+// (positive) right after a rejection; none of the other four limiters can
+// compute this as cheaply, since they'd need to reconstruct it from a
+// token count or timestamp slice.
+func (g *GCRARateLimiter) RetryAfter(_ string) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return time.Until(g.tat.Add(g.emissionInterval - g.burstTolerance))
+}
@@ -0,0 +1,118 @@
+package main
+
+import "time"
+
+// HierarchicalRateLimiter composes several RateLimiter tiers into one
+// decision: a request is admitted only if every tier reserves capacity
+// for it. WHY Reserve/Commit/Rollback instead of calling AllowRequest on
+// each tier directly: AllowRequest's consumption is final the instant it
+// returns true, so if tier 2 denies after tier 1 already admitted, tier
+// 1's capacity would be spent for nothing. Reserve defers that finality
+// until every tier has agreed.
+type HierarchicalRateLimiter struct {
+	tiers []RateLimiter
+}
+
+// NewHierarchicalRateLimiter composes tiers in the order they should be
+// checked - e.g. a global limiter first, then per-endpoint, then per-user.
+func NewHierarchicalRateLimiter(tiers ...RateLimiter) *HierarchicalRateLimiter {
+	return &HierarchicalRateLimiter{tiers: tiers}
+}
+
+func (h *HierarchicalRateLimiter) AllowRequest(userID string) bool {
+	commits := make([]Commit, 0, len(h.tiers))
+	rollbacks := make([]Rollback, 0, len(h.tiers))
+
+	for _, tier := range h.tiers {
+		ok, commit, rollback := tier.Reserve(userID)
+		if !ok {
+			for i := len(rollbacks) - 1; i >= 0; i-- {
+				rollbacks[i]()
+			}
+			return false
+		}
+		commits = append(commits, commit)
+		rollbacks = append(rollbacks, rollback)
+	}
+
+	for _, commit := range commits {
+		commit()
+	}
+	return true
+}
+
+// Reserve lets a HierarchicalRateLimiter itself be used as one tier of a
+// larger hierarchy: granting means every inner tier granted, and rolling
+// back means rolling every inner tier back.
+func (h *HierarchicalRateLimiter) Reserve(userID string) (bool, Commit, Rollback) {
+	commits := make([]Commit, 0, len(h.tiers))
+	rollbacks := make([]Rollback, 0, len(h.tiers))
+
+	for _, tier := range h.tiers {
+		ok, commit, rollback := tier.Reserve(userID)
+		if !ok {
+			for i := len(rollbacks) - 1; i >= 0; i-- {
+				rollbacks[i]()
+			}
+			return false, noop, noop
+		}
+		commits = append(commits, commit)
+		rollbacks = append(rollbacks, rollback)
+	}
+
+	commit := func() {
+		for _, c := range commits {
+			c()
+		}
+	}
+	rollback := func() {
+		for i := len(rollbacks) - 1; i >= 0; i-- {
+			rollbacks[i]()
+		}
+	}
+	return true, commit, rollback
+}
+
+// RegisterGlobal configures the optional top-tier limiter that guards
+// aggregate throughput across every user and endpoint. Pass algorithm ==
+// "" (or never call this) to leave hierarchical checks without a global
+// tier.
+func (s *RateLimiterService) RegisterGlobal(algorithm string, maxRequests int, window time.Duration) error {
+	var limiter RateLimiter
+	if algorithm != "" {
+		factory := &RateLimiterFactory{}
+		created, err := factory.CreateRateLimiter(algorithm, maxRequests, window)
+		if err != nil {
+			return err
+		}
+		limiter = created
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.globalLimiter.(*LeakyBucketRateLimiter); ok {
+		old.Close()
+	}
+	s.globalLimiter = limiter
+	return nil
+}
+
+// RegisterEndpoint configures the per-endpoint tier AllowRequest consults
+// when called with a non-empty endpointID.
+func (s *RateLimiterService) RegisterEndpoint(endpointID, algorithm string, maxRequests int, window time.Duration) error {
+	factory := &RateLimiterFactory{}
+	limiter, err := factory.CreateRateLimiter(algorithm, maxRequests, window)
+	if err != nil {
+		return err
+	}
+
+	s.endpointsMu.Lock()
+	defer s.endpointsMu.Unlock()
+	if old, ok := s.endpoints[endpointID]; ok {
+		if lb, ok := old.(*LeakyBucketRateLimiter); ok {
+			lb.Close()
+		}
+	}
+	s.endpoints[endpointID] = limiter
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import "time"
+
+// defaultJailThreshold is how many consecutive denials trigger an
+// automatic jailing when no explicit policy is configured.
+const defaultJailThreshold = 5
+
+// defaultBaseJailDuration is the length of a user's first automatic jail
+// sentence; each subsequent offense doubles it.
+const defaultBaseJailDuration = 30 * time.Second
+
+// maxJailDuration caps the exponential backoff so a user who offends
+// forever doesn't end up jailed for, effectively, eternity.
+const maxJailDuration = 24 * time.Hour
+
+// JailInfo is the point-in-time snapshot returned by JailStatus.
+type JailInfo struct {
+	Jailed      bool
+	JailCount   int
+	FirstJailAt time.Time
+	JailEnd     time.Time
+}
+
+// jailEntry is the per-user jail record. Mirrors the jails/jail_end_time
+// pattern used by validator-jailing schemes: JailCount tracks how many
+// times this user has been sent to jail, so repeat offenses can be
+// punished with an exponentially longer sentence.
+type jailEntry struct {
+	count       int
+	firstJailAt time.Time
+	jailEnd     time.Time
+}
+
+// jailDuration returns the sentence length for a user's (count+1)-th
+// offense: baseJailDuration doubled per prior offense, capped at
+// maxJailDuration.
+func (s *RateLimiterService) jailDuration(priorOffenses int) time.Duration {
+	d := s.baseJailDuration
+	for i := 0; i < priorOffenses; i++ {
+		d *= 2
+		if d >= maxJailDuration {
+			return maxJailDuration
+		}
+	}
+	return d
+}
+
+// Jail places userID in jail for duration, starting now. Calling Jail on
+// an already-jailed user extends their sentence and counts as another
+// offense, so a manual Jail call composes with the automatic backoff.
+func (s *RateLimiterService) Jail(userID string, duration time.Duration) {
+	now := time.Now()
+	s.jailMu.Lock()
+	defer s.jailMu.Unlock()
+
+	entry, ok := s.jails[userID]
+	if !ok {
+		entry = &jailEntry{firstJailAt: now}
+		s.jails[userID] = entry
+	}
+	entry.count++
+	entry.jailEnd = now.Add(duration)
+}
+
+// Unjail releases userID immediately, regardless of how much of their
+// sentence remains. It does not reset JailCount, so a future offense still
+// backs off from where this user left off.
+func (s *RateLimiterService) Unjail(userID string) {
+	s.jailMu.Lock()
+	defer s.jailMu.Unlock()
+	if entry, ok := s.jails[userID]; ok {
+		entry.jailEnd = time.Time{}
+	}
+}
+
+// JailStatus reports userID's current jail state.
+func (s *RateLimiterService) JailStatus(userID string) JailInfo {
+	s.jailMu.Lock()
+	defer s.jailMu.Unlock()
+
+	entry, ok := s.jails[userID]
+	if !ok {
+		return JailInfo{}
+	}
+	return JailInfo{
+		Jailed:      time.Now().Before(entry.jailEnd),
+		JailCount:   entry.count,
+		FirstJailAt: entry.firstJailAt,
+		JailEnd:     entry.jailEnd,
+	}
+}
+
+// isJailed reports whether userID is currently serving a jail sentence.
+func (s *RateLimiterService) isJailed(userID string) bool {
+	s.jailMu.Lock()
+	defer s.jailMu.Unlock()
+	entry, ok := s.jails[userID]
+	return ok && time.Now().Before(entry.jailEnd)
+}
+
+// recordDecision updates userID's consecutive-denial streak and, once it
+// crosses denialThreshold, automatically jails them. WHY a streak instead
+// of a denial-rate window: AllowRequest already has per-user mutual
+// exclusion via the underlying limiter, so a simple consecutive counter is
+// cheap and resets itself the moment the user is well-behaved again.
+func (s *RateLimiterService) recordDecision(userID string, allowed bool) {
+	s.streakMu.Lock()
+	defer s.streakMu.Unlock()
+
+	if allowed {
+		s.denialStreak[userID] = 0
+		return
+	}
+
+	s.denialStreak[userID]++
+	if s.denialStreak[userID] < s.denialThreshold {
+		return
+	}
+	s.denialStreak[userID] = 0
+
+	priorOffenses := s.JailStatus(userID).JailCount
+	s.Jail(userID, s.jailDuration(priorOffenses))
+}
@@ -12,8 +12,32 @@ import (
 /***************
  * Strategy API
  ***************/
+
+// Commit finalizes a reservation made by RateLimiter.Reserve. Every
+// concrete limiter's Commit is a no-op today, since Reserve already
+// consumes capacity up front - it exists so a caller driving a multi-tier
+// decision (see HierarchicalRateLimiter) has a symmetric call to make once
+// every tier has agreed, without needing to know that detail.
+type Commit func()
+
+// Rollback undoes a reservation made by RateLimiter.Reserve, refunding
+// whatever capacity it consumed.
+type Rollback func()
+
+// noop is the Commit/Rollback returned when a reservation was never
+// granted in the first place.
+func noop() {}
+
 type RateLimiter interface {
 	AllowRequest(userID string) bool
+
+	// Reserve speculatively consumes capacity for userID and reports
+	// whether it was granted, plus a Commit/Rollback pair. A caller that
+	// only needs a single tier can just call AllowRequest; a caller
+	// coordinating several tiers (HierarchicalRateLimiter) calls Reserve
+	// on each in turn so a later tier's denial can roll back the capacity
+	// already spent in earlier ones.
+	Reserve(userID string) (ok bool, commit Commit, rollback Rollback)
 }
 
 /**************************
@@ -36,7 +60,12 @@ func NewFixedWindowRateLimiter(maxRequests int, windowSize time.Duration) *Fixed
 	}
 }
 
-func (fw *FixedWindowRateLimiter) AllowRequest(_ string) bool {
+func (fw *FixedWindowRateLimiter) AllowRequest(userID string) bool {
+	ok, _, _ := fw.Reserve(userID)
+	return ok
+}
+
+func (fw *FixedWindowRateLimiter) Reserve(_ string) (bool, Commit, Rollback) {
 	now := time.Now()
 	fw.mu.Lock()
 	defer fw.mu.Unlock()
@@ -45,11 +74,22 @@ func (fw *FixedWindowRateLimiter) AllowRequest(_ string) bool {
 		fw.windowStart = now
 		fw.count = 0
 	}
-	if fw.count < fw.maxRequests {
-		fw.count++
-		return true
+	if fw.count >= fw.maxRequests {
+		return false, noop, noop
 	}
-	return false
+	fw.count++
+
+	reservedWindow := fw.windowStart
+	rollback := func() {
+		fw.mu.Lock()
+		defer fw.mu.Unlock()
+		// If the window has since rolled over, count already reset to 0
+		// on its own - refunding now would just double-credit it.
+		if fw.windowStart.Equal(reservedWindow) && fw.count > 0 {
+			fw.count--
+		}
+	}
+	return true, noop, rollback
 }
 
 /**************************
@@ -71,7 +111,12 @@ func NewSlidingWindowRateLimiter(maxRequests int, windowSize time.Duration) *Sli
 	}
 }
 
-func (s *SlidingWindowRateLimiter) AllowRequest(_ string) bool {
+func (s *SlidingWindowRateLimiter) AllowRequest(userID string) bool {
+	ok, _, _ := s.Reserve(userID)
+	return ok
+}
+
+func (s *SlidingWindowRateLimiter) Reserve(_ string) (bool, Commit, Rollback) {
 	now := time.Now()
 	cutoff := now.Add(-s.windowSize)
 
@@ -87,11 +132,23 @@ func (s *SlidingWindowRateLimiter) AllowRequest(_ string) bool {
 		s.timestamps = append([]time.Time{}, s.timestamps[i:]...)
 	}
 
-	if len(s.timestamps) < s.maxRequests {
-		s.timestamps = append(s.timestamps, now)
-		return true
+	if len(s.timestamps) >= s.maxRequests {
+		return false, noop, noop
+	}
+	s.timestamps = append(s.timestamps, now)
+	reserved := now
+
+	rollback := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, ts := range s.timestamps {
+			if ts.Equal(reserved) {
+				s.timestamps = append(s.timestamps[:i], s.timestamps[i+1:]...)
+				return
+			}
+		}
 	}
-	return false
+	return true, noop, rollback
 }
 
 /*************************
@@ -124,17 +181,28 @@ func (t *TokenBucketRateLimiter) refill(now time.Time) {
 	t.lastRefill = now
 }
 
-func (t *TokenBucketRateLimiter) AllowRequest(_ string) bool {
+func (t *TokenBucketRateLimiter) AllowRequest(userID string) bool {
+	ok, _, _ := t.Reserve(userID)
+	return ok
+}
+
+func (t *TokenBucketRateLimiter) Reserve(_ string) (bool, Commit, Rollback) {
 	now := time.Now()
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	t.refill(now)
-	if t.tokens >= 1.0 {
-		t.tokens -= 1.0
-		return true
+	if t.tokens < 1.0 {
+		return false, noop, noop
 	}
-	return false
+	t.tokens -= 1.0
+
+	rollback := func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.tokens = math.Min(float64(t.capacity), t.tokens+1.0)
+	}
+	return true, noop, rollback
 }
 
 /************************
@@ -180,14 +248,27 @@ func (l *LeakyBucketRateLimiter) leakLoop() {
 	}
 }
 
-func (l *LeakyBucketRateLimiter) AllowRequest(_ string) bool {
+func (l *LeakyBucketRateLimiter) AllowRequest(userID string) bool {
+	ok, _, _ := l.Reserve(userID)
+	return ok
+}
+
+func (l *LeakyBucketRateLimiter) Reserve(_ string) (bool, Commit, Rollback) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if l.q < l.capacity {
-		l.q++
-		return true
+	if l.q >= l.capacity {
+		return false, noop, noop
 	}
-	return false
+	l.q++
+
+	rollback := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.q > 0 {
+			l.q--
+		}
+	}
+	return true, noop, rollback
 }
 
 func (l *LeakyBucketRateLimiter) Close() {
@@ -225,6 +306,11 @@ func (f *RateLimiterFactory) CreateRateLimiter(kind string, maxRequests int, win
 			leakEvery = time.Second
 		}
 		return NewLeakyBucketRateLimiter(maxRequests, leakEvery), nil
+	case "GCRA":
+		if maxRequests <= 0 || window <= 0 {
+			return nil, errors.New("maxRequests and window must be > 0 for GCRA")
+		}
+		return NewGCRARateLimiter(maxRequests, window), nil
 	default:
 		return nil, fmt.Errorf("unsupported type: %s", kind)
 	}
@@ -234,13 +320,47 @@ func (f *RateLimiterFactory) CreateRateLimiter(kind string, maxRequests int, win
  * Service (Duration in API)
  ****************************/
 type RateLimiterService struct {
-	mu       sync.RWMutex
-	limiters map[string]RateLimiter
+	mu            sync.RWMutex
+	limiters      map[string]RateLimiter
+	globalLimiter RateLimiter // optional top tier guarding aggregate throughput - see hierarchical.go
+
+	endpointsMu sync.RWMutex
+	endpoints   map[string]RateLimiter // optional per-endpoint tier - see hierarchical.go
+
+	// Jail subsystem - see jail.go. jailMu guards jails, streakMu guards
+	// denialStreak; kept separate from mu so jail bookkeeping never
+	// contends with limiter registration/lookup.
+	jailMu           sync.Mutex
+	jails            map[string]*jailEntry
+	streakMu         sync.Mutex
+	denialStreak     map[string]int
+	denialThreshold  int
+	baseJailDuration time.Duration
+
+	// Observability - see metrics.go. statsMu guards userCounters; kept
+	// separate from mu for the same reason as the jail mutexes above.
+	Metrics      Metrics
+	statsMu      sync.Mutex
+	userCounters map[string]*userCounterPair
 }
 
 func NewRateLimiterService() *RateLimiterService {
+	return NewRateLimiterServiceWithJailPolicy(defaultJailThreshold, defaultBaseJailDuration)
+}
+
+// NewRateLimiterServiceWithJailPolicy is NewRateLimiterService with an
+// explicit auto-jail policy: a user denied `denialThreshold` times in a
+// row is jailed for baseJailDuration, doubling on each repeat offense.
+func NewRateLimiterServiceWithJailPolicy(denialThreshold int, baseJailDuration time.Duration) *RateLimiterService {
 	return &RateLimiterService{
-		limiters: make(map[string]RateLimiter),
+		limiters:         make(map[string]RateLimiter),
+		endpoints:        make(map[string]RateLimiter),
+		jails:            make(map[string]*jailEntry),
+		denialStreak:     make(map[string]int),
+		denialThreshold:  denialThreshold,
+		baseJailDuration: baseJailDuration,
+		Metrics:          NoopMetrics{},
+		userCounters:     make(map[string]*userCounterPair),
 	}
 }
 
@@ -260,17 +380,69 @@ func (s *RateLimiterService) RegisterUser(userID string, algorithm string, maxRe
 		}
 	}
 	s.limiters[userID] = limiter
+	s.Metrics.OnRegister(userID, strings.ToUpper(algorithm))
 	return nil
 }
 
-func (s *RateLimiterService) AllowRequest(userID string) (bool, error) {
+// UnregisterUser removes userID's limiter (stopping its leaky-bucket
+// ticker if it has one) and fires the OnUnregister metrics hook. Its
+// cumulative Stats counters are dropped along with it.
+func (s *RateLimiterService) UnregisterUser(userID string) {
+	s.mu.Lock()
+	if old, ok := s.limiters[userID]; ok {
+		if lb, ok := old.(*LeakyBucketRateLimiter); ok {
+			lb.Close()
+		}
+		delete(s.limiters, userID)
+	}
+	s.mu.Unlock()
+
+	s.statsMu.Lock()
+	delete(s.userCounters, userID)
+	s.statsMu.Unlock()
+
+	s.Metrics.OnUnregister(userID)
+}
+
+// AllowRequest admits a request only if every applicable tier agrees: the
+// optional global limiter, the optional per-endpoint limiter for
+// endpointID (pass "" to skip it), and the per-user limiter. See
+// hierarchical.go for how those tiers are composed atomically.
+func (s *RateLimiterService) AllowRequest(userID, endpointID string) (bool, error) {
 	s.mu.RLock()
-	limiter, ok := s.limiters[userID]
+	userLimiter, ok := s.limiters[userID]
 	s.mu.RUnlock()
 	if !ok {
 		return false, fmt.Errorf("user %s not registered", userID)
 	}
-	return limiter.AllowRequest(userID), nil
+
+	// A jailed user is denied without even consulting the limiter - the
+	// point of jail is to stop spending limiter/backend work on someone
+	// who's already shown they won't stay within bounds.
+	if s.isJailed(userID) {
+		s.recordStats(userID, limiterAlgo(userLimiter), false)
+		return false, nil
+	}
+
+	tiers := make([]RateLimiter, 0, 3)
+	if s.globalLimiter != nil {
+		tiers = append(tiers, s.globalLimiter)
+	}
+	if endpointID != "" {
+		s.endpointsMu.RLock()
+		endpointLimiter, ok := s.endpoints[endpointID]
+		s.endpointsMu.RUnlock()
+		if !ok {
+			return false, fmt.Errorf("endpoint %s not registered", endpointID)
+		}
+		tiers = append(tiers, endpointLimiter)
+	}
+	tiers = append(tiers, userLimiter)
+
+	allowed := NewHierarchicalRateLimiter(tiers...).AllowRequest(userID)
+	s.recordDecision(userID, allowed)
+	s.recordStats(userID, limiterAlgo(userLimiter), allowed)
+	return allowed, nil
 }
 
 func (s *RateLimiterService) CloseAll() {
@@ -296,11 +468,71 @@ func main() {
 	_ = svc.RegisterUser("user_4", "LEAKY_BUCKET", 3, 4*time.Second)  // leak ~every 1.33s
 
 	for i := 0; i < 7; i++ {
-		a1, _ := svc.AllowRequest("user_1")
-		a2, _ := svc.AllowRequest("user_2")
-		a3, _ := svc.AllowRequest("user_3")
-		a4, _ := svc.AllowRequest("user_4")
+		a1, _ := svc.AllowRequest("user_1", "")
+		a2, _ := svc.AllowRequest("user_2", "")
+		a3, _ := svc.AllowRequest("user_3", "")
+		a4, _ := svc.AllowRequest("user_4", "")
 		fmt.Printf("Tick %d | u1:%v u2:%v u3:%v u4:%v\n", i+1, a1, a2, a3, a4)
 		time.Sleep(1 * time.Second)
 	}
+
+	// Jail: a user who gets denied repeatedly in a row is automatically
+	// locked out, even once they'd otherwise be allowed again.
+	fmt.Println("\n=== Jail Subsystem ===")
+	jailSvc := NewRateLimiterServiceWithJailPolicy(3, 2*time.Second)
+	defer jailSvc.CloseAll()
+	_ = jailSvc.RegisterUser("offender", "FIXED_WINDOW", 1, time.Minute)
+	for i := 0; i < 4; i++ {
+		allowed, _ := jailSvc.AllowRequest("offender", "")
+		fmt.Printf("offender request %d: allowed=%v\n", i+1, allowed)
+	}
+	status := jailSvc.JailStatus("offender")
+	fmt.Printf("offender jail status: jailed=%v count=%d end=%v\n", status.Jailed, status.JailCount, status.JailEnd)
+	jailSvc.Unjail("offender")
+	fmt.Printf("offender jail status after Unjail: jailed=%v\n", jailSvc.JailStatus("offender").Jailed)
+
+	// Hierarchical rate limiting: a global cap, a per-endpoint cap, and the
+	// existing per-user cap must all agree before a request is admitted.
+	fmt.Println("\n=== Hierarchical Rate Limiter ===")
+	hierSvc := NewRateLimiterService()
+	defer hierSvc.CloseAll()
+	_ = hierSvc.RegisterGlobal("FIXED_WINDOW", 3, 10*time.Second)
+	_ = hierSvc.RegisterEndpoint("/checkout", "FIXED_WINDOW", 2, 10*time.Second)
+	_ = hierSvc.RegisterUser("user_5", "FIXED_WINDOW", 5, 10*time.Second)
+	for i := 0; i < 4; i++ {
+		allowed, err := hierSvc.AllowRequest("user_5", "/checkout")
+		fmt.Printf("user_5 -> /checkout request %d: allowed=%v err=%v\n", i+1, allowed, err)
+	}
+
+	// GCRA: same admission behavior as token bucket, but O(1) memory (one
+	// timestamp) and a cheap RetryAfter for a rejected caller.
+	fmt.Println("\n=== GCRA ===")
+	gcra := NewGCRARateLimiter(3, 3*time.Second) // burst of 3, then ~1/sec
+	for i := 0; i < 5; i++ {
+		allowed := gcra.AllowRequest("user_6")
+		if allowed {
+			fmt.Printf("GCRA request %d: allowed\n", i+1)
+		} else {
+			fmt.Printf("GCRA request %d: denied, retry after %v\n", i+1, gcra.RetryAfter("user_6"))
+		}
+	}
+
+	// Observability: a PrometheusMetrics adapter records every allow/deny
+	// decision, Stats reports per-user counters plus limiter utilization,
+	// and DebugHandler would let an operator curl /debug/ratelimit.
+	fmt.Println("\n=== Observability ===")
+	obsSvc := NewRateLimiterService()
+	defer obsSvc.CloseAll()
+	promMetrics := NewPrometheusMetrics()
+	obsSvc.Metrics = promMetrics
+	_ = obsSvc.RegisterUser("user_7", "TOKEN_BUCKET", 3, 6*time.Second)
+	for i := 0; i < 4; i++ {
+		allowed, _ := obsSvc.AllowRequest("user_7", "")
+		fmt.Printf("user_7 request %d: allowed=%v\n", i+1, allowed)
+	}
+	stats, _ := obsSvc.Stats("user_7")
+	fmt.Printf("user_7 stats: allowed=%d denied=%d utilization=%.2f timeToRefill=%v\n",
+		stats.Allowed, stats.Denied, stats.Utilization, stats.TimeToRefill)
+	fmt.Print(promMetrics.Render())
+	_ = obsSvc.DebugHandler() // mount at /debug/ratelimit in a real server
 }
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LimiterState is the uniform snapshot Introspect returns, so any
+// concrete limiter's internals can be inspected without reflection. Not
+// every field is meaningful for every kind - e.g. QueueDepth only means
+// something for LeakyBucketRateLimiter - so check Kind before relying on
+// one.
+type LimiterState struct {
+	Kind            string
+	Capacity        float64       // the limiter's configured capacity (maxRequests, or its token-bucket equivalent)
+	RemainingTokens float64       // capacity currently still available
+	QueueDepth      int           // leaky bucket: requests currently queued to leak
+	TimestampsCount int           // sliding window: requests currently counted in the window
+	WindowStart     time.Time     // fixed window: when the current window started
+	TimeToRefill    time.Duration // time until at least one more unit of capacity frees up
+}
+
+// Introspectable is implemented by a RateLimiter that can expose its
+// internal state for operational debugging. WHY a separate interface
+// instead of adding Introspect to RateLimiter itself: not every RateLimiter
+// (e.g. a future composite tier) can cheaply produce one uniform snapshot,
+// so callers type-assert for it - the same way Subscriber.Listen already
+// does for OverflowPolicy's optional Replay in the pub-sub package.
+type Introspectable interface {
+	Introspect() LimiterState
+}
+
+func (fw *FixedWindowRateLimiter) Introspect() LimiterState {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	ttl := fw.windowSize - time.Since(fw.windowStart)
+	if ttl < 0 {
+		ttl = 0
+	}
+	return LimiterState{
+		Kind:            "FIXED_WINDOW",
+		Capacity:        float64(fw.maxRequests),
+		RemainingTokens: float64(fw.maxRequests - fw.count),
+		WindowStart:     fw.windowStart,
+		TimeToRefill:    ttl,
+	}
+}
+
+func (s *SlidingWindowRateLimiter) Introspect() LimiterState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ttl time.Duration
+	if len(s.timestamps) > 0 {
+		ttl = time.Until(s.timestamps[0].Add(s.windowSize))
+		if ttl < 0 {
+			ttl = 0
+		}
+	}
+	return LimiterState{
+		Kind:            "SLIDING_WINDOW",
+		Capacity:        float64(s.maxRequests),
+		RemainingTokens: float64(s.maxRequests - len(s.timestamps)),
+		TimestampsCount: len(s.timestamps),
+		TimeToRefill:    ttl,
+	}
+}
+
+func (t *TokenBucketRateLimiter) Introspect() LimiterState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refill(time.Now())
+
+	var ttl time.Duration
+	if t.tokens < 1 && t.refillPerSec > 0 {
+		ttl = time.Duration((1 - t.tokens) / t.refillPerSec * float64(time.Second))
+	}
+	return LimiterState{
+		Kind:            "TOKEN_BUCKET",
+		Capacity:        float64(t.capacity),
+		RemainingTokens: t.tokens,
+		TimeToRefill:    ttl,
+	}
+}
+
+func (l *LeakyBucketRateLimiter) Introspect() LimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return LimiterState{
+		Kind:            "LEAKY_BUCKET",
+		Capacity:        float64(l.capacity),
+		RemainingTokens: float64(l.capacity - l.q),
+		QueueDepth:      l.q,
+		TimeToRefill:    l.leakEvery,
+	}
+}
+
+func (g *GCRARateLimiter) Introspect() LimiterState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	unitsInFlight := g.tat.Sub(now)
+	if unitsInFlight < 0 {
+		unitsInFlight = 0
+	}
+	capacityUnits := float64(g.burstTolerance) / float64(g.emissionInterval)
+	remainingUnits := capacityUnits - float64(unitsInFlight)/float64(g.emissionInterval)
+	if remainingUnits < 0 {
+		remainingUnits = 0
+	}
+
+	ttl := time.Until(g.tat.Add(g.emissionInterval - g.burstTolerance))
+	if ttl < 0 {
+		ttl = 0
+	}
+	return LimiterState{
+		Kind:            "GCRA",
+		Capacity:        capacityUnits,
+		RemainingTokens: remainingUnits,
+		TimeToRefill:    ttl,
+	}
+}
+
+// ----------------------------------------------------------
+// Metrics hooks
+
+// Metrics is invoked on every RateLimiterService decision, plus
+// registration changes, so a caller can wire up counters/dashboards
+// without RateLimiterService knowing anything about the metrics backend.
+type Metrics interface {
+	OnAllow(userID, algo string)
+	OnDeny(userID, algo string)
+	OnRegister(userID, algo string)
+	OnUnregister(userID string)
+}
+
+// NoopMetrics is the default Metrics: every call is a no-op.
+type NoopMetrics struct{}
+
+func (NoopMetrics) OnAllow(string, string)    {}
+func (NoopMetrics) OnDeny(string, string)     {}
+func (NoopMetrics) OnRegister(string, string) {}
+func (NoopMetrics) OnUnregister(string)       {}
+
+// PrometheusMetrics is a minimal Metrics adapter that keeps Prometheus-
+// style counters and renders them in the plain-text exposition format a
+// /metrics endpoint would serve. WHY not the real client_golang library:
+// this repo has no module manifest to pull in third-party dependencies.
+type PrometheusMetrics struct {
+	mu      sync.Mutex
+	allowed map[string]int64 // keyed by algorithm
+	denied  map[string]int64
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{allowed: make(map[string]int64), denied: make(map[string]int64)}
+}
+
+func (m *PrometheusMetrics) OnAllow(_ string, algo string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.allowed[algo]++
+}
+
+func (m *PrometheusMetrics) OnDeny(_ string, algo string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.denied[algo]++
+}
+
+func (m *PrometheusMetrics) OnRegister(string, string) {}
+func (m *PrometheusMetrics) OnUnregister(string)       {}
+
+// Render formats the accumulated counters as Prometheus text exposition.
+func (m *PrometheusMetrics) Render() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# TYPE ratelimiter_allowed_total counter\n")
+	for algo, n := range m.allowed {
+		fmt.Fprintf(&b, "ratelimiter_allowed_total{algorithm=%q} %d\n", algo, n)
+	}
+	b.WriteString("# TYPE ratelimiter_denied_total counter\n")
+	for algo, n := range m.denied {
+		fmt.Fprintf(&b, "ratelimiter_denied_total{algorithm=%q} %d\n", algo, n)
+	}
+	return b.String()
+}
+
+// ----------------------------------------------------------
+// Stats API
+
+// userCounterPair is the allowed/denied tally RateLimiterService keeps
+// for each user, independent of whatever Metrics backend is plugged in -
+// Stats needs to be able to answer even with the default NoopMetrics.
+type userCounterPair struct {
+	allowed int64
+	denied  int64
+}
+
+// UserStats is what Stats(userID) reports.
+type UserStats struct {
+	Allowed      int64
+	Denied       int64
+	Utilization  float64       // fraction of capacity currently consumed, 0-1
+	TimeToRefill time.Duration
+}
+
+// recordStats bumps userID's allowed/denied tally and fires the
+// configured Metrics hook. Called from AllowRequest after a decision.
+func (s *RateLimiterService) recordStats(userID, algo string, allowed bool) {
+	s.statsMu.Lock()
+	counters, ok := s.userCounters[userID]
+	if !ok {
+		counters = &userCounterPair{}
+		s.userCounters[userID] = counters
+	}
+	s.statsMu.Unlock()
+
+	if allowed {
+		atomic.AddInt64(&counters.allowed, 1)
+		s.Metrics.OnAllow(userID, algo)
+		return
+	}
+	atomic.AddInt64(&counters.denied, 1)
+	s.Metrics.OnDeny(userID, algo)
+}
+
+// limiterAlgo returns limiter's Kind via Introspect, or "UNKNOWN" if it
+// doesn't implement Introspectable.
+func limiterAlgo(limiter RateLimiter) string {
+	if introspectable, ok := limiter.(Introspectable); ok {
+		return introspectable.Introspect().Kind
+	}
+	return "UNKNOWN"
+}
+
+// Stats reports userID's cumulative allowed/denied counts plus their
+// underlying limiter's current utilization and time-to-refill.
+func (s *RateLimiterService) Stats(userID string) (UserStats, error) {
+	s.mu.RLock()
+	limiter, ok := s.limiters[userID]
+	s.mu.RUnlock()
+	if !ok {
+		return UserStats{}, fmt.Errorf("user %s not registered", userID)
+	}
+
+	s.statsMu.Lock()
+	counters := s.userCounters[userID]
+	s.statsMu.Unlock()
+
+	stats := UserStats{}
+	if counters != nil {
+		stats.Allowed = atomic.LoadInt64(&counters.allowed)
+		stats.Denied = atomic.LoadInt64(&counters.denied)
+	}
+
+	if introspectable, ok := limiter.(Introspectable); ok {
+		state := introspectable.Introspect()
+		stats.TimeToRefill = state.TimeToRefill
+		if state.Capacity > 0 {
+			stats.Utilization = (state.Capacity - state.RemainingTokens) / state.Capacity
+		}
+	}
+	return stats, nil
+}
+
+// DebugHandler returns an http.HandlerFunc suitable for mounting at
+// /debug/ratelimit: it dumps every registered user's LimiterState as
+// JSON, for operational debugging without reflection or a debugger.
+func (s *RateLimiterService) DebugHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.RLock()
+		dump := make(map[string]LimiterState, len(s.limiters))
+		for userID, limiter := range s.limiters {
+			if introspectable, ok := limiter.(Introspectable); ok {
+				dump[userID] = introspectable.Introspect()
+			}
+		}
+		s.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dump)
+	}
+}
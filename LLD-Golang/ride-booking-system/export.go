@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"time"
+	"unicode"
+)
+
+// Flatten recursively walks in (built from a struct/map via
+// flattenValue) and writes dotted-path leaf values into out, e.g.
+// user.name, route.distance, vehicle.base_vehicle.vehicle_id. Mirrors the identically
+// named helper in car-rental-platform's export.go - the two LLD
+// projects don't share a module, so each carries its own copy rather
+// than importing across project boundaries.
+func Flatten(prefix string, in map[string]any, out map[string]any) {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if isNil(v) {
+			continue
+		}
+		if nested, ok := v.(map[string]any); ok {
+			Flatten(key, nested, out)
+			continue
+		}
+		if nested, ok := structToMap(v); ok {
+			Flatten(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// structToMap turns a struct (or pointer/interface wrapping one) into
+// a map of its exported fields keyed by snake_case name, for Flatten
+// to recurse into. time.Time is treated as a leaf instead, since its
+// only "fields" are unexported.
+func structToMap(v any) (map[string]any, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	if _, isTime := rv.Interface().(time.Time); isTime {
+		return nil, false
+	}
+
+	rt := rv.Type()
+	out := make(map[string]any, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		out[snakeCase(field.Name)] = rv.Field(i).Interface()
+	}
+	return out, true
+}
+
+func snakeCase(name string) string {
+	var b []rune
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b = append(b, '_')
+		}
+		b = append(b, unicode.ToLower(r))
+	}
+	return string(b)
+}
+
+func flattenValue(v any) map[string]any {
+	out := make(map[string]any)
+	if top, ok := structToMap(v); ok {
+		Flatten("", top, out)
+	}
+	return out
+}
+
+// CSVExporter unions every key observed across a set of flattened
+// rows into one stable, alphabetically sorted header, then streams
+// each row against that header via encoding/csv.
+type CSVExporter struct{}
+
+func (e *CSVExporter) Export(w io.Writer, rows []map[string]any) error {
+	header := unionKeys(rows)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("export: write header: %w", err)
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, key := range header {
+			if v, ok := row[key]; ok {
+				record[i] = formatCell(v)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("export: write row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func unionKeys(rows []map[string]any) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, row := range rows {
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatCell(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// ExportBookings writes every booking matching filter as a CSV of
+// Flatten'd rows to w. Booking.State's underlying status string is
+// added under "status" explicitly, since Flatten only walks struct
+// fields - a pointer-receiver method like BookingState.Status() is
+// invisible to reflection by design.
+func (rm *ReservationManager) ExportBookings(w io.Writer, filter BookingFilter) error {
+	var rows []map[string]any
+	for _, b := range rm.ListBookings(filter) {
+		row := flattenValue(*b)
+		row["status"] = b.State.Status()
+		rows = append(rows, row)
+	}
+	return new(CSVExporter).Export(w, rows)
+}
+
+// ExportServer exposes GET /export/bookings.csv over a
+// ReservationManager, accepting the same user_id/vehicle_id/status/
+// from/to query params BookingFilter already understands.
+type ExportServer struct {
+	reservations *ReservationManager
+}
+
+func NewExportServer(reservations *ReservationManager) *ExportServer {
+	return &ExportServer{reservations: reservations}
+}
+
+func (es *ExportServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/export/bookings.csv", es.handleBookings)
+	return mux
+}
+
+func (es *ExportServer) handleBookings(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseBookingFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="bookings.csv"`)
+	if err := es.reservations.ExportBookings(w, filter); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseBookingFilter(q url.Values) (BookingFilter, error) {
+	var f BookingFilter
+	f.UserId = q.Get("user_id")
+	f.VehicleId = q.Get("vehicle_id")
+	f.Status = q.Get("status")
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, fmt.Errorf("export: invalid from: %w", err)
+		}
+		f.From = t
+	}
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, fmt.Errorf("export: invalid to: %w", err)
+		}
+		f.To = t
+	}
+	return f, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+
+	ridev1 "ride-booking-system/proto/ridev1"
+
+	"google.golang.org/grpc"
+)
+
+// RideClient is a thin typed wrapper around ridev1.RideServiceClient
+// (generated from proto/ridev1/ride.proto), for callers embedding this
+// module in a larger MaaS platform without hand-rolling the gRPC
+// plumbing themselves.
+type RideClient struct {
+	conn   *grpc.ClientConn
+	client ridev1.RideServiceClient
+}
+
+// DialRideService opens a gRPC connection to target (e.g.
+// "ride-service:443") using opts (credentials, interceptors, etc.).
+func DialRideService(target string, opts ...grpc.DialOption) (*RideClient, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &RideClient{conn: conn, client: ridev1.NewRideServiceClient(conn)}, nil
+}
+
+// NewRideClient wraps an already-established connection, e.g. one
+// returned by grpc.NewClient against a bufconn listener in tests.
+func NewRideClient(conn *grpc.ClientConn) *RideClient {
+	return &RideClient{conn: conn, client: ridev1.NewRideServiceClient(conn)}
+}
+
+func (c *RideClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *RideClient) RegisterUser(ctx context.Context, req *ridev1.RegisterUserRequest) (*ridev1.RegisterUserResponse, error) {
+	return c.client.RegisterUser(ctx, req)
+}
+
+func (c *RideClient) SearchVehicles(ctx context.Context, req *ridev1.SearchVehiclesRequest) (*ridev1.SearchVehiclesResponse, error) {
+	return c.client.SearchVehicles(ctx, req)
+}
+
+func (c *RideClient) CreateLease(ctx context.Context, req *ridev1.CreateLeaseRequest) (*ridev1.CreateLeaseResponse, error) {
+	return c.client.CreateLease(ctx, req)
+}
+
+func (c *RideClient) ConfirmBooking(ctx context.Context, req *ridev1.ConfirmBookingRequest) (*ridev1.ConfirmBookingResponse, error) {
+	return c.client.ConfirmBooking(ctx, req)
+}
+
+func (c *RideClient) CancelBooking(ctx context.Context, req *ridev1.CancelBookingRequest) (*ridev1.CancelBookingResponse, error) {
+	return c.client.CancelBooking(ctx, req)
+}
+
+func (c *RideClient) GetBookingStatus(ctx context.Context, req *ridev1.GetBookingStatusRequest) (*ridev1.GetBookingStatusResponse, error) {
+	return c.client.GetBookingStatus(ctx, req)
+}
+
+func (c *RideClient) ListBookings(ctx context.Context, req *ridev1.ListBookingsRequest) (*ridev1.ListBookingsResponse, error) {
+	return c.client.ListBookings(ctx, req)
+}
+
+// TrackVehicle returns the server-streaming client for vehicleId;
+// callers Recv() in a loop until the stream ends or ctx is cancelled.
+func (c *RideClient) TrackVehicle(ctx context.Context, vehicleId string) (ridev1.RideService_TrackVehicleClient, error) {
+	return c.client.TrackVehicle(ctx, &ridev1.TrackVehicleRequest{VehicleId: vehicleId})
+}
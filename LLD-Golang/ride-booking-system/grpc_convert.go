@@ -0,0 +1,93 @@
+package main
+
+import (
+	ridev1 "ride-booking-system/proto/ridev1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toPBLocation(l Location) *ridev1.Location {
+	return &ridev1.Location{Latitude: l.Latitude, Longitude: l.Longitude}
+}
+
+func fromPBLocation(l *ridev1.Location) Location {
+	if l == nil {
+		return Location{}
+	}
+	return Location{Latitude: l.Latitude, Longitude: l.Longitude}
+}
+
+func toPBRoute(r Route) *ridev1.Route {
+	return &ridev1.Route{
+		RouteId:                  r.RouteId,
+		Start:                    toPBLocation(r.Start),
+		End:                      toPBLocation(r.End),
+		DistanceKm:               r.Distance,
+		EstimatedDurationMinutes: r.EstimatedDuration,
+		Polyline:                 r.Polyline,
+	}
+}
+
+func fromPBRoute(r *ridev1.Route) Route {
+	if r == nil {
+		return Route{}
+	}
+	return Route{
+		RouteId:           r.RouteId,
+		Start:             fromPBLocation(r.Start),
+		End:               fromPBLocation(r.End),
+		Distance:          r.DistanceKm,
+		EstimatedDuration: r.EstimatedDurationMinutes,
+		Polyline:          r.Polyline,
+	}
+}
+
+func toPBUser(u User) *ridev1.User {
+	return &ridev1.User{
+		UserId: u.UserId,
+		Name:   u.Name,
+		Email:  u.Email,
+		Phone:  u.Phone,
+	}
+}
+
+func toPBSlot(s Slot) *ridev1.Slot {
+	return &ridev1.Slot{
+		VehicleId:     s.VehicleId,
+		VehicleTypeId: s.VehicleType.TypeId,
+		Route:         toPBRoute(s.Route),
+		Price:         s.Price,
+		StartTime:     timestamppb.New(s.StartTime),
+	}
+}
+
+func fromPBSlot(s *ridev1.Slot) Slot {
+	if s == nil {
+		return Slot{}
+	}
+	return Slot{
+		VehicleId:   s.VehicleId,
+		VehicleType: VehicleType{TypeId: s.VehicleTypeId},
+		Route:       fromPBRoute(s.Route),
+		Price:       s.Price,
+		StartTime:   s.StartTime.AsTime(),
+	}
+}
+
+func toPBBooking(b *Booking) *ridev1.Booking {
+	pb := &ridev1.Booking{
+		BookingId: b.BookingId,
+		User:      toPBUser(b.User),
+		Route:     toPBRoute(b.Route),
+		Status:    b.State.Status(),
+		StartTime: timestamppb.New(b.StartTime),
+	}
+	if b.Vehicle != nil {
+		pb.Vehicle = &ridev1.Vehicle{
+			VehicleId:       b.Vehicle.GetVehicleId(),
+			Status:          string(b.Vehicle.GetStatus()),
+			CurrentLocation: toPBLocation(b.Vehicle.GetCurrentLocation()),
+		}
+	}
+	return pb
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	ridev1 "ride-booking-system/proto/ridev1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RideServer implements ridev1.RideServiceServer (generated from
+// proto/ridev1/ride.proto - run protoc/buf generate to produce it) by
+// wrapping this package's existing UserService, ReservationManager,
+// VehicleAllocator, and VehicleTracker; payments go through the
+// PaymentService that ReservationManager holds. ReservationManager
+// - not BookingService, which is still just a TrackingObserver stub in
+// this tree - owns the actual lease/confirm flow, so it's what the
+// booking-shaped RPCs delegate to.
+type RideServer struct {
+	ridev1.UnimplementedRideServiceServer
+
+	userService  *UserService
+	reservations *ReservationManager
+	allocator    *VehicleAllocator
+	tracker      *VehicleTracker
+}
+
+func NewRideServer(userService *UserService, reservations *ReservationManager, allocator *VehicleAllocator, tracker *VehicleTracker) *RideServer {
+	return &RideServer{
+		userService:  userService,
+		reservations: reservations,
+		allocator:    allocator,
+		tracker:      tracker,
+	}
+}
+
+func (s *RideServer) RegisterUser(ctx context.Context, req *ridev1.RegisterUserRequest) (*ridev1.RegisterUserResponse, error) {
+	user := s.userService.RegisterUser(req.Name, req.Email, req.Phone)
+	return &ridev1.RegisterUserResponse{User: toPBUser(user)}, nil
+}
+
+// SearchVehicles quotes every available vehicle via ReservationManager,
+// then uses VehicleAllocator's drive-time ranking to move the vehicle
+// it would actually dispatch to the front of the results.
+func (s *RideServer) SearchVehicles(ctx context.Context, req *ridev1.SearchVehiclesRequest) (*ridev1.SearchVehiclesResponse, error) {
+	pickup := fromPBLocation(req.Pickup)
+	dropoff := fromPBLocation(req.Dropoff)
+	vehicleType := VehicleType{TypeId: req.VehicleTypeId}
+	startTime := req.StartTime.AsTime()
+
+	slots := s.reservations.CheckAvailability(pickup, dropoff, vehicleType, startTime)
+	sortSlotsNearestFirst(slots, s.nearestVehicleId(pickup, vehicleType))
+
+	resp := &ridev1.SearchVehiclesResponse{Slots: make([]*ridev1.Slot, 0, len(slots))}
+	for _, slot := range slots {
+		resp.Slots = append(resp.Slots, toPBSlot(slot))
+	}
+	return resp, nil
+}
+
+// nearestVehicleId asks VehicleAllocator for the closest available
+// vehicle by drive-time. FindNearestAvailable panics when none are
+// available; SearchVehicles tolerates that since CheckAvailability's
+// own (possibly empty) slot list is the authoritative answer.
+func (s *RideServer) nearestVehicleId(pickup Location, typ VehicleType) (id string) {
+	defer func() {
+		if recover() != nil {
+			id = ""
+		}
+	}()
+	return s.allocator.FindNearestAvailable(pickup, typ).GetVehicleId()
+}
+
+func sortSlotsNearestFirst(slots []Slot, nearestId string) {
+	if nearestId == "" {
+		return
+	}
+	for i, slot := range slots {
+		if slot.VehicleId == nearestId {
+			slots[0], slots[i] = slots[i], slots[0]
+			return
+		}
+	}
+}
+
+func (s *RideServer) CreateLease(ctx context.Context, req *ridev1.CreateLeaseRequest) (*ridev1.CreateLeaseResponse, error) {
+	slot := fromPBSlot(req.Slot)
+	lease, err := s.reservations.CreateLease(slot, req.UserId, req.IdempotencyToken)
+	if err != nil {
+		return nil, grpcStatusFor(err)
+	}
+	return &ridev1.CreateLeaseResponse{
+		LeaseId:   lease.LeaseId,
+		ExpiresAt: timestamppb.New(lease.ExpiresAt),
+	}, nil
+}
+
+func (s *RideServer) ConfirmBooking(ctx context.Context, req *ridev1.ConfirmBookingRequest) (*ridev1.ConfirmBookingResponse, error) {
+	booking, err := s.reservations.ConfirmBooking(req.LeaseId, req.PaymentMethod, req.IdempotencyToken)
+	if err != nil {
+		return nil, grpcStatusFor(err)
+	}
+	return &ridev1.ConfirmBookingResponse{Booking: toPBBooking(booking)}, nil
+}
+
+func (s *RideServer) CancelBooking(ctx context.Context, req *ridev1.CancelBookingRequest) (*ridev1.CancelBookingResponse, error) {
+	booking, err := s.reservations.CancelBooking(req.BookingId)
+	if err != nil {
+		return nil, grpcStatusFor(err)
+	}
+	return &ridev1.CancelBookingResponse{Booking: toPBBooking(booking)}, nil
+}
+
+func (s *RideServer) GetBookingStatus(ctx context.Context, req *ridev1.GetBookingStatusRequest) (*ridev1.GetBookingStatusResponse, error) {
+	bookingStatus, err := s.reservations.GetBookingStatus(req.BookingId)
+	if err != nil {
+		return nil, grpcStatusFor(err)
+	}
+	return &ridev1.GetBookingStatusResponse{Status: bookingStatus}, nil
+}
+
+func (s *RideServer) ListBookings(ctx context.Context, req *ridev1.ListBookingsRequest) (*ridev1.ListBookingsResponse, error) {
+	filter := BookingFilter{
+		UserId:    req.UserId,
+		VehicleId: req.VehicleId,
+		Status:    req.Status,
+	}
+	if req.From != nil {
+		filter.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		filter.To = req.To.AsTime()
+	}
+
+	bookings := s.reservations.ListBookings(filter)
+	resp := &ridev1.ListBookingsResponse{Bookings: make([]*ridev1.Booking, 0, len(bookings))}
+	for _, b := range bookings {
+		resp.Bookings = append(resp.Bookings, toPBBooking(b))
+	}
+	return resp, nil
+}
+
+// TrackVehicle reuses VehicleTracker/TrackingObserver: it registers a
+// streamObserver against vehicleId and forwards the locations that
+// observer buffers onto the gRPC stream until the stream's context ends.
+// VehicleTracker has no way to remove an observer, so the streamObserver
+// stays registered after the RPC returns.
+func (s *RideServer) TrackVehicle(req *ridev1.TrackVehicleRequest, stream ridev1.RideService_TrackVehicleServer) error {
+	updates := make(chan Location, 16)
+	obs := &streamObserver{updates: updates}
+	s.tracker.RegisterObserver(req.VehicleId, obs)
+
+	for {
+		select {
+		case loc := <-updates:
+			update := &ridev1.TrackVehicleUpdate{Location: toPBLocation(loc)}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// streamObserver adapts VehicleTracker's push-based TrackingObserver
+// callback onto a buffered channel that TrackVehicle can select on
+// alongside the stream's context cancellation.
+type streamObserver struct {
+	updates chan<- Location
+}
+
+func (o *streamObserver) UpdateVehicleLocation(vehicleId string, location Location) {
+	select {
+	case o.updates <- location:
+	default:
+		// Buffer full (the stream's reader is behind): drop this update.
+		// The positions already buffered are still sent, oldest first.
+	}
+}
+
+// grpcStatusFor maps this package's sentinel errors onto gRPC status
+// codes so clients can branch on codes.Code instead of string matching.
+func grpcStatusFor(err error) error {
+	switch {
+	case errors.As(err, new(*NoVehicleAvailableException)):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrLeaseExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrPriceChanged):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrVehicleUnavailable):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrLeaseNotFound), errors.Is(err, ErrBookingNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
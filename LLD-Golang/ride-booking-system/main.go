@@ -2,6 +2,7 @@ package main
 
 import (
 	"math"
+	"sync"
 	"time"
 )
 
@@ -59,11 +60,17 @@ type VehicleType struct {
 	BaseFare   float64
 	PerKmRate  float64
 	PerMinRate float64
+	Costing    string     // auto, bicycle, pedestrian - selects a RoutingProvider in VehicleAllocator
+	Pool       PoolPolicy // zero value (SeatCapacity 0) means this type never pools
 }
 
 type Vehicle interface {
 	CalculateFare(route Route) float64
 	GetCurrentLocation() Location // Added for VehicleAllocator
+	GetVehicleId() string         // Added for ReservationManager
+	GetStatus() VehicleStatus     // Added for ReservationManager
+	SetStatus(status VehicleStatus)
+	GetType() VehicleType // Added for pooling: lets VehicleAllocator filter active vehicles by type
 }
 
 type BaseVehicle struct {
@@ -75,14 +82,31 @@ type BaseVehicle struct {
 	CurrentLocation Location // Added for tracking and allocation
 }
 
+func (bv *BaseVehicle) GetVehicleId() string           { return bv.VehicleId }
+func (bv *BaseVehicle) GetStatus() VehicleStatus       { return bv.Status }
+func (bv *BaseVehicle) SetStatus(status VehicleStatus) { bv.Status = status }
+func (bv *BaseVehicle) GetType() VehicleType           { return bv.Type }
+
 type AutonomousVehicle struct {
 	BaseVehicle
 	SoftwareVersion string
 }
 
+// CalculateFare prices av's own assigned leg of route, not the whole
+// vehicle itinerary: for a pooled ride, route.OverlapKm is the portion
+// of this leg shared with co-riders, and is charged at half the per-km
+// rate instead of the solo rate. MatchPooledRide does not set
+// OverlapKm; it is 0 unless the caller fills it in.
 func (av *AutonomousVehicle) CalculateFare(route Route) float64 {
+	overlap := route.OverlapKm
+	if overlap > route.Distance {
+		overlap = route.Distance
+	}
+	solo := route.Distance - overlap
+
 	return av.Type.BaseFare +
-		(route.Distance * av.Type.PerKmRate) +
+		(solo * av.Type.PerKmRate) +
+		(overlap * av.Type.PerKmRate * 0.5) +
 		(route.EstimatedDuration * av.Type.PerMinRate)
 }
 
@@ -97,10 +121,13 @@ type BookingState interface {
 	StartRide(booking *Booking)
 	CompleteRide(booking *Booking)
 	CancelBooking(booking *Booking)
+	Status() string // Added for GetBookingStatus
 }
 
 type ConfirmedState struct{}
 
+func (cs *ConfirmedState) Status() string { return "CONFIRMED" }
+
 func (cs *ConfirmedState) ConfirmBooking(booking *Booking) {
 	// Implement state transition (e.g., do nothing if already confirmed)
 }
@@ -118,14 +145,16 @@ func (cs *ConfirmedState) CancelBooking(booking *Booking) {
 }
 
 type Booking struct {
-	BookingId string
-	User      User
-	Vehicle   Vehicle
-	Route     Route
-	Payment   Payment
-	State     BookingState
-	StartTime time.Time
-	EndTime   time.Time
+	BookingId    string
+	User         User
+	Vehicle      Vehicle
+	Route        Route
+	Payment      Payment
+	State        BookingState
+	StartTime    time.Time
+	EndTime      time.Time
+	SharedRideId string     // Set when this booking is pooled with others on the same vehicle
+	StopSequence []Location // The shared vehicle's planned remaining stops, set by MatchPooledRide
 }
 
 func (b *Booking) StartRide() {
@@ -191,6 +220,8 @@ type Route struct {
 	End               Location
 	Distance          float64 // in km
 	EstimatedDuration float64 // in minutes
+	Polyline          string  // encoded shape, populated by providers that return one (e.g. ValhallaProvider)
+	OverlapKm         float64 // km of this leg shared with a pooled co-rider; 0 unless the caller sets it (MatchPooledRide does not)
 }
 
 func (r *Route) CalculateRoute(start, end Location) {
@@ -231,10 +262,18 @@ func (ps *PaymentService) CreatePayment(booking *Booking) Payment {
 	return ps.processor.ProcessPayment(amount, booking.User.PaymentMethod)
 }
 
+// ChargeAmount charges a specific, already-quoted amount rather than
+// recomputing it from a Booking - used by ReservationManager.ConfirmBooking
+// to charge exactly the lease's locked-in price.
+func (ps *PaymentService) ChargeAmount(amount float64, paymentMethod string) Payment {
+	return ps.processor.ProcessPayment(amount, paymentMethod)
+}
+
 // 7. Vehicle Allocation
 
 type VehicleRepository interface {
 	FindAvailableVehicles(typ VehicleType) []Vehicle
+	FindById(vehicleId string) (Vehicle, error) // Added for ReservationManager
 }
 
 type NoVehicleAvailableException struct{}
@@ -243,16 +282,61 @@ func (e *NoVehicleAvailableException) Error() string {
 	return "no vehicle available"
 }
 
+// VehicleAllocator picks the vehicle a rider actually gets matched to.
+// routingByCosting lets each VehicleType route drive-time queries
+// through the RoutingProvider that matches how it moves (e.g. a bike
+// courier's ETA shouldn't be computed with car costing); defaultRouting
+// is used for any VehicleType.Costing with no entry.
 type VehicleAllocator struct {
-	vehicleRepo VehicleRepository
+	vehicleRepo      VehicleRepository
+	routingByCosting map[string]RoutingProvider
+	defaultRouting   RoutingProvider
+
+	poolMu         sync.Mutex
+	activeBookings map[string][]*Booking // vehicleId -> bookings it's currently serving (for pooling)
+	activeStops    map[string][]Location  // vehicleId -> its current planned remaining stops
+}
+
+func (va *VehicleAllocator) routingFor(typ VehicleType) RoutingProvider {
+	if r, ok := va.routingByCosting[typ.Costing]; ok {
+		return r
+	}
+	return va.defaultRouting
 }
 
+// FindNearestAvailable ranks available vehicles of typ by actual
+// drive-time to pickup via RoutingProvider.MatrixETA, rather than
+// Location.DistanceTo's crow-flies distance. If the routing provider
+// errors (e.g. Valhalla is down), it falls back to crow-flies ranking
+// so allocation still succeeds.
 func (va *VehicleAllocator) FindNearestAvailable(pickup Location, typ VehicleType) Vehicle {
 	available := va.vehicleRepo.FindAvailableVehicles(typ)
 	if len(available) == 0 {
 		panic(&NoVehicleAvailableException{})
 	}
 
+	origins := make([]Location, len(available))
+	for i, v := range available {
+		origins[i] = v.GetCurrentLocation()
+	}
+
+	matrix, err := va.routingFor(typ).MatrixETA(origins, []Location{pickup})
+	if err != nil {
+		return va.nearestByDistance(available, pickup)
+	}
+
+	var nearest Vehicle
+	minETA := time.Duration(math.MaxInt64)
+	for i, v := range available {
+		if eta := matrix[i][0]; eta < minETA {
+			minETA = eta
+			nearest = v
+		}
+	}
+	return nearest
+}
+
+func (va *VehicleAllocator) nearestByDistance(available []Vehicle, pickup Location) Vehicle {
 	var minVehicle Vehicle
 	minDist := math.MaxFloat64
 	for _, v := range available {
@@ -305,9 +389,15 @@ func (bs *BookingService) UpdateVehicleLocation(vehicleId string, location Locat
 // Example for VehicleAllocator singleton (if needed):
 var vehicleAllocator *VehicleAllocator
 
-func GetVehicleAllocator(repo VehicleRepository) *VehicleAllocator {
+func GetVehicleAllocator(repo VehicleRepository, routingByCosting map[string]RoutingProvider, defaultRouting RoutingProvider) *VehicleAllocator {
 	if vehicleAllocator == nil {
-		vehicleAllocator = &VehicleAllocator{vehicleRepo: repo}
+		vehicleAllocator = &VehicleAllocator{
+			vehicleRepo:      repo,
+			routingByCosting: routingByCosting,
+			defaultRouting:   defaultRouting,
+			activeBookings:   make(map[string][]*Booking),
+			activeStops:      make(map[string][]Location),
+		}
 	}
 	return vehicleAllocator
 }
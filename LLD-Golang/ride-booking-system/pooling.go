@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// PoolPolicy bounds how far MatchPooledRide may perturb an existing
+// rider's trip before it rejects a would-be insertion, and how many
+// concurrent riders a vehicle of this type can carry. The zero value
+// (SeatCapacity 0) disables pooling for a VehicleType, since no
+// candidate can ever satisfy len(active)+1 <= 0.
+type PoolPolicy struct {
+	MaxDetourKm      float64
+	MaxExtraDuration time.Duration
+	SeatCapacity     int
+}
+
+// ErrNoPoolableVehicle is returned when no candidate vehicle can take
+// on this pooled ride within policy bounds.
+var ErrNoPoolableVehicle = errors.New("pooling: no vehicle can accept this pooled ride within policy")
+
+// kmToDuration assumes the same average speed as Route.CalculateRoute
+// and HaversineProvider: 50 km/h.
+func kmToDuration(km float64) time.Duration {
+	return time.Duration(km / 50.0 * float64(time.Hour))
+}
+
+// routeLength sums crow-flies distance across an ordered stop
+// sequence starting from origin.
+func routeLength(origin Location, stops []Location) float64 {
+	total := 0.0
+	cursor := origin
+	for _, s := range stops {
+		total += cursor.DistanceTo(s)
+		cursor = s
+	}
+	return total
+}
+
+// cumulativeETA maps each stop to the elapsed drive time from origin
+// to reach it, walking the sequence in order.
+func cumulativeETA(origin Location, stops []Location) map[Location]time.Duration {
+	etas := make(map[Location]time.Duration, len(stops))
+	cursor := origin
+	var elapsed time.Duration
+	for _, s := range stops {
+		elapsed += kmToDuration(cursor.DistanceTo(s))
+		etas[s] = elapsed
+		cursor = s
+	}
+	return etas
+}
+
+// insertPositions enumerates every (i, j) with 0 <= i <= j <= n: insert
+// pickup just before stops[i] and dropoff just before stops[j], which
+// by construction always keeps pickup at or before dropoff and leaves
+// every existing stop in its original relative order.
+func insertPositions(n int) [][2]int {
+	positions := make([][2]int, 0, (n+1)*(n+2)/2)
+	for i := 0; i <= n; i++ {
+		for j := i; j <= n; j++ {
+			positions = append(positions, [2]int{i, j})
+		}
+	}
+	return positions
+}
+
+func spliceStops(stops []Location, i, j int, pickup, dropoff Location) []Location {
+	out := make([]Location, 0, len(stops)+2)
+	out = append(out, stops[:i]...)
+	out = append(out, pickup)
+	out = append(out, stops[i:j]...)
+	out = append(out, dropoff)
+	out = append(out, stops[j:]...)
+	return out
+}
+
+// evaluateInsertion tries every legal ordering of inserting
+// (pickup, dropoff) into existingStops and returns the cheapest one
+// (by added vehicle-km) that keeps every existing rider's added detour
+// and the new rider's added travel time within policy. ok is false if
+// no ordering qualifies.
+func evaluateInsertion(origin Location, active []*Booking, existingStops []Location, pickup, dropoff Location, policy PoolPolicy) (stops []Location, addedKm float64, ok bool) {
+	baseline := routeLength(origin, existingStops)
+	baselineETA := cumulativeETA(origin, existingStops)
+	directDuration := kmToDuration(pickup.DistanceTo(dropoff))
+
+	bestAdded := math.MaxFloat64
+	var bestStops []Location
+
+	for _, pos := range insertPositions(len(existingStops)) {
+		candidate := spliceStops(existingStops, pos[0], pos[1], pickup, dropoff)
+
+		added := routeLength(origin, candidate) - baseline
+		if added < 0 {
+			added = 0
+		}
+		if added > policy.MaxDetourKm || added >= bestAdded {
+			continue
+		}
+
+		candidateETA := cumulativeETA(origin, candidate)
+
+		detourOK := true
+		for _, b := range active {
+			if candidateETA[b.Route.End]-baselineETA[b.Route.End] > policy.MaxExtraDuration {
+				detourOK = false
+				break
+			}
+		}
+		if !detourOK {
+			continue
+		}
+
+		riderDuration := candidateETA[dropoff] - candidateETA[pickup]
+		if riderDuration-directDuration > policy.MaxExtraDuration {
+			continue
+		}
+
+		bestAdded = added
+		bestStops = candidate
+	}
+
+	return bestStops, bestAdded, bestStops != nil
+}
+
+// poolCandidates returns every vehicle of typ worth considering for a
+// pooled match: vehicles currently Available, plus vehicles already
+// serving an active pooled booking of this type (which a fresh
+// FindAvailableVehicles call would skip, since their status isn't
+// Available).
+func (va *VehicleAllocator) poolCandidates(typ VehicleType) []Vehicle {
+	seen := make(map[string]bool)
+	var candidates []Vehicle
+
+	for _, v := range va.vehicleRepo.FindAvailableVehicles(typ) {
+		seen[v.GetVehicleId()] = true
+		candidates = append(candidates, v)
+	}
+
+	for vehicleId := range va.activeBookings {
+		if seen[vehicleId] {
+			continue
+		}
+		v, err := va.vehicleRepo.FindById(vehicleId)
+		if err != nil || v.GetType().TypeId != typ.TypeId {
+			continue
+		}
+		seen[vehicleId] = true
+		candidates = append(candidates, v)
+	}
+
+	return candidates
+}
+
+// MatchPooledRide finds the vehicle of typ that can add a rider going
+// pickup->dropoff for the least extra vehicle-km, considering every
+// candidate's in-progress bookings: it tries every legal ordering of
+// splicing pickup/dropoff into each vehicle's remaining stop sequence
+// and keeps the cheapest ordering that stays within policy for both
+// the existing riders and the new one. On success it returns the
+// chosen vehicle and the (now re-sequenced) bookings it was already
+// serving; the caller still creates the new rider's own Booking
+// through the usual lease/confirm flow and registers it via
+// RegisterActiveBooking.
+func (va *VehicleAllocator) MatchPooledRide(pickup, dropoff Location, typ VehicleType, policy PoolPolicy) (Vehicle, []Booking, error) {
+	va.poolMu.Lock()
+	defer va.poolMu.Unlock()
+
+	var bestVehicle Vehicle
+	var bestStops []Location
+	bestAdded := math.MaxFloat64
+
+	for _, v := range va.poolCandidates(typ) {
+		active := va.activeBookings[v.GetVehicleId()]
+		if len(active)+1 > policy.SeatCapacity {
+			continue
+		}
+
+		existingStops := make([]Location, len(active))
+		for i, b := range active {
+			existingStops[i] = b.Route.End
+		}
+
+		stops, added, ok := evaluateInsertion(v.GetCurrentLocation(), active, existingStops, pickup, dropoff, policy)
+		if !ok || added >= bestAdded {
+			continue
+		}
+		bestAdded = added
+		bestVehicle = v
+		bestStops = stops
+	}
+
+	if bestVehicle == nil {
+		return nil, nil, ErrNoPoolableVehicle
+	}
+
+	vehicleId := bestVehicle.GetVehicleId()
+	va.activeStops[vehicleId] = bestStops
+
+	active := va.activeBookings[vehicleId]
+	affected := make([]Booking, 0, len(active))
+	for _, b := range active {
+		b.StopSequence = bestStops
+		affected = append(affected, *b)
+	}
+	return bestVehicle, affected, nil
+}
+
+// RegisterActiveBooking records booking as one of vehicleId's
+// currently-served riders, so future MatchPooledRide calls see it as
+// an existing stop to route around.
+func (va *VehicleAllocator) RegisterActiveBooking(vehicleId string, booking *Booking) {
+	va.poolMu.Lock()
+	defer va.poolMu.Unlock()
+	va.activeBookings[vehicleId] = append(va.activeBookings[vehicleId], booking)
+}
+
+// ReleaseBooking removes bookingId from vehicleId's active set, e.g.
+// once it's cancelled or completed.
+func (va *VehicleAllocator) ReleaseBooking(vehicleId, bookingId string) {
+	va.poolMu.Lock()
+	defer va.poolMu.Unlock()
+
+	bookings := va.activeBookings[vehicleId]
+	for i, b := range bookings {
+		if b.BookingId == bookingId {
+			va.activeBookings[vehicleId] = append(bookings[:i], bookings[i+1:]...)
+			break
+		}
+	}
+	if len(va.activeBookings[vehicleId]) == 0 {
+		delete(va.activeBookings, vehicleId)
+		delete(va.activeStops, vehicleId)
+	}
+}
+
+// SharedPricing wraps a base PricingStrategy and discounts the
+// route.OverlapKm stretch of the route: that stretch is charged at half
+// the base fare's effective per-km rate, since a co-rider is splitting
+// it. MatchPooledRide does not set OverlapKm, so a route whose OverlapKm
+// the caller has not filled in is charged the full base fare.
+type SharedPricing struct {
+	Base PricingStrategy
+}
+
+func NewSharedPricing(base PricingStrategy) *SharedPricing {
+	return &SharedPricing{Base: base}
+}
+
+func (sp *SharedPricing) CalculateFare(route Route, typ VehicleType) float64 {
+	full := sp.Base.CalculateFare(route, typ)
+	if route.Distance <= 0 || route.OverlapKm <= 0 {
+		return full
+	}
+	overlapFraction := route.OverlapKm / route.Distance
+	if overlapFraction > 1 {
+		overlapFraction = 1
+	}
+	return full * (1 - overlapFraction*0.5)
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Leased sits between Available and Booked: the vehicle is held
+// against a pending lease but hasn't been converted into a confirmed
+// booking yet.
+const Leased VehicleStatus = "LEASED"
+
+// LeasedState mirrors Leased in the Booking state machine: a lease has
+// been created but ConfirmBooking hasn't run yet.
+type LeasedState struct{}
+
+func (ls *LeasedState) ConfirmBooking(booking *Booking) {
+	booking.State = &ConfirmedState{}
+}
+
+func (ls *LeasedState) StartRide(booking *Booking) {
+	// Can't start a ride before the lease is confirmed.
+}
+
+func (ls *LeasedState) CompleteRide(booking *Booking) {
+	// Nothing has started yet.
+}
+
+func (ls *LeasedState) CancelBooking(booking *Booking) {
+	booking.State = &CancelledState{}
+}
+
+func (ls *LeasedState) Status() string { return "LEASED" }
+
+// CancelledState is terminal; every transition is a no-op.
+type CancelledState struct{}
+
+func (cs *CancelledState) ConfirmBooking(booking *Booking) {}
+func (cs *CancelledState) StartRide(booking *Booking)      {}
+func (cs *CancelledState) CompleteRide(booking *Booking)   {}
+func (cs *CancelledState) CancelBooking(booking *Booking)  {}
+func (cs *CancelledState) Status() string                  { return "CANCELLED" }
+
+var (
+	ErrVehicleUnavailable = errors.New("reservation: vehicle unavailable")
+	ErrLeaseNotFound      = errors.New("reservation: lease not found")
+	ErrLeaseExpired       = errors.New("reservation: lease expired")
+	ErrPriceChanged       = errors.New("reservation: quoted price changed since lease was created")
+	ErrBookingNotFound    = errors.New("reservation: booking not found")
+)
+
+// Slot is one candidate vehicle/price offering returned by
+// CheckAvailability.
+type Slot struct {
+	VehicleId   string
+	VehicleType VehicleType
+	Route       Route
+	Price       float64
+	StartTime   time.Time
+}
+
+// Lease is a short-lived, exclusive hold on a specific vehicle quoted
+// at a specific price, created from a Slot and either confirmed into a
+// Booking or released back to Available on expiry/cancellation.
+type Lease struct {
+	LeaseId          string
+	VehicleId        string
+	UserId           string
+	Route            Route
+	VehicleType      VehicleType
+	Price            float64
+	ExpiresAt        time.Time
+	IdempotencyToken string
+}
+
+// BookingRepository persists confirmed bookings.
+type BookingRepository interface {
+	Save(booking *Booking)
+	FindById(bookingId string) (*Booking, error) // Added for GetBookingStatus/CancelBooking
+	FindAll() []*Booking                         // Added for ListBookings
+}
+
+// BookingFilter narrows ListBookings to bookings matching every
+// non-zero-value field. UserId/VehicleId/Status match exactly; a
+// booking's StartTime must fall within [From, To) when those are set.
+type BookingFilter struct {
+	UserId    string
+	VehicleId string
+	Status    string
+	From      time.Time
+	To        time.Time
+}
+
+func (f BookingFilter) matches(b *Booking) bool {
+	if f.UserId != "" && b.User.UserId != f.UserId {
+		return false
+	}
+	if f.VehicleId != "" && b.Vehicle.GetVehicleId() != f.VehicleId {
+		return false
+	}
+	if f.Status != "" && b.State.Status() != f.Status {
+		return false
+	}
+	if !f.From.IsZero() && b.StartTime.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !b.StartTime.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// ReservationManager implements the two-phase reservation protocol -
+// CheckAvailability, CreateLease, ConfirmBooking - modeled on the
+// Google Maps Booking Partner flow: a lease holds one specific vehicle
+// for a short TTL before the rider actually pays. CreateLease and
+// ConfirmBooking are idempotent on a caller-supplied token so a network
+// retry can never double-hold a car or double-charge; CheckAvailability
+// only quotes and takes no token.
+type ReservationManager struct {
+	vehicleRepo    VehicleRepository
+	bookingRepo    BookingRepository
+	paymentService *PaymentService
+	allocator      *VehicleAllocator // optional; nil disables pooling bookkeeping
+	leaseTTL       time.Duration
+
+	mu              sync.Mutex
+	leases          map[string]*Lease   // leaseId -> lease
+	leasesByToken   map[string]*Lease   // CreateLease idempotency token -> lease
+	bookingsByToken map[string]*Booking // ConfirmBooking idempotency token -> booking
+}
+
+func NewReservationManager(vehicleRepo VehicleRepository, bookingRepo BookingRepository, paymentService *PaymentService, allocator *VehicleAllocator, leaseTTL time.Duration) *ReservationManager {
+	return &ReservationManager{
+		vehicleRepo:     vehicleRepo,
+		bookingRepo:     bookingRepo,
+		paymentService:  paymentService,
+		allocator:       allocator,
+		leaseTTL:        leaseTTL,
+		leases:          make(map[string]*Lease),
+		leasesByToken:   make(map[string]*Lease),
+		bookingsByToken: make(map[string]*Booking),
+	}
+}
+
+// CheckAvailability quotes every available vehicle of typ for the
+// pickup/dropoff route at startTime.
+func (rm *ReservationManager) CheckAvailability(pickup, dropoff Location, vehicleType VehicleType, startTime time.Time) []Slot {
+	route := Route{}
+	route.CalculateRoute(pickup, dropoff)
+	price := GetStrategy(startTime).CalculateFare(route, vehicleType)
+
+	candidates := rm.vehicleRepo.FindAvailableVehicles(vehicleType)
+	slots := make([]Slot, 0, len(candidates))
+	for _, v := range candidates {
+		slots = append(slots, Slot{
+			VehicleId:   v.GetVehicleId(),
+			VehicleType: vehicleType,
+			Route:       route,
+			Price:       price,
+			StartTime:   startTime,
+		})
+	}
+	return slots
+}
+
+// CreateLease atomically holds slot.VehicleId for rm.leaseTTL. A retry
+// with the same idempotencyToken returns the original lease instead of
+// taking a second hold.
+func (rm *ReservationManager) CreateLease(slot Slot, userId, idempotencyToken string) (*Lease, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if existing, ok := rm.leasesByToken[idempotencyToken]; ok {
+		return existing, nil
+	}
+
+	vehicle, err := rm.vehicleRepo.FindById(slot.VehicleId)
+	if err != nil {
+		return nil, err
+	}
+	if vehicle.GetStatus() != Available {
+		return nil, ErrVehicleUnavailable
+	}
+	vehicle.SetStatus(Leased)
+
+	lease := &Lease{
+		LeaseId:          generateId(),
+		VehicleId:        slot.VehicleId,
+		UserId:           userId,
+		Route:            slot.Route,
+		VehicleType:      slot.VehicleType,
+		Price:            slot.Price,
+		ExpiresAt:        time.Now().Add(rm.leaseTTL),
+		IdempotencyToken: idempotencyToken,
+	}
+	rm.leases[lease.LeaseId] = lease
+	rm.leasesByToken[idempotencyToken] = lease
+	return lease, nil
+}
+
+// ConfirmBooking converts leaseId into a confirmed Booking and charges
+// paymentMethod for the lease's quoted price. It rejects with
+// ErrPriceChanged if re-quoting the lease's route now would come out
+// different (e.g. the pricing strategy's peak window moved), and with
+// ErrLeaseExpired if the reaper hasn't gotten to it yet but the TTL has
+// already passed. A retry with the same idempotencyToken returns the
+// original booking instead of charging twice.
+func (rm *ReservationManager) ConfirmBooking(leaseId, paymentMethod, idempotencyToken string) (*Booking, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if existing, ok := rm.bookingsByToken[idempotencyToken]; ok {
+		return existing, nil
+	}
+
+	lease, ok := rm.leases[leaseId]
+	if !ok {
+		return nil, ErrLeaseNotFound
+	}
+	if time.Now().After(lease.ExpiresAt) {
+		rm.releaseLeaseLocked(lease)
+		return nil, ErrLeaseExpired
+	}
+
+	currentPrice := GetStrategy(time.Now()).CalculateFare(lease.Route, lease.VehicleType)
+	if !floatsEqual(currentPrice, lease.Price) {
+		rm.releaseLeaseLocked(lease)
+		return nil, ErrPriceChanged
+	}
+
+	vehicle, err := rm.vehicleRepo.FindById(lease.VehicleId)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := rm.paymentService.ChargeAmount(lease.Price, paymentMethod)
+
+	booking := &Booking{
+		BookingId: generateId(),
+		User:      User{UserId: lease.UserId},
+		Vehicle:   vehicle,
+		Route:     lease.Route,
+		Payment:   payment,
+		State:     &ConfirmedState{},
+		StartTime: time.Now(),
+	}
+	vehicle.SetStatus(Booked)
+	rm.bookingRepo.Save(booking)
+	if rm.allocator != nil {
+		rm.allocator.RegisterActiveBooking(lease.VehicleId, booking)
+	}
+
+	delete(rm.leases, lease.LeaseId)
+	delete(rm.leasesByToken, lease.IdempotencyToken)
+	rm.bookingsByToken[idempotencyToken] = booking
+
+	return booking, nil
+}
+
+// CancelBooking transitions bookingId to CancelledState and releases
+// its vehicle back to Available.
+func (rm *ReservationManager) CancelBooking(bookingId string) (*Booking, error) {
+	booking, err := rm.bookingRepo.FindById(bookingId)
+	if err != nil {
+		return nil, ErrBookingNotFound
+	}
+	booking.State.CancelBooking(booking)
+	// ConfirmedState.CancelBooking leaves State unchanged, so the
+	// terminal state is set here for every booking.
+	booking.State = &CancelledState{}
+	booking.Vehicle.SetStatus(Available)
+	if rm.allocator != nil {
+		rm.allocator.ReleaseBooking(booking.Vehicle.GetVehicleId(), booking.BookingId)
+	}
+	return booking, nil
+}
+
+// GetBookingStatus returns bookingId's current state as a status string
+// (e.g. "CONFIRMED", "CANCELLED").
+func (rm *ReservationManager) GetBookingStatus(bookingId string) (string, error) {
+	booking, err := rm.bookingRepo.FindById(bookingId)
+	if err != nil {
+		return "", ErrBookingNotFound
+	}
+	return booking.State.Status(), nil
+}
+
+// ListBookings returns every booking matching filter.
+func (rm *ReservationManager) ListBookings(filter BookingFilter) []*Booking {
+	var matched []*Booking
+	for _, b := range rm.bookingRepo.FindAll() {
+		if filter.matches(b) {
+			matched = append(matched, b)
+		}
+	}
+	return matched
+}
+
+// StartReaper launches a background goroutine that releases expired
+// leases back to Available every interval, until stop is closed.
+func (rm *ReservationManager) StartReaper(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				rm.reapExpiredLeases()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (rm *ReservationManager) reapExpiredLeases() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	now := time.Now()
+	for leaseId, lease := range rm.leases {
+		if now.After(lease.ExpiresAt) {
+			rm.releaseLeaseLocked(lease)
+			delete(rm.leases, leaseId)
+			delete(rm.leasesByToken, lease.IdempotencyToken)
+		}
+	}
+}
+
+// releaseLeaseLocked puts a lease's vehicle back to Available. Callers
+// must hold rm.mu; it does not remove the lease from rm.leases itself.
+func (rm *ReservationManager) releaseLeaseLocked(lease *Lease) {
+	if vehicle, err := rm.vehicleRepo.FindById(lease.VehicleId); err == nil && vehicle.GetStatus() == Leased {
+		vehicle.SetStatus(Available)
+	}
+}
+
+func floatsEqual(a, b float64) bool {
+	const epsilon = 0.01
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff < epsilon
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVehicleRepo is a minimal in-memory VehicleRepository for exercising
+// ReservationManager without a real persistence layer, which this repo
+// doesn't have one of yet.
+type fakeVehicleRepo struct {
+	mu       sync.Mutex
+	vehicles map[string]Vehicle
+}
+
+func newFakeVehicleRepo(vehicles ...Vehicle) *fakeVehicleRepo {
+	r := &fakeVehicleRepo{vehicles: make(map[string]Vehicle)}
+	for _, v := range vehicles {
+		r.vehicles[v.GetVehicleId()] = v
+	}
+	return r
+}
+
+func (r *fakeVehicleRepo) FindAvailableVehicles(typ VehicleType) []Vehicle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Vehicle
+	for _, v := range r.vehicles {
+		if v.GetType().TypeId == typ.TypeId && v.GetStatus() == Available {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (r *fakeVehicleRepo) FindById(vehicleId string) (Vehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.vehicles[vehicleId]
+	if !ok {
+		return nil, errors.New("vehicle not found")
+	}
+	return v, nil
+}
+
+// fakeBookingRepo is a minimal in-memory BookingRepository.
+type fakeBookingRepo struct {
+	mu       sync.Mutex
+	bookings map[string]*Booking
+}
+
+func newFakeBookingRepo() *fakeBookingRepo {
+	return &fakeBookingRepo{bookings: make(map[string]*Booking)}
+}
+
+func (r *fakeBookingRepo) Save(booking *Booking) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bookings[booking.BookingId] = booking
+}
+
+func (r *fakeBookingRepo) FindById(bookingId string) (*Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[bookingId]
+	if !ok {
+		return nil, errors.New("booking not found")
+	}
+	return b, nil
+}
+
+func (r *fakeBookingRepo) FindAll() []*Booking {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*Booking, 0, len(r.bookings))
+	for _, b := range r.bookings {
+		out = append(out, b)
+	}
+	return out
+}
+
+func newTestReservationManager(vehicles ...Vehicle) (*ReservationManager, *fakeVehicleRepo) {
+	vehicleRepo := newFakeVehicleRepo(vehicles...)
+	bookingRepo := newFakeBookingRepo()
+	paymentService := NewPaymentService(&CreditCardProcessor{})
+	rm := NewReservationManager(vehicleRepo, bookingRepo, paymentService, nil, time.Minute)
+	return rm, vehicleRepo
+}
+
+// TestCreateLeaseSerializesConcurrentRequestsForSameVehicle races N
+// CreateLease calls for the same vehicle, as the request asks, and proves
+// exactly one can hold it: the rest must see ErrVehicleUnavailable rather
+// than the vehicle being double-leased.
+func TestCreateLeaseSerializesConcurrentRequestsForSameVehicle(t *testing.T) {
+	sedan := VehicleType{TypeId: "sedan"}
+	vehicle := &AutonomousVehicle{BaseVehicle: BaseVehicle{VehicleId: "v1", Status: Available, Type: sedan}}
+	rm, _ := newTestReservationManager(vehicle)
+
+	slot := Slot{VehicleId: "v1", VehicleType: sedan, Price: 10.0, StartTime: time.Now()}
+
+	const racers = 50
+	var wg sync.WaitGroup
+	results := make([]error, racers)
+	wg.Add(racers)
+	for i := 0; i < racers; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			_, err := rm.CreateLease(slot, "user", fmt.Sprintf("token-%d", i))
+			results[i] = err
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, ErrVehicleUnavailable) {
+			t.Fatalf("unexpected error: %v, want nil or ErrVehicleUnavailable", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("succeeded leases = %d, want exactly 1", succeeded)
+	}
+	if vehicle.GetStatus() != Leased {
+		t.Fatalf("vehicle status = %v, want Leased", vehicle.GetStatus())
+	}
+}
+
+// TestCreateLeaseIsIdempotent proves a retry with the same idempotency
+// token returns the original lease instead of taking a second hold.
+func TestCreateLeaseIsIdempotent(t *testing.T) {
+	sedan := VehicleType{TypeId: "sedan"}
+	vehicle := &AutonomousVehicle{BaseVehicle: BaseVehicle{VehicleId: "v1", Status: Available, Type: sedan}}
+	rm, _ := newTestReservationManager(vehicle)
+
+	slot := Slot{VehicleId: "v1", VehicleType: sedan, Price: 10.0, StartTime: time.Now()}
+
+	first, err := rm.CreateLease(slot, "user", "retry-token")
+	if err != nil {
+		t.Fatalf("first CreateLease: %v", err)
+	}
+	second, err := rm.CreateLease(slot, "user", "retry-token")
+	if err != nil {
+		t.Fatalf("retried CreateLease: %v", err)
+	}
+	if first.LeaseId != second.LeaseId {
+		t.Fatalf("retry produced a different lease: %s vs %s", first.LeaseId, second.LeaseId)
+	}
+}
+
+// TestCreateLeaseRejectsAlreadyLeasedVehicle proves a lease request for a
+// vehicle that's already leased (by a different token) is rejected rather
+// than silently succeeding.
+func TestCreateLeaseRejectsAlreadyLeasedVehicle(t *testing.T) {
+	sedan := VehicleType{TypeId: "sedan"}
+	vehicle := &AutonomousVehicle{BaseVehicle: BaseVehicle{VehicleId: "v1", Status: Available, Type: sedan}}
+	rm, _ := newTestReservationManager(vehicle)
+
+	slot := Slot{VehicleId: "v1", VehicleType: sedan, Price: 10.0, StartTime: time.Now()}
+
+	if _, err := rm.CreateLease(slot, "user-a", "token-a"); err != nil {
+		t.Fatalf("first CreateLease: %v", err)
+	}
+	if _, err := rm.CreateLease(slot, "user-b", "token-b"); !errors.Is(err, ErrVehicleUnavailable) {
+		t.Fatalf("second CreateLease = %v, want ErrVehicleUnavailable", err)
+	}
+}
+
+// TestLeaseExpiryReleasesVehicle proves ConfirmBooking treats a lease
+// past its TTL as expired and releases the vehicle back to Available,
+// as the request's expiry coverage asks for.
+func TestLeaseExpiryReleasesVehicle(t *testing.T) {
+	sedan := VehicleType{TypeId: "sedan"}
+	vehicle := &AutonomousVehicle{BaseVehicle: BaseVehicle{VehicleId: "v1", Status: Available, Type: sedan}}
+	vehicleRepo := newFakeVehicleRepo(vehicle)
+	bookingRepo := newFakeBookingRepo()
+	paymentService := NewPaymentService(&CreditCardProcessor{})
+	rm := NewReservationManager(vehicleRepo, bookingRepo, paymentService, nil, time.Millisecond)
+
+	slot := Slot{VehicleId: "v1", VehicleType: sedan, Price: 10.0, StartTime: time.Now()}
+	lease, err := rm.CreateLease(slot, "user", "token")
+	if err != nil {
+		t.Fatalf("CreateLease: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := rm.ConfirmBooking(lease.LeaseId, "card", "confirm-token"); !errors.Is(err, ErrLeaseExpired) {
+		t.Fatalf("ConfirmBooking on expired lease = %v, want ErrLeaseExpired", err)
+	}
+	if vehicle.GetStatus() != Available {
+		t.Fatalf("vehicle status after expiry = %v, want Available", vehicle.GetStatus())
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RoutingProvider computes real routes and travel-time matrices,
+// replacing Route.CalculateRoute's hardcoded Haversine-distance-at-50kmh
+// assumption with a pluggable source of truth.
+type RoutingProvider interface {
+	Directions(start, end Location, departAt time.Time) (Route, error)
+	MatrixETA(origins, destinations []Location) ([][]time.Duration, error)
+}
+
+// HaversineProvider is RoutingProvider's in-process fallback: it's
+// exactly Route.CalculateRoute's original crow-flies-distance-at-50kmh
+// behavior, so existing callers keep working if no real routing
+// backend is configured.
+type HaversineProvider struct{}
+
+func (h *HaversineProvider) Directions(start, end Location, departAt time.Time) (Route, error) {
+	route := Route{RouteId: generateId()}
+	route.CalculateRoute(start, end)
+	return route, nil
+}
+
+func (h *HaversineProvider) MatrixETA(origins, destinations []Location) ([][]time.Duration, error) {
+	matrix := make([][]time.Duration, len(origins))
+	for i, o := range origins {
+		matrix[i] = make([]time.Duration, len(destinations))
+		for j, d := range destinations {
+			hours := o.DistanceTo(d) / 50.0
+			matrix[i][j] = time.Duration(hours * float64(time.Hour))
+		}
+	}
+	return matrix, nil
+}
+
+// routeCache caches Directions results keyed on rounded lat/lon pairs
+// plus a departure-minute bucket, so nearby requests within the same
+// minute reuse one Valhalla call instead of driving up Valhalla QPS.
+type routeCache struct {
+	mu      sync.RWMutex
+	entries map[string]Route
+}
+
+func newRouteCache() *routeCache {
+	return &routeCache{entries: make(map[string]Route)}
+}
+
+func (c *routeCache) get(key string) (Route, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	route, ok := c.entries[key]
+	return route, ok
+}
+
+func (c *routeCache) put(key string, route Route) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = route
+}
+
+// roundCoord snaps a coordinate to a ~111m grid at the equator, so
+// pickups a few meters apart share a cache entry.
+func roundCoord(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+func routeCacheKey(start, end Location, departAt time.Time) string {
+	return fmt.Sprintf("%.3f,%.3f->%.3f,%.3f@%d",
+		roundCoord(start.Latitude), roundCoord(start.Longitude),
+		roundCoord(end.Latitude), roundCoord(end.Longitude),
+		departAt.Unix()/60)
+}
+
+// Valhalla request/response shapes (https://valhalla.github.io), pared
+// down to the fields this provider actually reads.
+
+type valhallaLocation struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+type valhallaRouteRequest struct {
+	Locations []valhallaLocation `json:"locations"`
+	Costing   string             `json:"costing"`
+}
+
+type valhallaRouteResponse struct {
+	Trip struct {
+		Summary struct {
+			Length float64 `json:"length"` // km
+			Time   float64 `json:"time"`   // seconds
+		} `json:"summary"`
+		Legs []struct {
+			Shape   string `json:"shape"`
+			Summary struct {
+				Length float64 `json:"length"`
+				Time   float64 `json:"time"`
+			} `json:"summary"`
+		} `json:"legs"`
+	} `json:"trip"`
+}
+
+type valhallaMatrixRequest struct {
+	Sources []valhallaLocation `json:"sources"`
+	Targets []valhallaLocation `json:"targets"`
+	Costing string             `json:"costing"`
+}
+
+type valhallaMatrixCell struct {
+	Time float64 `json:"time"` // seconds
+}
+
+type valhallaMatrixResponse struct {
+	SourcesToTargets [][]valhallaMatrixCell `json:"sources_to_targets"`
+}
+
+// ValhallaProvider calls a real Valhalla routing service over HTTP,
+// parsing the trip summary's total length and time and the first leg's
+// polyline into a Route (per-leg distances and maneuver-level durations
+// are not read), and its cost matrix into per-pair ETAs.
+type ValhallaProvider struct {
+	BaseURL    string
+	Costing    string // auto, bicycle, pedestrian
+	HTTPClient *http.Client
+	cache      *routeCache
+}
+
+func NewValhallaProvider(baseURL, costing string) *ValhallaProvider {
+	return &ValhallaProvider{
+		BaseURL:    baseURL,
+		Costing:    costing,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		cache:      newRouteCache(),
+	}
+}
+
+func (v *ValhallaProvider) Directions(start, end Location, departAt time.Time) (Route, error) {
+	key := routeCacheKey(start, end, departAt)
+	if cached, ok := v.cache.get(key); ok {
+		return cached, nil
+	}
+
+	reqBody := valhallaRouteRequest{
+		Locations: []valhallaLocation{
+			{Lat: start.Latitude, Lon: start.Longitude},
+			{Lat: end.Latitude, Lon: end.Longitude},
+		},
+		Costing: v.Costing,
+	}
+	var resp valhallaRouteResponse
+	if err := v.post("/route", reqBody, &resp); err != nil {
+		return Route{}, err
+	}
+
+	route := Route{
+		RouteId:           generateId(),
+		Start:             start,
+		End:               end,
+		Distance:          resp.Trip.Summary.Length,
+		EstimatedDuration: resp.Trip.Summary.Time / 60, // seconds -> minutes
+	}
+	if len(resp.Trip.Legs) > 0 {
+		route.Polyline = resp.Trip.Legs[0].Shape
+	}
+
+	v.cache.put(key, route)
+	return route, nil
+}
+
+func (v *ValhallaProvider) MatrixETA(origins, destinations []Location) ([][]time.Duration, error) {
+	reqBody := valhallaMatrixRequest{
+		Sources: toValhallaLocations(origins),
+		Targets: toValhallaLocations(destinations),
+		Costing: v.Costing,
+	}
+	var resp valhallaMatrixResponse
+	if err := v.post("/sources_to_targets", reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	matrix := make([][]time.Duration, len(resp.SourcesToTargets))
+	for i, row := range resp.SourcesToTargets {
+		matrix[i] = make([]time.Duration, len(row))
+		for j, cell := range row {
+			matrix[i][j] = time.Duration(cell.Time * float64(time.Second))
+		}
+	}
+	return matrix, nil
+}
+
+func (v *ValhallaProvider) post(path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := v.HTTPClient.Post(v.BaseURL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("valhalla: unexpected status %d from %s", resp.StatusCode, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func toValhallaLocations(locations []Location) []valhallaLocation {
+	out := make([]valhallaLocation, len(locations))
+	for i, l := range locations {
+		out[i] = valhallaLocation{Lat: l.Latitude, Lon: l.Longitude}
+	}
+	return out
+}
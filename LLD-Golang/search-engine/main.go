@@ -23,23 +23,38 @@ type Posting struct {
 	Positions []int
 }
 
-// InvertedIndex (core data structure)
-type InvertedIndex map[string][]Posting
+// InvertedIndex is the core search data structure: a term -> postings
+// map, plus the per-doc length and corpus-average length BM25 needs for
+// its length-normalization term.
+type InvertedIndex struct {
+	Postings  map[string][]Posting
+	DocLen    map[string]int // token count per doc, after stopword removal
+	AvgDocLen float64
+}
 
 // Function to build index (Indexing Strategy)
 func BuildIndex(docs []Document) InvertedIndex {
-	index := make(InvertedIndex)
+	index := InvertedIndex{
+		Postings: make(map[string][]Posting),
+		DocLen:   make(map[string]int),
+	}
+	var totalLen int
 	for _, doc := range docs {
 		tokens := tokenize(doc.Content) // Simple split, add stemming in prod
-		termFreq := make(map[string]int)
-		for _, token := range tokens {
-			termFreq[token]++
-			// Add position if needed
+		index.DocLen[doc.ID] = len(tokens)
+		totalLen += len(tokens)
+
+		positions := make(map[string][]int)
+		for pos, token := range tokens {
+			positions[token] = append(positions[token], pos)
 		}
-		for term, freq := range termFreq {
-			index[term] = append(index[term], Posting{DocID: doc.ID, Frequency: freq})
+		for term, pos := range positions {
+			index.Postings[term] = append(index.Postings[term], Posting{DocID: doc.ID, Frequency: len(pos), Positions: pos})
 		}
 	}
+	if len(docs) > 0 {
+		index.AvgDocLen = float64(totalLen) / float64(len(docs))
+	}
 	return index
 }
 
@@ -56,18 +71,29 @@ func tokenize(text string) []string {
 	return tokens
 }
 
-// Ranking: Simple TF-IDF
+// bm25K1 and bm25B are the standard Okapi BM25 defaults: k1 controls how
+// quickly additional term occurrences saturate, b controls how much
+// document-length normalization is applied.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Ranking: Okapi BM25
 func RankResults(queryTerms []string, index InvertedIndex, totalDocs int) []string {
 	scores := make(map[string]float64)
 	for _, term := range queryTerms {
-		postings, ok := index[term]
+		postings, ok := index.Postings[term]
 		if !ok {
 			continue
 		}
-		idf := math.Log(float64(totalDocs) / float64(len(postings)))
+		df := float64(len(postings))
+		idf := math.Log((float64(totalDocs)-df+0.5)/(df+0.5) + 1)
 		for _, p := range postings {
 			tf := float64(p.Frequency)
-			scores[p.DocID] += tf * idf
+			docLen := float64(index.DocLen[p.DocID])
+			denom := tf + bm25K1*(1-bm25B+bm25B*docLen/index.AvgDocLen)
+			scores[p.DocID] += idf * (tf * (bm25K1 + 1)) / denom
 		}
 	}
 	// Sort by score descending
@@ -81,6 +107,84 @@ func RankResults(queryTerms []string, index InvertedIndex, totalDocs int) []stri
 	return ranked
 }
 
+// PhraseQuery returns, sorted ascending, the IDs of every doc where
+// terms appear as a contiguous phrase: postings for every term are
+// intersected on DocID, and within each candidate doc some run of
+// positions p, p+1, p+2, ... (one per term, in order) must exist.
+// Positions are indices into the stopword-stripped token stream, so a
+// phrase like "quick the fox" matches the same as "quick fox" once "the"
+// has been stripped at index time.
+func (index InvertedIndex) PhraseQuery(terms []string) []string {
+	terms = tokenize(strings.Join(terms, " "))
+	if len(terms) == 0 {
+		return nil
+	}
+	postingsByTerm := make([][]Posting, len(terms))
+	for i, term := range terms {
+		postings, ok := index.Postings[term]
+		if !ok {
+			return nil
+		}
+		postingsByTerm[i] = postings
+	}
+
+	firstByDoc := make(map[string][]int, len(postingsByTerm[0]))
+	for _, p := range postingsByTerm[0] {
+		firstByDoc[p.DocID] = p.Positions
+	}
+
+	var matches []string
+docLoop:
+	for docID, firstPositions := range firstByDoc {
+		chain := make([][]int, len(terms))
+		chain[0] = firstPositions
+		for i := 1; i < len(terms); i++ {
+			found := false
+			for _, p := range postingsByTerm[i] {
+				if p.DocID == docID {
+					chain[i] = p.Positions
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue docLoop
+			}
+		}
+		if phraseChainMatches(chain) {
+			matches = append(matches, docID)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// phraseChainMatches reports whether some position in chain[0] starts a
+// run where chain[i] contains start+i for every later term.
+func phraseChainMatches(chain [][]int) bool {
+	sets := make([]map[int]bool, len(chain))
+	for i, positions := range chain {
+		set := make(map[int]bool, len(positions))
+		for _, p := range positions {
+			set[p] = true
+		}
+		sets[i] = set
+	}
+	for _, start := range chain[0] {
+		ok := true
+		for i := 1; i < len(sets); i++ {
+			if !sets[i][start+i] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Autocomplete: Simple Trie
 type TrieNode struct {
 	Children map[rune]*TrieNode
@@ -109,17 +213,66 @@ func (t *Trie) Insert(word string, freq int) {
 }
 
 func (t *Trie) Suggest(prefix string, limit int) []string {
-	// Traverse to prefix node, then DFS for suggestions, sort by freq
-	// Implementation omitted for brevity; return top suggestions.
-	return []string{} // Placeholder
+	node := t.Root
+	for _, ch := range prefix {
+		next, ok := node.Children[ch]
+		if !ok {
+			return nil
+		}
+		node = next
+	}
+
+	type candidate struct {
+		word string
+		freq int
+	}
+	var candidates []candidate
+	var collect func(n *TrieNode, word []rune)
+	collect = func(n *TrieNode, word []rune) {
+		if n.IsEnd {
+			candidates = append(candidates, candidate{word: string(word), freq: n.Freq})
+		}
+		for ch, child := range n.Children {
+			collect(child, append(word, ch))
+		}
+	}
+	collect(node, []rune(prefix))
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].freq != candidates[j].freq {
+			return candidates[i].freq > candidates[j].freq
+		}
+		return candidates[i].word < candidates[j].word
+	})
+
+	if limit > len(candidates) {
+		limit = len(candidates)
+	}
+	out := make([]string, limit)
+	for i := 0; i < limit; i++ {
+		out[i] = candidates[i].word
+	}
+	return out
 }
 
 // Example Usage
 func main() {
-	docs := []Document{{ID: "1", Content: "Hello world search engine"}}
+	docs := []Document{
+		{ID: "1", Content: "Hello world search engine"},
+		{ID: "2", Content: "The quick brown fox jumps over the lazy dog"},
+		{ID: "3", Content: "A fast search engine indexes the world quickly"},
+	}
 	index := BuildIndex(docs)
-	results := RankResults([]string{"search"}, index, len(docs))
+	results := RankResults([]string{"search", "engine"}, index, len(docs))
 	// Output results
-
 	fmt.Println(results)
+
+	phraseMatches := index.PhraseQuery([]string{"search", "engine"})
+	fmt.Println("phrase matches:", phraseMatches)
+
+	trie := NewTrie()
+	trie.Insert("search", 50)
+	trie.Insert("sea", 10)
+	trie.Insert("season", 30)
+	fmt.Println("suggestions:", trie.Suggest("sea", 2))
 }
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"os"
 	"sync"
+	"time"
+
+	"shopping-cart-deisgn/storage"
 )
 
 // ──────────────────────────────────────────────────────────────
@@ -12,6 +18,7 @@ import (
 type Product struct {
 	ID                string
 	Name              string
+	Category          string
 	Price             float64
 	InventoryQuantity int
 }
@@ -26,6 +33,7 @@ type CartItem struct {
 // ──────────────────────────────────────────────────────────────
 type InventoryService struct {
 	products map[string]Product
+	repo     storage.InventoryRepo
 	mu       sync.Mutex
 }
 
@@ -34,16 +42,39 @@ var (
 	inventoryInst *InventoryService
 )
 
+// NewInventoryService builds an InventoryService backed by repo. Pass
+// storage.NewInMemoryInventoryRepo() for the old zero-dependency
+// behavior, or a storage.GormInventoryRepo to survive a restart.
+func NewInventoryService(repo storage.InventoryRepo) *InventoryService {
+	return &InventoryService{
+		products: make(map[string]Product),
+		repo:     repo,
+	}
+}
+
+// Inventory returns the process-wide InventoryService singleton, backed
+// by an in-memory repo so existing callers keep working unchanged.
 func Inventory() *InventoryService {
 	inventoryOnce.Do(func() {
-		inventoryInst = &InventoryService{
-			products: make(map[string]Product),
-		}
+		inventoryInst = NewInventoryService(storage.NewInMemoryInventoryRepo())
 	})
 	return inventoryInst
 }
 
-func (is *InventoryService) AddProduct(p Product) { is.products[p.ID] = p }
+func (is *InventoryService) AddProduct(p Product) {
+	is.products[p.ID] = p
+	is.persist(p)
+}
+
+// persist mirrors p into the repo, logging rather than failing the
+// caller if the write doesn't go through - the in-memory map stays the
+// source of truth for this process either way.
+func (is *InventoryService) persist(p Product) {
+	storageProduct := storage.Product{ID: p.ID, Name: p.Name, Price: p.Price, InventoryQuantity: p.InventoryQuantity}
+	if err := is.repo.Save(context.Background(), storageProduct); err != nil {
+		fmt.Fprintf(os.Stderr, "inventory: failed to persist %q: %v\n", p.ID, err)
+	}
+}
 func (is *InventoryService) Get(id string) (*Product, error) {
 	p, ok := is.products[id]
 	if !ok {
@@ -67,6 +98,7 @@ func (is *InventoryService) Reserve(id string, qty int) error {
 	}
 	p.InventoryQuantity -= qty
 	is.products[id] = *p
+	is.persist(*p)
 	return nil
 }
 func (is *InventoryService) Release(id string, qty int) {
@@ -75,21 +107,20 @@ func (is *InventoryService) Release(id string, qty int) {
 	if p, ok := is.products[id]; ok {
 		p.InventoryQuantity += qty
 		is.products[id] = p
+		is.persist(p)
 	}
 }
 
 // ──────────────────────────────────────────────────────────────
-// 3. DISCOUNT SERVICE (Singleton + Strategy for rules)
+// 3. DISCOUNT SERVICE (Singleton over a RuleEngine - see discount.go for
+//    the Rule/RuleEngine/Receipt machinery this now delegates to)
 // ──────────────────────────────────────────────────────────────
-type DiscountRule struct {
-	Code     string
-	Percent  float64 // 0.1 = 10%
-	MinTotal float64 // optional condition
-}
 
+// DiscountService is the process-wide rule engine for cart discounts -
+// same singleton accessor as before, now backed by a RuleEngine instead
+// of a flat DiscountRule list.
 type DiscountService struct {
-	rules []DiscountRule
-	mu    sync.Mutex
+	*RuleEngine
 }
 
 var (
@@ -97,29 +128,20 @@ var (
 	discountInst *DiscountService
 )
 
+// Discount returns the process-wide DiscountService singleton, seeded
+// with the two promo codes the demo used to hard-code.
 func Discount() *DiscountService {
 	discountOnce.Do(func() {
-		discountInst = &DiscountService{
-			rules: []DiscountRule{
-				{Code: "SWIGGY10", Percent: 0.10},
-				{Code: "FIRST50", Percent: 0.50, MinTotal: 100},
-			},
-		}
+		engine := NewRuleEngine()
+		engine.Register(
+			NewPercentOff("SWIGGY10", 0.10, 1),
+			NewFirstOrderOnly(NewPercentOff("FIRST50", 0.50, 2)),
+		)
+		discountInst = &DiscountService{RuleEngine: engine}
 	})
 	return discountInst
 }
 
-func (ds *DiscountService) Apply(subtotal float64, code string) float64 {
-	ds.mu.Lock()
-	defer ds.mu.Unlock()
-	for _, r := range ds.rules {
-		if r.Code == code && subtotal >= r.MinTotal {
-			return subtotal * (1 - r.Percent)
-		}
-	}
-	return subtotal // no discount
-}
-
 // ──────────────────────────────────────────────────────────────
 // 4. PAYMENT STRATEGY
 // ──────────────────────────────────────────────────────────────
@@ -192,24 +214,62 @@ func (c ConsoleObserver) Update(msg string) {
 // 6. CART (core domain) – uses all services
 // ──────────────────────────────────────────────────────────────
 type Cart struct {
-	UserID string
-	Items  []CartItem
-	Total  float64
+	UserID      string
+	Items       []CartItem
+	Total       float64
+	LastReceipt *Receipt
 
 	// injected services (DI)
 	inventory *InventoryService
 	discount  *DiscountService
 	payment   PaymentService
 	notifier  *NotificationService
+	repo      storage.CartRepo
+
+	promoCodes   []string
+	isFirstOrder bool
 }
 
-func NewCart(userID string, payment PaymentService) *Cart {
+// persist mirrors the cart's current state into repo, if one was
+// configured via WithCartRepo. Logs rather than fails the caller on a
+// write error, matching InventoryService.persist.
+func (c *Cart) persist() {
+	if c.repo == nil {
+		return
+	}
+	items := make([]storage.CartItem, len(c.Items))
+	for i, it := range c.Items {
+		items[i] = storage.CartItem{ProductID: it.Product.ID, Quantity: it.Quantity}
+	}
+	state := storage.CartState{UserID: c.UserID, Items: items, Total: c.Total}
+	if err := c.repo.Save(context.Background(), state); err != nil {
+		fmt.Fprintf(os.Stderr, "cart: failed to persist %q: %v\n", c.UserID, err)
+	}
+}
+
+// CartOption configures optional Cart behavior at construction time,
+// mirroring the CacheOption pattern used for inmemorycache.Cache.
+type CartOption func(*Cart)
+
+// WithCartRepo wires repo into the cart: every mutation is persisted
+// through it, so a restart can resume from storage.CartRepo.FindByUserID
+// instead of starting empty. Without this option Cart stays purely
+// in-memory, as it always was.
+func WithCartRepo(repo storage.CartRepo) CartOption {
+	return func(c *Cart) { c.repo = repo }
+}
+
+func NewCart(userID string, payment PaymentService, opts ...CartOption) *Cart {
 	c := &Cart{
-		UserID:    userID,
-		inventory: Inventory(),
-		discount:  Discount(),
-		payment:   payment,
-		notifier:  Notifier(),
+		UserID:       userID,
+		inventory:    Inventory(),
+		discount:     Discount(),
+		payment:      payment,
+		notifier:     Notifier(),
+		isFirstOrder: true,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 	// auto-subscribe cart as observer for its own events
 	c.notifier.Subscribe(c) // Cart implements Observer for internal logging
@@ -235,13 +295,13 @@ func (c *Cart) Add(productID string, qty int) error {
 	for i := range c.Items {
 		if c.Items[i].Product.ID == productID {
 			c.Items[i].Quantity += qty
-			c.recalculate("")
+			c.recalculate()
 			c.notifier.Send(fmt.Sprintf("Added %d more %s", qty, p.Name))
 			return nil
 		}
 	}
 	c.Items = append(c.Items, CartItem{Product: p, Quantity: qty})
-	c.recalculate("")
+	c.recalculate()
 	c.notifier.Send(fmt.Sprintf("Added %s (x%d)", p.Name, qty))
 	return nil
 }
@@ -257,7 +317,7 @@ func (c *Cart) Remove(productID string, qty int) error {
 			if c.Items[i].Quantity == 0 {
 				c.Items = append(c.Items[:i], c.Items[i+1:]...)
 			}
-			c.recalculate("")
+			c.recalculate()
 			c.notifier.Send(fmt.Sprintf("Removed %d of %s", qty, c.Items[i].Product.Name))
 			return nil
 		}
@@ -270,7 +330,7 @@ func (c *Cart) Delete(productID string) error {
 		if c.Items[i].Product.ID == productID {
 			c.inventory.Release(productID, c.Items[i].Quantity)
 			c.Items = append(c.Items[:i], c.Items[i+1:]...)
-			c.recalculate("")
+			c.recalculate()
 			c.notifier.Send("Deleted " + c.Items[i].Product.Name + " from cart")
 			return nil
 		}
@@ -278,17 +338,40 @@ func (c *Cart) Delete(productID string) error {
 	return errors.New("item not found")
 }
 
-func (c *Cart) recalculate(promo string) {
+// recalculate is the single choke point every mutation routes through,
+// so it's also where the cart's persisted state gets refreshed. It
+// evaluates c.promoCodes (set via ApplyPromoCodes/Checkout) against the
+// current items through the RuleEngine, rather than a single promo code
+// against a bare subtotal.
+func (c *Cart) recalculate() {
 	sub := 0.0
 	for _, it := range c.Items {
 		sub += it.Product.Price * float64(it.Quantity)
 	}
-	c.Total = c.discount.Apply(sub, promo)
+	ctx := &CartContext{
+		UserID:       c.UserID,
+		Items:        c.Items,
+		Subtotal:     sub,
+		AppliedCodes: c.promoCodes,
+		IsFirstOrder: c.isFirstOrder,
+		Now:          time.Now(),
+	}
+	receipt := c.discount.Evaluate(ctx)
+	c.LastReceipt = &receipt
+	c.Total = receipt.Total
+	c.persist()
+}
+
+// ApplyPromoCodes stages promo codes for the next recalculate - used by
+// Checkout, and available on its own to preview a Receipt before paying.
+func (c *Cart) ApplyPromoCodes(codes []string) {
+	c.promoCodes = codes
+	c.recalculate()
 }
 
 // Checkout uses Builder-like fluent API (optional)
-func (c *Cart) Checkout(promo string) error {
-	c.recalculate(promo)
+func (c *Cart) Checkout(promoCodes []string) error {
+	c.ApplyPromoCodes(promoCodes)
 	ok, err := c.payment.Process(c.Total)
 	if err != nil || !ok {
 		c.notifier.Send("Payment failed")
@@ -297,6 +380,10 @@ func (c *Cart) Checkout(promo string) error {
 	c.notifier.Send(fmt.Sprintf("Order placed! Total: %.2f", c.Total))
 	c.Items = nil
 	c.Total = 0
+	c.LastReceipt = nil
+	c.promoCodes = nil
+	c.isFirstOrder = false
+	c.persist()
 	return nil
 }
 
@@ -304,25 +391,46 @@ func (c *Cart) Checkout(promo string) error {
 // 7. DEMO MAIN
 // ──────────────────────────────────────────────────────────────
 func main() {
+	// `go run . migrate up|down|status` manages schema_migrations against
+	// whatever DATABASE_DRIVER/DATABASE_URL point at (Postgres or SQLite -
+	// migrateUp/Down/Status only use database/sql, not GORM, so either
+	// works once the matching driver is blank-imported into the build).
+	if driver := os.Getenv("DATABASE_DRIVER"); driver != "" {
+		db, err := sql.Open(driver, os.Getenv("DATABASE_URL"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: open %s: %v\n", driver, err)
+			os.Exit(1)
+		}
+		defer db.Close()
+		if handled, err := runMigrateCLI(os.Args, db); handled {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// seed inventory
-	Inventory().AddProduct(Product{ID: "p1", Name: "Margherita Pizza", Price: 12.99, InventoryQuantity: 10})
-	Inventory().AddProduct(Product{ID: "p2", Name: "Veggie Burger", Price: 5.99, InventoryQuantity: 20})
+	Inventory().AddProduct(Product{ID: "p1", Name: "Margherita Pizza", Category: "food", Price: 12.99, InventoryQuantity: 10})
+	Inventory().AddProduct(Product{ID: "p2", Name: "Veggie Burger", Category: "food", Price: 5.99, InventoryQuantity: 20})
 
 	// register console observer (could be email, push, etc.)
 	Notifier().Subscribe(ConsoleObserver{})
 
-	// create cart with mock payment
+	// create cart with mock payment; WithCartRepo(storage.NewGormCartRepo(db))
+	// would make it durable across restarts instead.
 	cart := NewCart("u123", MockPayment{})
 
 	_ = cart.Add("p1", 2) // success
 	_ = cart.Add("p2", 3) // success
 	_ = cart.Add("p1", 9) // fail → insufficient stock
 
-	cart.recalculate("SWIGGY10")
+	cart.ApplyPromoCodes([]string{"SWIGGY10"})
 	fmt.Printf("Subtotal after discount: %.2f\n", cart.Total)
 
 	_ = cart.Remove("p2", 1)
 	_ = cart.Delete("p1")
 
-	_ = cart.Checkout("SWIGGY10")
+	_ = cart.Checkout([]string{"SWIGGY10"})
 }
\ No newline at end of file
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migration is one numbered .sql file split into its Up and Down halves.
+//
+// Rockhopper-style format: a single file per version, with the two
+// halves separated by "-- +migrate Up" / "-- +migrate Down" marker
+// comments, e.g. migrations/0001_init.sql.
+type migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+var migrationFilename = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+
+// loadMigrations reads every *.sql file in dir, in version order.
+func loadMigrations(dir string) ([]migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read %s: %w", dir, err)
+	}
+
+	var out []migration
+	for _, entry := range entries {
+		m := migrationFilename.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("migrate: bad version in %s: %w", entry.Name(), err)
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", entry.Name(), err)
+		}
+		up, down, err := splitMigration(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", entry.Name(), err)
+		}
+		out = append(out, migration{Version: version, Name: m[2], Up: up, Down: down})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+func splitMigration(content string) (up, down string, err error) {
+	const upMarker, downMarker = "-- +migrate Up", "-- +migrate Down"
+	upIdx := strings.Index(content, upMarker)
+	downIdx := strings.Index(content, downMarker)
+	if upIdx == -1 || downIdx == -1 || downIdx < upIdx {
+		return "", "", fmt.Errorf("missing %q / %q markers", upMarker, downMarker)
+	}
+	return strings.TrimSpace(content[upIdx+len(upMarker) : downIdx]), strings.TrimSpace(content[downIdx+len(downMarker):]), nil
+}
+
+// ensureSchemaMigrationsTable creates the bookkeeping table migrate
+// itself depends on, if it isn't there yet.
+func ensureSchemaMigrationsTable(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	return err
+}
+
+func appliedVersions(db *sql.DB) (map[int]bool, error) {
+	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// migrateUp applies every migration in dir not yet recorded in
+// schema_migrations, in order.
+func migrateUp(db *sql.DB, dir string) error {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return fmt.Errorf("migrate: ensure schema_migrations: %w", err)
+	}
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return fmt.Errorf("migrate: read applied versions: %w", err)
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if _, err := db.Exec(m.Up); err != nil {
+			return fmt.Errorf("migrate: apply %04d_%s: %w", m.Version, m.Name, err)
+		}
+		if _, err := db.Exec(fmt.Sprintf(`INSERT INTO schema_migrations (version) VALUES (%d)`, m.Version)); err != nil {
+			return fmt.Errorf("migrate: record %04d_%s: %w", m.Version, m.Name, err)
+		}
+		fmt.Printf("migrate: applied %04d_%s\n", m.Version, m.Name)
+	}
+	return nil
+}
+
+// migrateDown reverts the single most recently applied migration.
+func migrateDown(db *sql.DB, dir string) error {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return fmt.Errorf("migrate: ensure schema_migrations: %w", err)
+	}
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return fmt.Errorf("migrate: read applied versions: %w", err)
+	}
+
+	latest := -1
+	for _, m := range migrations {
+		if applied[m.Version] && m.Version > latest {
+			latest = m.Version
+		}
+	}
+	if latest == -1 {
+		fmt.Println("migrate: nothing to roll back")
+		return nil
+	}
+	for _, m := range migrations {
+		if m.Version != latest {
+			continue
+		}
+		if _, err := db.Exec(m.Down); err != nil {
+			return fmt.Errorf("migrate: revert %04d_%s: %w", m.Version, m.Name, err)
+		}
+		if _, err := db.Exec(fmt.Sprintf(`DELETE FROM schema_migrations WHERE version = %d`, m.Version)); err != nil {
+			return fmt.Errorf("migrate: unrecord %04d_%s: %w", m.Version, m.Name, err)
+		}
+		fmt.Printf("migrate: reverted %04d_%s\n", m.Version, m.Name)
+	}
+	return nil
+}
+
+// migrateStatus prints every migration in dir with whether it's applied.
+func migrateStatus(db *sql.DB, dir string) error {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return fmt.Errorf("migrate: ensure schema_migrations: %w", err)
+	}
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return fmt.Errorf("migrate: read applied versions: %w", err)
+	}
+
+	for _, m := range migrations {
+		status := "pending"
+		if applied[m.Version] {
+			status = "applied"
+		}
+		fmt.Printf("%04d_%-30s %s\n", m.Version, m.Name, status)
+	}
+	return nil
+}
+
+// runMigrateCLI handles `go run . migrate up|down|status` against db,
+// covering both Postgres and SQLite since it only relies on database/sql.
+// It returns true if args requested a migrate subcommand (and therefore
+// handled it), so callers know not to fall through to the normal demo.
+func runMigrateCLI(args []string, db *sql.DB) (bool, error) {
+	if len(args) < 2 || args[1] != "migrate" {
+		return false, nil
+	}
+	if len(args) < 3 {
+		return true, fmt.Errorf("migrate: usage: migrate up|down|status")
+	}
+	dir := "migrations"
+	switch args[2] {
+	case "up":
+		return true, migrateUp(db, dir)
+	case "down":
+		return true, migrateDown(db, dir)
+	case "status":
+		return true, migrateStatus(db, dir)
+	default:
+		return true, fmt.Errorf("migrate: unknown subcommand %q", args[2])
+	}
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ProductRow is the GORM row backing InventoryRepo, following the same
+// gorm.Model-embedding convention as model.User/model.Ride in
+// go-cab-booking-system.
+type ProductRow struct {
+	gorm.Model
+	ProductID         string `gorm:"uniqueIndex"`
+	Name              string
+	Price             float64
+	InventoryQuantity int
+}
+
+// CartRow and CartItemRow together back CartRepo: one CartRow per user,
+// with its items in a one-to-many CartItemRow table.
+type CartRow struct {
+	gorm.Model
+	UserID string      `gorm:"uniqueIndex"`
+	Total  float64
+	Items  []CartItemRow `gorm:"foreignKey:CartRowID"`
+}
+
+type CartItemRow struct {
+	gorm.Model
+	CartRowID uint
+	ProductID string
+	Quantity  int
+}
+
+// GormInventoryRepo is the production InventoryRepo, backed by Postgres
+// or SQLite through GORM's respective drivers depending on how db was
+// opened.
+type GormInventoryRepo struct {
+	db *gorm.DB
+}
+
+func NewGormInventoryRepo(db *gorm.DB) *GormInventoryRepo {
+	return &GormInventoryRepo{db: db}
+}
+
+func (r *GormInventoryRepo) Save(ctx context.Context, p Product) error {
+	row := ProductRow{
+		ProductID:         p.ID,
+		Name:              p.Name,
+		Price:             p.Price,
+		InventoryQuantity: p.InventoryQuantity,
+	}
+	return r.db.WithContext(ctx).
+		Where(ProductRow{ProductID: p.ID}).
+		// A map rather than a struct, so a zero InventoryQuantity is still written.
+		Assign(map[string]interface{}{
+			"name":               p.Name,
+			"price":              p.Price,
+			"inventory_quantity": p.InventoryQuantity,
+		}).
+		FirstOrCreate(&row).Error
+}
+
+func (r *GormInventoryRepo) FindByID(ctx context.Context, id string) (Product, error) {
+	var row ProductRow
+	err := r.db.WithContext(ctx).Where("product_id = ?", id).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Product{}, ErrNotFound
+	}
+	if err != nil {
+		return Product{}, err
+	}
+	return Product{ID: row.ProductID, Name: row.Name, Price: row.Price, InventoryQuantity: row.InventoryQuantity}, nil
+}
+
+func (r *GormInventoryRepo) All(ctx context.Context) ([]Product, error) {
+	var rows []ProductRow
+	if err := r.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]Product, len(rows))
+	for i, row := range rows {
+		out[i] = Product{ID: row.ProductID, Name: row.Name, Price: row.Price, InventoryQuantity: row.InventoryQuantity}
+	}
+	return out, nil
+}
+
+// GormCartRepo is the production CartRepo.
+type GormCartRepo struct {
+	db *gorm.DB
+}
+
+func NewGormCartRepo(db *gorm.DB) *GormCartRepo {
+	return &GormCartRepo{db: db}
+}
+
+func (r *GormCartRepo) Save(ctx context.Context, state CartState) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row CartRow
+		// A map rather than a struct, so a zero Total is still written.
+		err := tx.Where(CartRow{UserID: state.UserID}).Assign(map[string]interface{}{"total": state.Total}).FirstOrCreate(&row).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("cart_row_id = ?", row.ID).Delete(&CartItemRow{}).Error; err != nil {
+			return err
+		}
+		items := make([]CartItemRow, len(state.Items))
+		for i, it := range state.Items {
+			items[i] = CartItemRow{CartRowID: row.ID, ProductID: it.ProductID, Quantity: it.Quantity}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+func (r *GormCartRepo) FindByUserID(ctx context.Context, userID string) (CartState, error) {
+	var row CartRow
+	err := r.db.WithContext(ctx).Preload("Items").Where("user_id = ?", userID).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return CartState{}, ErrNotFound
+	}
+	if err != nil {
+		return CartState{}, err
+	}
+	items := make([]CartItem, len(row.Items))
+	for i, it := range row.Items {
+		items[i] = CartItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	return CartState{UserID: row.UserID, Items: items, Total: row.Total}, nil
+}
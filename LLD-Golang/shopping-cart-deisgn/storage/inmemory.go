@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryInventoryRepo is the default InventoryRepo, used when no
+// database is wired up.
+type InMemoryInventoryRepo struct {
+	mu       sync.Mutex
+	products map[string]Product
+}
+
+func NewInMemoryInventoryRepo() *InMemoryInventoryRepo {
+	return &InMemoryInventoryRepo{products: make(map[string]Product)}
+}
+
+func (r *InMemoryInventoryRepo) Save(ctx context.Context, p Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products[p.ID] = p
+	return nil
+}
+
+func (r *InMemoryInventoryRepo) FindByID(ctx context.Context, id string) (Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	if !ok {
+		return Product{}, ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *InMemoryInventoryRepo) All(ctx context.Context) ([]Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Product, 0, len(r.products))
+	for _, p := range r.products {
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// InMemoryCartRepo is the default CartRepo, used when no database is
+// wired up.
+type InMemoryCartRepo struct {
+	mu    sync.Mutex
+	carts map[string]CartState
+}
+
+func NewInMemoryCartRepo() *InMemoryCartRepo {
+	return &InMemoryCartRepo{carts: make(map[string]CartState)}
+}
+
+func (r *InMemoryCartRepo) Save(ctx context.Context, state CartState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.carts[state.UserID] = state
+	return nil
+}
+
+func (r *InMemoryCartRepo) FindByUserID(ctx context.Context, userID string) (CartState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.carts[userID]
+	if !ok {
+		return CartState{}, ErrNotFound
+	}
+	return state, nil
+}
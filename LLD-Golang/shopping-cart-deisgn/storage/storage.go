@@ -0,0 +1,54 @@
+// Package storage gives Cart and InventoryService a durable system of
+// record instead of the process-memory maps they started with.
+//
+// WHY: a restart used to wipe every cart and every inventory count; the
+// Repository interfaces here let a caller swap in a real database while
+// the in-memory implementation keeps `go run .` working with zero
+// external dependencies - the same tradeoff splitwise/eventstore already
+// makes between InMemoryEventStore and PostgresEventStore.
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by a repo when the requested row doesn't exist.
+var ErrNotFound = errors.New("storage: not found")
+
+// Product mirrors the cart domain's Product. It's redeclared here rather
+// than imported from package main so this package doesn't have to depend
+// on the very package that depends on it.
+type Product struct {
+	ID                string
+	Name              string
+	Price             float64
+	InventoryQuantity int
+}
+
+// CartItem mirrors the cart domain's CartItem, by product ID rather than
+// a *Product pointer so a CartState round-trips through a database row.
+type CartItem struct {
+	ProductID string
+	Quantity  int
+}
+
+// CartState is everything Cart needs to resume after a restart.
+type CartState struct {
+	UserID string
+	Items  []CartItem
+	Total  float64
+}
+
+// InventoryRepo persists Product rows.
+type InventoryRepo interface {
+	Save(ctx context.Context, p Product) error
+	FindByID(ctx context.Context, id string) (Product, error)
+	All(ctx context.Context) ([]Product, error)
+}
+
+// CartRepo persists one CartState per user.
+type CartRepo interface {
+	Save(ctx context.Context, state CartState) error
+	FindByUserID(ctx context.Context, userID string) (CartState, error)
+}
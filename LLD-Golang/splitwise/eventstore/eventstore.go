@@ -0,0 +1,211 @@
+// Package eventstore gives aggregates (User, Expense, ...) a durable,
+// replayable system of record. Instead of mutating in-memory state directly,
+// callers append typed events and rebuild state by folding the event log.
+//
+// WHY: Splitwise balances must be auditable and recoverable after a crash.
+// An append-only log plus a pure reducer gives us both for free. Same
+// tradeoff as meeting-schedular/storage and shopping-cart-deisgn/storage.
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Known event types for the splitwise domain.
+// WHY: Versioned type strings let us evolve the schema without breaking
+// events already on disk (e.g. a future ExpenseCreatedV2).
+const (
+	ExpenseCreatedV1   = "ExpenseCreatedV1"
+	SplitAppliedV1     = "SplitAppliedV1"
+	BalanceAdjustedV1  = "BalanceAdjustedV1"
+	UserRegisteredV1   = "UserRegisteredV1"
+)
+
+// Event is one fact appended to an aggregate's log.
+// WHY: Seq gives us ordering and doubles as the optimistic-concurrency token.
+type Event struct {
+	AggregateID string          `json:"aggregate_id"`
+	Seq         int64           `json:"seq"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+	Ts          time.Time       `json:"ts"`
+}
+
+// Aggregate is anything that can be rebuilt by folding events over a zero
+// value. WHY: lets Rehydrate be generic across User, Expense, etc.
+type Aggregate interface {
+	// Apply folds one event into the aggregate's own state in place,
+	// returning an error if the event cannot be applied. Must not do I/O
+	// or change anything outside the aggregate.
+	Apply(event Event) error
+}
+
+// EventStore is the contract every aggregate manager depends on.
+// WHY: keeps ExpenseManager/UserManager ignorant of whether events live
+// in memory or Postgres.
+type EventStore interface {
+	// SaveEvents appends events for aggregateID starting at expectedSeq+1.
+	// It must fail with a concurrency error if another writer already
+	// advanced the aggregate past expectedSeq.
+	SaveEvents(ctx context.Context, aggregateID string, expectedSeq int64, events []Event) error
+	GetEvents(ctx context.Context, aggregateID string) ([]Event, error)
+}
+
+// ErrConcurrencyConflict is returned when expectedSeq no longer matches the
+// aggregate's latest seq in the store.
+type ErrConcurrencyConflict struct {
+	AggregateID string
+	Expected    int64
+	Actual      int64
+}
+
+func (e *ErrConcurrencyConflict) Error() string {
+	return fmt.Sprintf("eventstore: concurrency conflict on %s: expected seq %d, store is at %d", e.AggregateID, e.Expected, e.Actual)
+}
+
+// EventBus lets downstream subscribers (notifications, projections, ...)
+// react to committed events without EventStore knowing about them.
+// WHY: matches the Subscribe/Publish shape already used by Subscription
+// in the notification file, so the two subsystems feel like one codebase.
+type EventBus struct {
+	mu   sync.Mutex
+	subs []func(Event)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+func (b *EventBus) Subscribe(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, fn)
+}
+
+func (b *EventBus) Publish(evt Event) {
+	b.mu.Lock()
+	subs := append([]func(Event){}, b.subs...)
+	b.mu.Unlock()
+	for _, fn := range subs {
+		fn(evt)
+	}
+}
+
+// InMemoryEventStore is the default store used by the demo in main.go.
+// WHY: keeps `go run .` working with zero external dependencies while still
+// exercising the exact same interface Postgres will serve in production.
+type InMemoryEventStore struct {
+	mu   sync.Mutex
+	logs map[string][]Event
+}
+
+func NewInMemoryEventStore() *InMemoryEventStore {
+	return &InMemoryEventStore{logs: make(map[string][]Event)}
+}
+
+func (s *InMemoryEventStore) SaveEvents(ctx context.Context, aggregateID string, expectedSeq int64, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log := s.logs[aggregateID]
+	actual := int64(len(log))
+	if actual != expectedSeq {
+		return &ErrConcurrencyConflict{AggregateID: aggregateID, Expected: expectedSeq, Actual: actual}
+	}
+	for i, e := range events {
+		e.AggregateID = aggregateID
+		e.Seq = expectedSeq + int64(i) + 1
+		log = append(log, e)
+	}
+	s.logs[aggregateID] = log
+	return nil
+}
+
+func (s *InMemoryEventStore) GetEvents(ctx context.Context, aggregateID string) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Event, len(s.logs[aggregateID]))
+	copy(out, s.logs[aggregateID])
+	sort.Slice(out, func(i, j int) bool { return out[i].Seq < out[j].Seq })
+	return out, nil
+}
+
+// GetEventsAfter implements EventsAfterStore so RehydrateWithSnapshot can
+// skip straight to events newer than a snapshot without refetching the
+// whole log.
+func (s *InMemoryEventStore) GetEventsAfter(ctx context.Context, aggregateID string, seq int64) ([]Event, error) {
+	all, err := s.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	out := all[:0:0]
+	for _, e := range all {
+		if e.Seq > seq {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// InMemorySnapshotStore is the zero-dependency counterpart to
+// InMemoryEventStore, used by the same demo wiring in main.go.
+type InMemorySnapshotStore struct {
+	mu   sync.Mutex
+	snap map[string]struct {
+		seq   int64
+		state json.RawMessage
+	}
+}
+
+func NewInMemorySnapshotStore() *InMemorySnapshotStore {
+	return &InMemorySnapshotStore{snap: make(map[string]struct {
+		seq   int64
+		state json.RawMessage
+	})}
+}
+
+func (s *InMemorySnapshotStore) SaveSnapshot(ctx context.Context, aggregateID string, seq int64, state json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.snap[aggregateID]; ok && existing.seq >= seq {
+		// WHY: snapshots can race through the async worker pool; keep the
+		// newest one deterministically rather than whichever lands last.
+		return nil
+	}
+	s.snap[aggregateID] = struct {
+		seq   int64
+		state json.RawMessage
+	}{seq, state}
+	return nil
+}
+
+func (s *InMemorySnapshotStore) LoadLatestSnapshot(ctx context.Context, aggregateID string) (int64, json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.snap[aggregateID]
+	if !ok {
+		return 0, nil, nil
+	}
+	return entry.seq, entry.state, nil
+}
+
+// Rehydrate folds every event for aggregateID, in seq order, into agg.
+// WHY: single choke point so UserManager/ExpenseManager rebuild state the
+// same way on every code path (startup, tests, manual replay).
+func Rehydrate(ctx context.Context, store EventStore, aggregateID string, agg Aggregate) error {
+	events, err := store.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return fmt.Errorf("eventstore: rehydrate %s: %w", aggregateID, err)
+	}
+	for _, e := range events {
+		if err := agg.Apply(e); err != nil {
+			return fmt.Errorf("eventstore: rehydrate %s at seq %d: %w", aggregateID, e.Seq, err)
+		}
+	}
+	return nil
+}
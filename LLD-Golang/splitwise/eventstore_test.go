@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"splitwise/eventstore"
+)
+
+// newTestUserManager builds a standalone UserManager (bypassing the
+// GetUserManager singleton) so a test can point two independent managers
+// at the same EventStore to simulate a crash-and-restart.
+func newTestUserManager(store eventstore.EventStore, snaps eventstore.SnapshotStore) *UserManager {
+	policy := eventstore.SnapshotPolicy{EveryNEvents: 20}
+	return &UserManager{
+		users:  make(map[string]*User),
+		seqs:   make(map[string]int64),
+		store:  store,
+		bus:    eventstore.NewEventBus(),
+		snaps:  snaps,
+		worker: eventstore.NewSnapshotWorker(snaps, policy, 2, 64),
+	}
+}
+
+// TestRehydrateMatchesLiveState proves the crash-recovery story the
+// request asked for: a second UserManager pointed at the same
+// EventStore, with no in-memory state of its own, replays the exact
+// same BalanceAdjustedV1 history and lands on identical User.Net.
+func TestRehydrateMatchesLiveState(t *testing.T) {
+	store := eventstore.NewInMemoryEventStore()
+	snaps := eventstore.NewInMemorySnapshotStore()
+
+	live := newTestUserManager(store, snaps)
+	u := NewUser("Alice", "alice@example.com", "555-0100", "USD")
+	live.AddUser(u)
+	live.AddPay(u.UserID, 120.50)
+	live.AddBorrow(u.UserID, 40.25)
+	live.AddBorrow(u.UserID, 10.00)
+
+	wantNet := live.GetNet(u.UserID)
+	if wantNet == 0 {
+		t.Fatalf("expected a non-zero Net after pays/borrows, got %v", wantNet)
+	}
+
+	// Simulate the process crashing: a brand-new UserManager, sharing
+	// only the durable EventStore, rebuilds state purely by replay.
+	recovered := newTestUserManager(store, snaps)
+	rehydrated, err := recovered.Rehydrate(context.Background(), u.UserID)
+	if err != nil {
+		t.Fatalf("Rehydrate: %v", err)
+	}
+
+	if rehydrated.Net != wantNet {
+		t.Fatalf("rehydrated Net = %v, want %v (live state before crash)", rehydrated.Net, wantNet)
+	}
+	if rehydrated.TotalPays != u.TotalPays || rehydrated.TotalBorrows != u.TotalBorrows {
+		t.Fatalf("rehydrated totals = (%v, %v), want (%v, %v)",
+			rehydrated.TotalPays, rehydrated.TotalBorrows, u.TotalPays, u.TotalBorrows)
+	}
+}
+
+// TestRehydrateIsDeterministicAcrossRuns replays the same log twice from
+// scratch and checks both replays agree, guarding against Apply picking
+// up any non-deterministic or ordering-dependent behavior.
+func TestRehydrateIsDeterministicAcrossRuns(t *testing.T) {
+	store := eventstore.NewInMemoryEventStore()
+	snaps := eventstore.NewInMemorySnapshotStore()
+
+	live := newTestUserManager(store, snaps)
+	u := NewUser("Bob", "bob@example.com", "555-0101", "USD")
+	live.AddUser(u)
+	live.AddPay(u.UserID, 75.00)
+	live.AddBorrow(u.UserID, 25.00)
+
+	for i := 0; i < 2; i++ {
+		fresh := newTestUserManager(store, snaps)
+		got, err := fresh.Rehydrate(context.Background(), u.UserID)
+		if err != nil {
+			t.Fatalf("replay %d: Rehydrate: %v", i, err)
+		}
+		if got.Net != u.Net {
+			t.Fatalf("replay %d: Net = %v, want %v", i, got.Net, u.Net)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FXProvider resolves a conversion rate between two ISO 4217 currencies as
+// of a point in time.
+// WHY: splits must stay reproducible on replay, so the rate used for a past
+// expense has to be addressable by date, not just "whatever the rate is now".
+type FXProvider interface {
+	Rate(ctx context.Context, from, to string, at time.Time) (float64, error)
+}
+
+// InMemoryFXProvider serves a fixed table of rates and is the default used
+// by the demo in main.go.
+// WHY: keeps `go run .` deterministic and dependency-free; a pluggable
+// implementation can hit a live FX API without touching any caller.
+type InMemoryFXProvider struct {
+	mu    sync.RWMutex
+	rates map[string]float64 // "FROM_TO" -> rate
+}
+
+func NewInMemoryFXProvider() *InMemoryFXProvider {
+	return &InMemoryFXProvider{rates: make(map[string]float64)}
+}
+
+// SetRate registers a rate to use for any AsOf time (this demo has no
+// historical rate table - a pluggable provider would key on `at`).
+func (p *InMemoryFXProvider) SetRate(from, to string, rate float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates[from+"_"+to] = rate
+}
+
+func (p *InMemoryFXProvider) Rate(ctx context.Context, from, to string, at time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	rate, ok := p.rates[from+"_"+to]
+	if !ok {
+		return 0, fmt.Errorf("fx: no rate registered for %s->%s", from, to)
+	}
+	return rate, nil
+}
+
+var (
+	fxProviderInstance FXProvider = NewInMemoryFXProvider()
+	fxProviderMu       sync.RWMutex
+)
+
+// SetFXProvider swaps the process-wide FXProvider, e.g. to plug in a live
+// rate source. WHY: a process-wide instance, like GetUserManager/
+// GetExpenseManager (which have no setter), so a provider need not be
+// threaded through every call site.
+func SetFXProvider(p FXProvider) {
+	fxProviderMu.Lock()
+	defer fxProviderMu.Unlock()
+	fxProviderInstance = p
+}
+
+func getFXProvider() FXProvider {
+	fxProviderMu.RLock()
+	defer fxProviderMu.RUnlock()
+	return fxProviderInstance
+}
+
+// convertSplits converts each split's Amount from the expense currency into
+// each user's HomeCurrency, using the expense's AsOf time so replay is
+// deterministic. The input splits (native currency) are left untouched for
+// auditability; only the returned copy is in home-currency terms.
+func convertSplits(ctx context.Context, splits []Split, fromCurrency string, asOf time.Time, um *UserManager) ([]Split, error) {
+	out := make([]Split, len(splits))
+	for i, s := range splits {
+		u := um.GetUser(s.User)
+		to := fromCurrency
+		if u != nil && u.HomeCurrency != "" {
+			to = u.HomeCurrency
+		}
+		rate, err := getFXProvider().Rate(ctx, fromCurrency, to, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("convertSplits: %w", err)
+		}
+		out[i] = Split{User: s.User, SplitType: s.SplitType, Amount: round2(s.Amount * rate)}
+	}
+	return out, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Group scopes a set of members and their expenses so balances and
+// settlements can be computed within the group alone, independent of a
+// member's overall Net across every other group or direct expense.
+// WHY: a real splitwise-style app has many overlapping groups ("Goa trip",
+// "Flatmates"); global Net can't tell you what's owed inside just one of
+// them.
+type Group struct {
+	GroupID  string
+	Name     string
+	Members  []string
+	mu       sync.Mutex
+	expenses []*Expense
+	balances map[string]float64 // userID -> net within this group
+}
+
+var (
+	groupCounterMu sync.Mutex
+	groupCounter   int
+)
+
+// NewGroup mirrors NewUser/nextExpenseID's incrementing-ID convention.
+func NewGroup(name string, members []string) *Group {
+	groupCounterMu.Lock()
+	groupCounter++
+	id := fmt.Sprintf("g%d", groupCounter)
+	groupCounterMu.Unlock()
+
+	return &Group{
+		GroupID:  id,
+		Name:     name,
+		Members:  append([]string{}, members...),
+		balances: make(map[string]float64),
+	}
+}
+
+func (g *Group) hasMember(userID string) bool {
+	for _, m := range g.Members {
+		if m == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// recordExpense folds an already-verified expense's splits into the
+// group's local ledger. WHY: this runs in addition to, not instead of, the
+// global UserManager update AddExpense already performs - Net stays the
+// single source of truth for "what do I owe overall", while the group
+// ledger answers "what do we owe each other inside this group".
+func (g *Group) recordExpense(exp *Expense) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.expenses = append(g.expenses, exp)
+	for _, sp := range exp.Splits {
+		if sp.User == exp.PaidBy {
+			g.balances[sp.User] = round2(g.balances[sp.User] + exp.Amount - sp.Amount)
+		} else {
+			g.balances[sp.User] = round2(g.balances[sp.User] - sp.Amount)
+		}
+	}
+}
+
+// Expenses returns the expenses recorded against this group.
+func (g *Group) Expenses() []*Expense {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]*Expense, len(g.expenses))
+	copy(out, g.expenses)
+	return out
+}
+
+// Settle computes transfers that zero out every member's balance within
+// this group alone, greedily pairing the largest creditor with the
+// largest debtor. That pairing does not always find the fewest transfers:
+// creditors 5, 4, 1 against debtors 4, 3, 3 take 5 where 4 would do.
+// WHY: reuses the same greedy-heap approach as ExpenseManager.SimplifyDebts,
+// just scoped to g.balances instead of the global UserManager.
+func (g *Group) Settle() ([]Settlement, int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	creditors := &balanceHeap{}
+	debtors := &balanceHeap{}
+	heap.Init(creditors)
+	heap.Init(debtors)
+
+	for _, m := range g.Members {
+		net := round2(g.balances[m])
+		switch {
+		case net > 0.01:
+			heap.Push(creditors, balanceEntry{m, net})
+		case net < -0.01:
+			heap.Push(debtors, balanceEntry{m, -net})
+		}
+	}
+
+	var settlements []Settlement
+	for creditors.Len() > 0 && debtors.Len() > 0 {
+		c := heap.Pop(creditors).(balanceEntry)
+		d := heap.Pop(debtors).(balanceEntry)
+
+		amount := round2(minFloat(c.amount, d.amount))
+		if amount >= 0.01 {
+			settlements = append(settlements, Settlement{From: d.userID, To: c.userID, Amount: amount})
+		}
+		c.amount = round2(c.amount - amount)
+		d.amount = round2(d.amount - amount)
+		if c.amount >= 0.01 {
+			heap.Push(creditors, c)
+		}
+		if d.amount >= 0.01 {
+			heap.Push(debtors, d)
+		}
+	}
+	return settlements, len(settlements)
+}
+
+// GroupManager is the aggregate root for groups, mirroring the
+// UserManager/ExpenseManager singleton pattern used elsewhere in this file.
+type GroupManager struct {
+	mu     sync.Mutex
+	groups map[string]*Group
+}
+
+var (
+	groupManagerInstance *GroupManager
+	groupManagerOnce     sync.Once
+)
+
+func GetGroupManager() *GroupManager {
+	groupManagerOnce.Do(func() {
+		groupManagerInstance = &GroupManager{groups: make(map[string]*Group)}
+	})
+	return groupManagerInstance
+}
+
+func (gm *GroupManager) AddGroup(group *Group) *Group {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if _, exists := gm.groups[group.GroupID]; !exists {
+		gm.groups[group.GroupID] = group
+	}
+	return group
+}
+
+func (gm *GroupManager) GetGroup(groupID string) *Group {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	return gm.groups[groupID]
+}
+
+// AddGroupExpense records an expense the normal way (split, verify, FX
+// convert, update UserManager balances) and additionally folds it into the
+// group's own ledger for group-scoped settlement.
+// WHY: one entry point keeps "global Net" and "what this group owes" from
+// drifting apart - both are derived from the same verified split.
+func (em *ExpenseManager) AddGroupExpense(groupID, expenseName, userID string, splitType SplitType, subAmounts []float64, amount float64, currency string) *Expense {
+	group := GetGroupManager().GetGroup(groupID)
+	if group == nil {
+		fmt.Println("AddGroupExpense: unknown group", groupID)
+		return nil
+	}
+	if !group.hasMember(userID) {
+		fmt.Println("AddGroupExpense: payer is not a group member")
+		return nil
+	}
+
+	exp := em.AddExpense(expenseName, userID, splitType, group.Members, amount, subAmounts, currency, time.Now())
+	if exp == nil {
+		return nil
+	}
+	group.recordExpense(exp)
+	return exp
+}
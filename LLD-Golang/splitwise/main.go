@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"sync"
+	"time"
+
+	"splitwise/eventstore"
 )
 
 // ======================= Utilities =======================
@@ -44,11 +48,37 @@ type User struct {
 	Name         string
 	Email        string
 	Mobile       string
+	HomeCurrency string
 	TotalBorrows float64
 	TotalPays    float64
 	Net          float64
 }
 
+// Apply folds one committed event into the user's balances.
+// WHY: this is the ONLY place User state changes so that live updates and
+// Rehydrate replay produce byte-identical results.
+func (u *User) Apply(event eventstore.Event) error {
+	switch event.Type {
+	case eventstore.UserRegisteredV1:
+		var payload struct{ Name, Email, Mobile string }
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return fmt.Errorf("apply %s: %w", event.Type, err)
+		}
+		u.Name, u.Email, u.Mobile = payload.Name, payload.Email, payload.Mobile
+	case eventstore.BalanceAdjustedV1:
+		var payload struct{ Pays, Borrows float64 }
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return fmt.Errorf("apply %s: %w", event.Type, err)
+		}
+		u.TotalPays = round2(u.TotalPays + payload.Pays)
+		u.TotalBorrows = round2(u.TotalBorrows + payload.Borrows)
+		u.Net = round2(u.TotalPays - u.TotalBorrows)
+	default:
+		return fmt.Errorf("apply: unknown event type %q", event.Type)
+	}
+	return nil
+}
+
 var (
 	// WHY: Make user-ID generation threadsafe in case the app becomes concurrent.
 	counterMu sync.Mutex
@@ -57,7 +87,7 @@ var (
 
 // NewUser auto-assigns an incrementing numeric ID as a string.
 // WHY: Mirrors your C# style, keeps demo simple, avoids external ID deps.
-func NewUser(name, email, mobile string) *User {
+func NewUser(name, email, mobile, homeCurrency string) *User {
 	counterMu.Lock()
 	counter++
 	id := fmt.Sprintf("%d", counter)
@@ -68,6 +98,7 @@ func NewUser(name, email, mobile string) *User {
 		Name:         name,
 		Email:        email,
 		Mobile:       mobile,
+		HomeCurrency: homeCurrency,
 		TotalBorrows: 0,
 		TotalPays:    0,
 		Net:          0,
@@ -78,21 +109,68 @@ func NewUser(name, email, mobile string) *User {
 // WHY: Keeps both raw inputs (PaidBy, Amount, SplitType) and computed
 // artifacts (Splits, NetBalance) for observability/debugging.
 type Expense struct {
+	ExpenseID   string
 	PaidBy      string
 	Amount      float64
+	Currency    string // ISO 4217, e.g. "USD"; native currency of Amount/Split.Amount
+	AsOf        time.Time
 	Splits      []Split
 	SplitType   SplitType
 	NetBalance  float64
 	ExpenseName string
 }
 
+// Apply folds ExpenseCreatedV1 into a zero-value Expense during replay.
+// WHY: ExpenseCreatedV1 is the only event recorded against an expense;
+// balance changes are BalanceAdjustedV1 events on each user's own log, and
+// nothing emits SplitAppliedV1.
+func (e *Expense) Apply(event eventstore.Event) error {
+	switch event.Type {
+	case eventstore.ExpenseCreatedV1:
+		var payload struct {
+			ExpenseName, PaidBy string
+			Amount              float64
+			SplitType           SplitType
+			Splits              []Split
+		}
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return fmt.Errorf("apply %s: %w", event.Type, err)
+		}
+		e.ExpenseID = event.AggregateID
+		e.ExpenseName, e.PaidBy, e.Amount, e.SplitType, e.Splits = payload.ExpenseName, payload.PaidBy, payload.Amount, payload.SplitType, payload.Splits
+		e.calculateNetBalance()
+	default:
+		return fmt.Errorf("apply: unknown event type %q", event.Type)
+	}
+	return nil
+}
+
+var (
+	// WHY: mirrors the counterMu/counter pair used for user IDs above.
+	expenseCounterMu sync.Mutex
+	expenseCounter   int
+)
+
+func nextExpenseID() string {
+	expenseCounterMu.Lock()
+	defer expenseCounterMu.Unlock()
+	expenseCounter++
+	return fmt.Sprintf("e%d", expenseCounter)
+}
+
 // NewExpense constructs and computes the net balance visible to the payer.
 // WHY: calculate once at creation; immutable thereafter (by convention).
-func NewExpense(expenseName, paidBy string, amount float64, splits []Split, splitType SplitType) *Expense {
+func NewExpense(expenseName, paidBy string, amount float64, currency string, asOf time.Time, splits []Split, splitType SplitType) *Expense {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
 	e := &Expense{
+		ExpenseID:   nextExpenseID(),
 		ExpenseName: expenseName,
 		PaidBy:      paidBy,
 		Amount:      round2(amount), // WHY: normalize incoming amount immediately
+		Currency:    currency,
+		AsOf:        asOf,
 		Splits:      splits,
 		SplitType:   splitType,
 	}
@@ -123,10 +201,17 @@ func (e *Expense) calculateNetBalance() float64 {
 //
 
 type UserManager struct {
-	mu    sync.Mutex
-	users map[string]*User
+	mu     sync.Mutex
+	users  map[string]*User
+	seqs   map[string]int64 // WHY: tracks each user aggregate's latest committed seq
+	store  eventstore.EventStore
+	bus    *eventstore.EventBus
+	snaps  eventstore.SnapshotStore
+	worker *eventstore.SnapshotWorker
 }
 
+var userSnapshotSerializer = eventstore.JSONSerializer[User]()
+
 var (
 	userManagerInstance *UserManager
 	userManagerOnce     sync.Once
@@ -136,13 +221,78 @@ var (
 // WHY: Keeps global state controlled; easy to swap for DI in the future.
 func GetUserManager() *UserManager {
 	userManagerOnce.Do(func() {
+		snaps := eventstore.NewInMemorySnapshotStore()
+		// WHY: snapshot every 20 events, off a small async worker pool, so a
+		// burst of balance updates never stalls behind a snapshot write.
+		policy := eventstore.SnapshotPolicy{EveryNEvents: 20}
 		userManagerInstance = &UserManager{
-			users: make(map[string]*User),
+			users:  make(map[string]*User),
+			seqs:   make(map[string]int64),
+			store:  eventstore.NewInMemoryEventStore(),
+			bus:    eventstore.NewEventBus(),
+			snaps:  snaps,
+			worker: eventstore.NewSnapshotWorker(snaps, policy, 2, 64),
 		}
 	})
 	return userManagerInstance
 }
 
+// appendEvent saves evtType/payload for aggregateID at the next seq and
+// applies it to agg, keeping seqs in sync with the store.
+// WHY: single choke point so every mutation is event-then-apply, never
+// apply-then-event.
+func (um *UserManager) appendEvent(aggregateID, evtType string, payload any, agg eventstore.Aggregate) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("appendEvent: marshal %s: %w", evtType, err)
+	}
+	expected := um.seqs[aggregateID]
+	evt := eventstore.Event{Type: evtType, Data: data}
+	if err := um.store.SaveEvents(context.Background(), aggregateID, expected, []eventstore.Event{evt}); err != nil {
+		return fmt.Errorf("appendEvent: %w", err)
+	}
+	um.seqs[aggregateID] = expected + 1
+	evt.AggregateID, evt.Seq = aggregateID, expected+1
+	if err := agg.Apply(evt); err != nil {
+		return fmt.Errorf("appendEvent: apply %s: %w", evtType, err)
+	}
+	um.bus.Publish(evt)
+	if u, ok := agg.(*User); ok {
+		if state, err := json.Marshal(u); err == nil {
+			um.worker.Notify(aggregateID, evt.Seq, state)
+		}
+	}
+	return nil
+}
+
+// Rehydrate rebuilds a user's balances from the latest snapshot (if any)
+// plus whatever events were committed after it.
+// WHY: lets a crashed process come back up with identical User.Net values
+// without replaying the entire history every time.
+func (um *UserManager) Rehydrate(ctx context.Context, aggregateID string) (*User, error) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	u, err := eventstore.RehydrateWithSnapshot(ctx, um.store, um.snaps, aggregateID, userSnapshotSerializer, User{UserID: aggregateID},
+		func(state User, e eventstore.Event) (User, error) {
+			if err := (&state).Apply(e); err != nil {
+				return state, err
+			}
+			return state, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := um.store.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	um.seqs[aggregateID] = int64(len(events))
+	um.users[aggregateID] = &u
+	return &u, nil
+}
+
 func (um *UserManager) GetUser(userID string) *User {
 	um.mu.Lock()
 	defer um.mu.Unlock()
@@ -152,24 +302,29 @@ func (um *UserManager) GetUser(userID string) *User {
 func (um *UserManager) AddBorrow(userID string, amount float64) bool {
 	um.mu.Lock()
 	defer um.mu.Unlock()
-	if user, ok := um.users[userID]; ok {
-		// WHY: Round each step to keep ledger stable across ops.
-		user.TotalBorrows = round2(user.TotalBorrows + amount)
-		user.Net = round2(user.TotalPays - user.TotalBorrows)
-		return true
+	user, ok := um.users[userID]
+	if !ok {
+		return false
+	}
+	if err := um.appendEvent(userID, eventstore.BalanceAdjustedV1, struct{ Pays, Borrows float64 }{Borrows: amount}, user); err != nil {
+		fmt.Println("AddBorrow:", err)
+		return false
 	}
-	return false
+	return true
 }
 
 func (um *UserManager) AddPay(userID string, amount float64) bool {
 	um.mu.Lock()
 	defer um.mu.Unlock()
-	if user, ok := um.users[userID]; ok {
-		user.TotalPays = round2(user.TotalPays + amount)
-		user.Net = round2(user.TotalPays - user.TotalBorrows)
-		return true
+	user, ok := um.users[userID]
+	if !ok {
+		return false
+	}
+	if err := um.appendEvent(userID, eventstore.BalanceAdjustedV1, struct{ Pays, Borrows float64 }{Pays: amount}, user); err != nil {
+		fmt.Println("AddPay:", err)
+		return false
 	}
-	return false
+	return true
 }
 
 func (um *UserManager) GetNet(userID string) float64 {
@@ -184,8 +339,13 @@ func (um *UserManager) GetNet(userID string) float64 {
 func (um *UserManager) AddUser(user *User) *User {
 	um.mu.Lock()
 	defer um.mu.Unlock()
-	if _, exists := um.users[user.UserID]; !exists {
-		um.users[user.UserID] = user
+	if _, exists := um.users[user.UserID]; exists {
+		return user
+	}
+	um.users[user.UserID] = user
+	payload := struct{ Name, Email, Mobile string }{user.Name, user.Email, user.Mobile}
+	if err := um.appendEvent(user.UserID, eventstore.UserRegisteredV1, payload, user); err != nil {
+		fmt.Println("AddUser:", err)
 	}
 	return user
 }
@@ -205,13 +365,14 @@ func (um *UserManager) UpdateUserBorrows(splits []Split, payer string, amount fl
 			// WHY: In production, you'd return error; here we skip gracefully.
 			continue
 		}
+		payload := struct{ Pays, Borrows float64 }{Borrows: sp.Amount}
 		if sp.User == payer {
-			u.TotalPays = round2(u.TotalPays + amount)
-			u.TotalBorrows = round2(u.TotalBorrows + sp.Amount)
-		} else {
-			u.TotalBorrows = round2(u.TotalBorrows + sp.Amount)
+			payload.Pays = amount
+		}
+		if err := um.appendEvent(sp.User, eventstore.BalanceAdjustedV1, payload, u); err != nil {
+			fmt.Println("UpdateUserBorrows:", err)
+			return false
 		}
-		u.Net = round2(u.TotalPays - u.TotalBorrows)
 	}
 	return true
 }
@@ -228,8 +389,11 @@ type SplitStrategy interface {
 	Split(userID string, users []string, amount float64, subAmounts []float64) []Split
 }
 
-// VerifySplit ensures numerical integrity and only then updates user balances.
-// WHY: Prevents recording invalid expenses and keeps the ledger consistent.
+// VerifySplit checks that splits sum to amount in the expense's native
+// currency. It does NOT touch user balances.
+// WHY: verification must happen before FX conversion - converting each
+// split first and re-summing would let per-split rounding drift cause a
+// numerically valid split to spuriously fail.
 func VerifySplit(split []Split, amount float64, userID string) bool {
 	sum := 0.0
 	for _, s := range split {
@@ -240,7 +404,6 @@ func VerifySplit(split []Split, amount float64, userID string) bool {
 		fmt.Println("Verify split failed.")
 		return false
 	}
-	GetUserManager().UpdateUserBorrows(split, userID, amount)
 	return true
 }
 
@@ -337,6 +500,8 @@ func CreateSplitStrategy(splitType SplitType) SplitStrategy {
 type ExpenseManager struct {
 	mu       sync.RWMutex
 	expenses map[string][]*Expense
+	byID     map[string]*Expense
+	store    eventstore.EventStore
 }
 
 var (
@@ -348,14 +513,29 @@ func GetExpenseManager() *ExpenseManager {
 	expenseManagerOnce.Do(func() {
 		expenseManagerInstance = &ExpenseManager{
 			expenses: make(map[string][]*Expense),
+			byID:     make(map[string]*Expense),
+			store:    eventstore.NewInMemoryEventStore(),
 		}
 	})
 	return expenseManagerInstance
 }
 
-// AddExpense orchestrates: pick strategy -> split -> verify -> record expense.
+// Rehydrate rebuilds an expense from its event log.
+// WHY: gives ExpenseManager the same crash-recovery story as UserManager.
+func (em *ExpenseManager) Rehydrate(ctx context.Context, expenseID string) (*Expense, error) {
+	e := &Expense{}
+	if err := eventstore.Rehydrate(ctx, em.store, expenseID, e); err != nil {
+		return nil, err
+	}
+	em.mu.Lock()
+	em.byID[expenseID] = e
+	em.mu.Unlock()
+	return e, nil
+}
+
+// AddExpense orchestrates: pick strategy -> split -> verify -> convert -> record.
 // WHY: If verification fails, we DO NOT record the expense (nil returned).
-func (em *ExpenseManager) AddExpense(expenseName, userID string, splitType SplitType, users []string, amount float64, subAmounts []float64) *Expense {
+func (em *ExpenseManager) AddExpense(expenseName, userID string, splitType SplitType, users []string, amount float64, subAmounts []float64, currency string, asOf time.Time) *Expense {
 	strategy := CreateSplitStrategy(splitType)
 	splits := strategy.Split(userID, users, round2(amount), subAmounts)
 	if splits == nil {
@@ -363,10 +543,44 @@ func (em *ExpenseManager) AddExpense(expenseName, userID string, splitType Split
 		return nil
 	}
 
-	exp := NewExpense(expenseName, userID, amount, splits, splitType)
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	um := GetUserManager()
+	homeSplits, err := convertSplits(context.Background(), splits, currency, asOf, um)
+	if err != nil {
+		fmt.Println("AddExpense: fx conversion:", err)
+		return nil
+	}
+	homeAmount := round2(amount)
+	if payer := um.GetUser(userID); payer != nil && payer.HomeCurrency != "" {
+		if rate, err := getFXProvider().Rate(context.Background(), currency, payer.HomeCurrency, asOf); err == nil {
+			homeAmount = round2(amount * rate)
+		}
+	}
+	um.UpdateUserBorrows(homeSplits, userID, homeAmount)
+
+	exp := NewExpense(expenseName, userID, amount, currency, asOf, splits, splitType)
+
+	payload := struct {
+		ExpenseName, PaidBy string
+		Amount              float64
+		SplitType           SplitType
+		Splits              []Split
+	}{expenseName, userID, exp.Amount, splitType, splits}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Println("AddExpense: marshal event:", err)
+		return nil
+	}
+	if err := em.store.SaveEvents(context.Background(), exp.ExpenseID, 0, []eventstore.Event{{Type: eventstore.ExpenseCreatedV1, Data: data}}); err != nil {
+		fmt.Println("AddExpense: save event:", err)
+		return nil
+	}
 
 	em.mu.Lock()
 	em.expenses[userID] = append(em.expenses[userID], exp)
+	em.byID[exp.ExpenseID] = exp
 	em.mu.Unlock()
 	return exp
 }
@@ -400,20 +614,25 @@ func main() {
 	userManager := GetUserManager()
 
 	// Create users (IDs auto-assigned: "1", "2", "3")
-	u1 := NewUser("animesh", "vamsi@gmail.com", "9999999999")
-	u2 := NewUser("roy", "krishna@gmail.com", "8888888888")
-	u3 := NewUser("somu", "jani@gmail.com", "7777777777")
+	u1 := NewUser("animesh", "vamsi@gmail.com", "9999999999", "USD")
+	u2 := NewUser("roy", "krishna@gmail.com", "8888888888", "INR")
+	u3 := NewUser("somu", "jani@gmail.com", "7777777777", "USD")
 	userManager.AddUser(u1)
 	userManager.AddUser(u2)
 	userManager.AddUser(u3)
 
 	users := []string{u1.UserID, u2.UserID, u3.UserID}
 
-	// Expense 1: Equal split of 100 paid by u1
-	_ = expenseManager.AddExpense("Red Biryani", u1.UserID, EQUAL, users, 100, nil)
+	fx := NewInMemoryFXProvider()
+	fx.SetRate("USD", "INR", 83.0)
+	fx.SetRate("INR", "USD", 1/83.0)
+	SetFXProvider(fx)
+
+	// Expense 1: Equal split of 100 USD paid by u1
+	_ = expenseManager.AddExpense("Red Biryani", u1.UserID, EQUAL, users, 100, nil, "USD", time.Now())
 
-	// Expense 2: Percentage split 30/40/30 of 200 paid by u2
-	_ = expenseManager.AddExpense("Groceries", u2.UserID, PERCENTAGE, users, 200, []float64{0.3, 0.4, 0.3})
+	// Expense 2: Percentage split 30/40/30 of 200 USD paid by u2
+	_ = expenseManager.AddExpense("Groceries", u2.UserID, PERCENTAGE, users, 200, []float64{0.3, 0.4, 0.3}, "USD", time.Now())
 
 	// Show all expenses where user "1" is involved
 	result := expenseManager.ShowExpenses("1")
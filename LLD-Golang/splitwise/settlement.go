@@ -0,0 +1,91 @@
+package main
+
+import "container/heap"
+
+// Settlement is one transfer produced by debt simplification: From pays To
+// the given Amount and both are fully settled for that amount.
+type Settlement struct {
+	From   string
+	To     string
+	Amount float64
+}
+
+// balanceHeap is a max-heap of (userID, amount) pairs, ordered by amount.
+// WHY: SimplifyDebts needs "largest creditor" and "largest debtor" on every
+// iteration; a heap gives us that in O(log n) instead of a linear scan.
+type balanceHeap []balanceEntry
+
+type balanceEntry struct {
+	userID string
+	amount float64
+}
+
+func (h balanceHeap) Len() int            { return len(h) }
+func (h balanceHeap) Less(i, j int) bool  { return h[i].amount > h[j].amount }
+func (h balanceHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *balanceHeap) Push(x interface{}) { *h = append(*h, x.(balanceEntry)) }
+func (h *balanceHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// SimplifyDebts computes transfers that zero out every user's Net,
+// restricted to userIDs, greedily pairing the largest creditor with the
+// largest debtor. That pairing does not always find the fewest transfers
+// (the same counterexample as Group.Settle applies).
+// WHY: naive settlement is N-1 transfers per group; greedily matching the
+// biggest creditor against the biggest debtor collapses cycles (e.g.
+// A->B->C->A) down to zero transfers instead of three.
+func (em *ExpenseManager) SimplifyDebts(userIDs []string) ([]Settlement, int) {
+	um := GetUserManager()
+
+	creditors := &balanceHeap{}
+	debtors := &balanceHeap{}
+	heap.Init(creditors)
+	heap.Init(debtors)
+
+	for _, id := range userIDs {
+		net := round2(um.GetNet(id))
+		switch {
+		case net > 0.01:
+			heap.Push(creditors, balanceEntry{id, net})
+		case net < -0.01:
+			heap.Push(debtors, balanceEntry{id, -net})
+		}
+	}
+
+	var settlements []Settlement
+	for creditors.Len() > 0 && debtors.Len() > 0 {
+		c := heap.Pop(creditors).(balanceEntry)
+		d := heap.Pop(debtors).(balanceEntry)
+
+		amount := round2(minFloat(c.amount, d.amount))
+		if amount >= 0.01 {
+			settlements = append(settlements, Settlement{From: d.userID, To: c.userID, Amount: amount})
+		}
+
+		c.amount = round2(c.amount - amount)
+		d.amount = round2(d.amount - amount)
+
+		// WHY: push whichever side still has a nonzero balance back on;
+		// residual cents below 0.01 are treated as fully settled.
+		if c.amount >= 0.01 {
+			heap.Push(creditors, c)
+		}
+		if d.amount >= 0.01 {
+			heap.Push(debtors, d)
+		}
+	}
+
+	return settlements, len(settlements)
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,316 @@
+package main
+
+import "fmt"
+
+// DiscountRule is one composable piece of a cart's discount logic
+// (Strategy Pattern, replacing the single PercentageDiscount). Applies
+// decides whether the rule is even in play for the current cart;
+// Apply is handed the running total left after any earlier rules in
+// the same subset and returns how much it saves off that total.
+type DiscountRule interface {
+	Name() string
+	Stackable() bool
+	Applies(cart *Cart) bool
+	Apply(cart *Cart, runningTotal float64) float64
+}
+
+// SavingLine is one entry of a Cart's SavingsBreakdown, e.g. so a
+// notification can announce "Coupon SAVE10 applied: -$4.20".
+type SavingLine struct {
+	RuleName string
+	Amount   float64
+}
+
+// FlatAmountOff knocks a fixed amount off the running total.
+type FlatAmountOff struct {
+	Amount    float64
+	stackable bool
+}
+
+func NewFlatAmountOff(amount float64, stackable bool) *FlatAmountOff {
+	return &FlatAmountOff{Amount: amount, stackable: stackable}
+}
+
+func (f *FlatAmountOff) Name() string      { return fmt.Sprintf("FLAT_%.2f_OFF", f.Amount) }
+func (f *FlatAmountOff) Stackable() bool   { return f.stackable }
+func (f *FlatAmountOff) Applies(cart *Cart) bool { return len(cart.Items) > 0 }
+func (f *FlatAmountOff) Apply(cart *Cart, runningTotal float64) float64 {
+	return f.Amount
+}
+
+// PercentageOff knocks a percentage off the running total.
+type PercentageOff struct {
+	Percent   float64
+	stackable bool
+}
+
+func NewPercentageOff(percent float64, stackable bool) *PercentageOff {
+	return &PercentageOff{Percent: percent, stackable: stackable}
+}
+
+func (p *PercentageOff) Name() string      { return fmt.Sprintf("%.0f%%_OFF", p.Percent) }
+func (p *PercentageOff) Stackable() bool   { return p.stackable }
+func (p *PercentageOff) Applies(cart *Cart) bool { return len(cart.Items) > 0 }
+func (p *PercentageOff) Apply(cart *Cart, runningTotal float64) float64 {
+	return runningTotal * (p.Percent / 100)
+}
+
+// BuyXGetYFree gives Y free units of productID for every bundle of X
+// paid units, e.g. BuyXGetYFree("p1", 2, 1) is "buy 2 get 1 free".
+type BuyXGetYFree struct {
+	ProductID string
+	X, Y      int
+	stackable bool
+}
+
+func NewBuyXGetYFree(productID string, x, y int, stackable bool) *BuyXGetYFree {
+	return &BuyXGetYFree{ProductID: productID, X: x, Y: y, stackable: stackable}
+}
+
+func (b *BuyXGetYFree) Name() string {
+	return fmt.Sprintf("BUY%d_GET%d_FREE_%s", b.X, b.Y, b.ProductID)
+}
+func (b *BuyXGetYFree) Stackable() bool { return b.stackable }
+
+func (b *BuyXGetYFree) Applies(cart *Cart) bool {
+	return quantityOf(cart, b.ProductID) >= b.X+b.Y
+}
+
+func (b *BuyXGetYFree) Apply(cart *Cart, runningTotal float64) float64 {
+	bundles := quantityOf(cart, b.ProductID) / (b.X + b.Y)
+	freeUnits := bundles * b.Y
+	return priceOf(cart, b.ProductID) * float64(freeUnits)
+}
+
+// TieredThreshold applies percent off once the cart subtotal reaches min.
+type TieredThreshold struct {
+	Min       float64
+	Percent   float64
+	stackable bool
+}
+
+func NewTieredThreshold(min, percent float64, stackable bool) *TieredThreshold {
+	return &TieredThreshold{Min: min, Percent: percent, stackable: stackable}
+}
+
+func (t *TieredThreshold) Name() string    { return fmt.Sprintf("TIER_%.2f_%.0f%%", t.Min, t.Percent) }
+func (t *TieredThreshold) Stackable() bool { return t.stackable }
+func (t *TieredThreshold) Applies(cart *Cart) bool {
+	return cart.Subtotal() >= t.Min
+}
+func (t *TieredThreshold) Apply(cart *Cart, runningTotal float64) float64 {
+	return runningTotal * (t.Percent / 100)
+}
+
+// CategoryPercent applies percent off only the items in categoryID.
+type CategoryPercent struct {
+	CategoryID string
+	Percent    float64
+	stackable  bool
+}
+
+func NewCategoryPercent(categoryID string, percent float64, stackable bool) *CategoryPercent {
+	return &CategoryPercent{CategoryID: categoryID, Percent: percent, stackable: stackable}
+}
+
+func (c *CategoryPercent) Name() string    { return fmt.Sprintf("%s_%.0f%%_OFF", c.CategoryID, c.Percent) }
+func (c *CategoryPercent) Stackable() bool { return c.stackable }
+func (c *CategoryPercent) Applies(cart *Cart) bool {
+	return categorySubtotal(cart, c.CategoryID) > 0
+}
+func (c *CategoryPercent) Apply(cart *Cart, runningTotal float64) float64 {
+	return categorySubtotal(cart, c.CategoryID) * (c.Percent / 100)
+}
+
+// CouponCode gates another DiscountRule behind a code the customer must
+// have actually applied to the cart (Decorator Pattern).
+type CouponCode struct {
+	Code    string
+	Wrapped DiscountRule
+}
+
+func NewCouponCode(code string, wrapped DiscountRule) *CouponCode {
+	return &CouponCode{Code: code, Wrapped: wrapped}
+}
+
+func (c *CouponCode) Name() string    { return c.Code }
+func (c *CouponCode) Stackable() bool { return c.Wrapped.Stackable() }
+func (c *CouponCode) Applies(cart *Cart) bool {
+	return containsCoupon(cart.AppliedCoupons, c.Code) && c.Wrapped.Applies(cart)
+}
+func (c *CouponCode) Apply(cart *Cart, runningTotal float64) float64 {
+	return c.Wrapped.Apply(cart, runningTotal)
+}
+
+// ApplicationMode decides how CompositeDiscount combines its rules.
+type ApplicationMode int
+
+const (
+	// Sequential applies applicable rules in declared order: a
+	// non-stackable first rule is applied alone; otherwise every
+	// stackable rule is applied and later non-stackable ones are skipped.
+	Sequential ApplicationMode = iota
+	// BestOf tries every combinable subset of applicable rules and
+	// keeps whichever saves the most, capped at the subtotal.
+	BestOf
+)
+
+// CompositeDiscount owns an ordered set of DiscountRules and the mode
+// used to combine them, replacing the cart's single DiscountStrategy.
+type CompositeDiscount struct {
+	Rules []DiscountRule
+	Mode  ApplicationMode
+}
+
+func NewCompositeDiscount(mode ApplicationMode, rules ...DiscountRule) *CompositeDiscount {
+	return &CompositeDiscount{Rules: rules, Mode: mode}
+}
+
+// Apply evaluates the composite against cart/subtotal and returns the
+// final total plus a per-rule SavingsBreakdown, in application order.
+func (cd *CompositeDiscount) Apply(cart *Cart, subtotal float64) (float64, []SavingLine) {
+	applicable := make([]DiscountRule, 0, len(cd.Rules))
+	for _, r := range cd.Rules {
+		if r.Applies(cart) {
+			applicable = append(applicable, r)
+		}
+	}
+
+	if cd.Mode == BestOf {
+		return bestSubset(cart, subtotal, combinableSubsets(applicable))
+	}
+	return applySubset(cart, subtotal, defaultSequentialSubset(applicable))
+}
+
+// defaultSequentialSubset walks applicable rules in declared order. If
+// the first rule is non-stackable it claims the stack exclusively and
+// nothing after it is applied; otherwise stackable rules are stacked
+// together and any non-stackable rule arriving later is skipped rather
+// than breaking what already stacked.
+func defaultSequentialSubset(applicable []DiscountRule) []DiscountRule {
+	var subset []DiscountRule
+	lockedNonStackable := false
+	for _, r := range applicable {
+		if lockedNonStackable {
+			break
+		}
+		if len(subset) == 0 {
+			subset = append(subset, r)
+			if !r.Stackable() {
+				lockedNonStackable = true
+			}
+			continue
+		}
+		if r.Stackable() {
+			subset = append(subset, r)
+		}
+	}
+	return subset
+}
+
+// combinableSubsets enumerates every subset of rules that respects each
+// rule's Stackable flag: a subset containing a non-stackable rule may
+// only ever contain that one rule. Intended for demo-sized rule lists
+// (this is O(2^n)).
+func combinableSubsets(rules []DiscountRule) [][]DiscountRule {
+	n := len(rules)
+	var subsets [][]DiscountRule
+	for mask := 1; mask < (1 << n); mask++ {
+		var subset []DiscountRule
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, rules[i])
+			}
+		}
+		if isCombinable(subset) {
+			subsets = append(subsets, subset)
+		}
+	}
+	return subsets
+}
+
+func isCombinable(subset []DiscountRule) bool {
+	if len(subset) <= 1 {
+		return true
+	}
+	for _, r := range subset {
+		if !r.Stackable() {
+			return false
+		}
+	}
+	return true
+}
+
+// bestSubset tries every candidate subset and keeps whichever leaves
+// the lowest final total; defaultSequentialSubset's exact choice is
+// always one of the candidates, so BestOf never does worse than
+// Sequential.
+func bestSubset(cart *Cart, subtotal float64, subsets [][]DiscountRule) (float64, []SavingLine) {
+	bestTotal := subtotal
+	var bestBreakdown []SavingLine
+	for _, subset := range subsets {
+		total, breakdown := applySubset(cart, subtotal, subset)
+		if total < bestTotal {
+			bestTotal = total
+			bestBreakdown = breakdown
+		}
+	}
+	return bestTotal, bestBreakdown
+}
+
+// applySubset applies an ordered subset of rules sequentially, each
+// rule's saving clamped to what's left of the running total, and
+// returns the final total plus the per-rule breakdown.
+func applySubset(cart *Cart, subtotal float64, subset []DiscountRule) (float64, []SavingLine) {
+	runningTotal := subtotal
+	var breakdown []SavingLine
+	for _, r := range subset {
+		saving := r.Apply(cart, runningTotal)
+		if saving < 0 {
+			saving = 0
+		}
+		if saving > runningTotal {
+			saving = runningTotal
+		}
+		runningTotal -= saving
+		breakdown = append(breakdown, SavingLine{RuleName: r.Name(), Amount: saving})
+	}
+	return runningTotal, breakdown
+}
+
+func containsCoupon(coupons []string, code string) bool {
+	for _, c := range coupons {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func quantityOf(cart *Cart, productID string) int {
+	for _, item := range cart.Items {
+		if item.Product.ID == productID {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+func priceOf(cart *Cart, productID string) float64 {
+	for _, item := range cart.Items {
+		if item.Product.ID == productID {
+			return item.Product.Price
+		}
+	}
+	return 0
+}
+
+func categorySubtotal(cart *Cart, categoryID string) float64 {
+	subtotal := 0.0
+	for _, item := range cart.Items {
+		if item.Product.CategoryID == categoryID {
+			subtotal += item.Product.Price * float64(item.Quantity)
+		}
+	}
+	return subtotal
+}
@@ -11,6 +11,7 @@ type Product struct {
 	Name              string
 	Price             float64
 	InventoryQuantity int
+	CategoryID        string
 }
 
 // CartItem represents an item in the cart with quantity.
@@ -56,20 +57,6 @@ func (m *MockInventoryService) UpdateInventory(productID string, quantity int) e
 	return nil
 }
 
-// DiscountStrategy interface for applying discounts (Strategy Pattern).
-type DiscountStrategy interface {
-	ApplyDiscount(total float64) float64
-}
-
-// PercentageDiscount is an example strategy (e.g., 10% off).
-type PercentageDiscount struct {
-	Percentage float64
-}
-
-func (p *PercentageDiscount) ApplyDiscount(total float64) float64 {
-	return total * (1 - p.Percentage/100)
-}
-
 // PaymentProcessor interface for handling payments (Strategy Pattern).
 type PaymentProcessor interface {
 	ProcessPayment(amount float64) error
@@ -100,23 +87,33 @@ func (m *MockNotificationService) SendNotification(message string) error {
 type Cart struct {
 	Items               []CartItem
 	Total               float64
+	AppliedCoupons      []string
+	SavingsBreakdown    []SavingLine
 	inventoryService    InventoryService
-	discountStrategy    DiscountStrategy
+	discount            *CompositeDiscount
 	paymentProcessor    PaymentProcessor
 	notificationService NotificationService
 }
 
 // NewCart constructor injects dependencies (Dependency Injection).
-func NewCart(inventory InventoryService, discount DiscountStrategy, payment PaymentProcessor, notification NotificationService) *Cart {
+func NewCart(inventory InventoryService, discount *CompositeDiscount, payment PaymentProcessor, notification NotificationService) *Cart {
 	return &Cart{
 		Items:               make([]CartItem, 0),
 		inventoryService:    inventory,
-		discountStrategy:    discount,
+		discount:            discount,
 		paymentProcessor:    payment,
 		notificationService: notification,
 	}
 }
 
+// ApplyCoupon records a coupon code as applied to the cart, so any
+// CouponCode rule gated on that code becomes eligible, then recomputes
+// the total.
+func (c *Cart) ApplyCoupon(code string) {
+	c.AppliedCoupons = append(c.AppliedCoupons, code)
+	c.CalculateTotal()
+}
+
 // AddItem adds a product to the cart, validates inventory, updates total, notifies.
 func (c *Cart) AddItem(product *Product, quantity int) error {
 	if quantity <= 0 {
@@ -189,13 +186,26 @@ func (c *Cart) DeleteItem(productID string) error {
 	return errors.New("product not found in cart")
 }
 
-// CalculateTotal computes the total with discount applied.
-func (c *Cart) CalculateTotal() float64 {
+// Subtotal sums item price*quantity before any discount is applied.
+func (c *Cart) Subtotal() float64 {
 	subtotal := 0.0
 	for _, item := range c.Items {
 		subtotal += item.Product.Price * float64(item.Quantity)
 	}
-	c.Total = c.discountStrategy.ApplyDiscount(subtotal)
+	return subtotal
+}
+
+// CalculateTotal computes the total with the cart's CompositeDiscount
+// applied, recording a per-rule SavingsBreakdown and announcing each
+// applied rule's saving.
+func (c *Cart) CalculateTotal() float64 {
+	subtotal := c.Subtotal()
+	total, breakdown := c.discount.Apply(c, subtotal)
+	c.Total = total
+	c.SavingsBreakdown = breakdown
+	for _, line := range breakdown {
+		c.notify(fmt.Sprintf("%s applied: -$%.2f", line.RuleName, line.Amount))
+	}
 	c.notify(fmt.Sprintf("Cart total updated to %.2f", c.Total))
 	return c.Total
 }
@@ -224,14 +234,17 @@ func main() {
 	inventory.inventory["p1"] = 10 // Add product inventory
 	inventory.inventory["p2"] = 5
 
-	discount := &PercentageDiscount{Percentage: 10}
+	discount := NewCompositeDiscount(BestOf,
+		NewTieredThreshold(20, 10, true),
+		NewCouponCode("SAVE10", NewFlatAmountOff(10, false)),
+	)
 	payment := &MockPaymentProcessor{}
 	notification := &MockNotificationService{}
 
 	cart := NewCart(inventory, discount, payment, notification)
 
-	product1 := &Product{ID: "p1", Name: "Pizza", Price: 10.0, InventoryQuantity: 10}
-	product2 := &Product{ID: "p2", Name: "Burger", Price: 5.0, InventoryQuantity: 5}
+	product1 := &Product{ID: "p1", Name: "Pizza", Price: 10.0, InventoryQuantity: 10, CategoryID: "food"}
+	product2 := &Product{ID: "p2", Name: "Burger", Price: 5.0, InventoryQuantity: 5, CategoryID: "food"}
 
 	// Add items
 	cart.AddItem(product1, 2) // Expect notifications and total update
@@ -243,7 +256,8 @@ func main() {
 	// Delete
 	cart.DeleteItem("p1")
 
-	// Try add again and pay
+	// Try add again, apply a coupon, and pay
 	cart.AddItem(product1, 3)
+	cart.ApplyCoupon("SAVE10")
 	cart.ProcessPayment()
 }
@@ -19,9 +19,10 @@ func main() {
 		ExecutedAt: time.Now().Add(2 * time.Second),
 		Interval:   0,
 		Priority:   1,
-		Job: func() {
+		Job: func() (any, error) {
 			fmt.Println("Task 1: High priority, one-time")
 			time.Sleep(1 * time.Second) // Simulate work
+			return "task1-done", nil
 		},
 		MaxRetries: 2,
 		Status:     "pending",
@@ -32,9 +33,10 @@ func main() {
 		ExecutedAt: time.Now().Add(1 * time.Second),
 		Interval:   3 * time.Second,
 		Priority:   0,
-		Job: func() {
+		Job: func() (any, error) {
 			fmt.Println("Task 2: Highest priority, recurring")
 			time.Sleep(1 * time.Second) // Simulate work
+			return "task2-done", nil
 		},
 		MaxRetries: 1,
 		Status:     "pending",
@@ -45,9 +47,23 @@ func main() {
 		ExecutedAt: time.Now().Add(3 * time.Second),
 		Interval:   0,
 		Priority:   2,
-		Job: func() {
+		Job: func() (any, error) {
 			fmt.Println("Task 3: Low priority, one-time")
 			time.Sleep(1 * time.Second) // Simulate work
+			return "task3-done", nil
+		},
+		MaxRetries: 0,
+		Status:     "pending",
+	}
+
+	task4 := &taskqueue.Task{
+		ID:         "4",
+		ExecutedAt: time.Now().Add(2 * time.Second),
+		CronSpec:   "*/1 * * * *", // fires once a minute once resumed
+		Priority:   1,
+		Job: func() (any, error) {
+			fmt.Println("Task 4: cron-scheduled")
+			return "task4-done", nil
 		},
 		MaxRetries: 0,
 		Status:     "pending",
@@ -57,6 +73,16 @@ func main() {
 	scheduler.AddTask(task1)
 	scheduler.AddTask(task2)
 	scheduler.AddTask(task3)
+	scheduler.AddTask(task4)
+
+	// Pause task 4 immediately, then resume it - it stays off the queue
+	// in between, so it can't fire while paused.
+	if scheduler.PauseTask("4") {
+		fmt.Println("Task 4 paused")
+	}
+	if scheduler.ResumeTask("4") {
+		fmt.Println("Task 4 resumed")
+	}
 
 	// Start the scheduler
 	scheduler.Start()
@@ -66,8 +92,19 @@ func main() {
 	fmt.Printf("Task 1 status: %s\n", scheduler.GetTaskStatus("1"))
 	fmt.Printf("Task 2 status: %s\n", scheduler.GetTaskStatus("2"))
 	fmt.Printf("Task 3 status: %s\n", scheduler.GetTaskStatus("3"))
+	fmt.Printf("Task 4 status: %s\n", scheduler.GetTaskStatus("4"))
+
+	if result, ok := scheduler.GetResult("1"); ok {
+		fmt.Printf("Task 1 result: output=%v err=%v attempts=%d\n", result.Output, result.Err, result.Attempts)
+	}
+
+	// Cancel the recurring task before its next interval fires.
+	if scheduler.CancelTask("2") {
+		fmt.Println("Task 2 cancelled")
+	}
 
 	time.Sleep(5 * time.Second)
+	fmt.Printf("Dead-lettered tasks: %d\n", len(scheduler.DeadLetters()))
 	scheduler.Stop()
 	fmt.Println("Scheduler stopped manually")
 }
@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"task-schedular/taskqueue"
+)
+
+// JobStore persists tasks and their in-flight status so a restarted
+// scheduler can recover what it was doing. WHY an interface instead of
+// baking persistence into TaskSchedular: the default is in-memory, but a
+// caller that needs tasks to survive a process restart can swap in
+// JSONFileJobStore (or its own) without touching TaskSchedular itself -
+// the same reasoning taskqueue.TaskStore already uses for the queue.
+//
+// Task.Job is a func and can't round-trip through JSON (see its `json:"-"`
+// tag); a task Load()ed back from JSONFileJobStore after a restart needs
+// its Job re-attached by the caller, keyed by ID, before it can be
+// resubmitted - this store only recovers the scheduling metadata.
+type JobStore interface {
+	Save(task *taskqueue.Task) error
+	Load() ([]*taskqueue.Task, error)
+	UpdateStatus(id, status string) error
+	RecordAttempt(id string, attemptErr error) error
+}
+
+// InMemoryJobStore is the default JobStore: a process-local map, useful
+// mainly for Recover() after a TaskSchedular.Stop() within the same
+// process rather than across an actual restart.
+type InMemoryJobStore struct {
+	mu    sync.Mutex
+	tasks map[string]*taskqueue.Task
+}
+
+func NewInMemoryJobStore() *InMemoryJobStore {
+	return &InMemoryJobStore{tasks: make(map[string]*taskqueue.Task)}
+}
+
+func (st *InMemoryJobStore) Save(task *taskqueue.Task) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	clone := *task
+	st.tasks[task.ID] = &clone
+	return nil
+}
+
+func (st *InMemoryJobStore) Load() ([]*taskqueue.Task, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	out := make([]*taskqueue.Task, 0, len(st.tasks))
+	for _, t := range st.tasks {
+		clone := *t
+		out = append(out, &clone)
+	}
+	return out, nil
+}
+
+func (st *InMemoryJobStore) UpdateStatus(id, status string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t, ok := st.tasks[id]
+	if !ok {
+		return fmt.Errorf("jobstore: unknown task %s", id)
+	}
+	t.Status = status
+	return nil
+}
+
+func (st *InMemoryJobStore) RecordAttempt(id string, attemptErr error) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t, ok := st.tasks[id]
+	if !ok {
+		return fmt.Errorf("jobstore: unknown task %s", id)
+	}
+	t.Attempts++
+	if attemptErr != nil {
+		t.LastError = attemptErr.Error()
+	}
+	return nil
+}
+
+// JSONFileJobStore persists every task as one JSON object in a single
+// file, rewritten in full on each mutation. That's wasteful for a large
+// task count, but this is a demo-scale scheduler, not a production job
+// store, and it keeps the on-disk format trivial to inspect by hand.
+type JSONFileJobStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewJSONFileJobStore(path string) *JSONFileJobStore {
+	return &JSONFileJobStore{path: path}
+}
+
+func (st *JSONFileJobStore) readAllLocked() (map[string]*taskqueue.Task, error) {
+	data, err := os.ReadFile(st.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]*taskqueue.Task), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jobstore: read %s: %w", st.path, err)
+	}
+	tasks := make(map[string]*taskqueue.Task)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, fmt.Errorf("jobstore: decode %s: %w", st.path, err)
+		}
+	}
+	return tasks, nil
+}
+
+func (st *JSONFileJobStore) writeAllLocked(tasks map[string]*taskqueue.Task) error {
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("jobstore: encode: %w", err)
+	}
+	if err := os.WriteFile(st.path, data, 0o644); err != nil {
+		return fmt.Errorf("jobstore: write %s: %w", st.path, err)
+	}
+	return nil
+}
+
+func (st *JSONFileJobStore) Save(task *taskqueue.Task) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tasks, err := st.readAllLocked()
+	if err != nil {
+		return err
+	}
+	clone := *task
+	tasks[task.ID] = &clone
+	return st.writeAllLocked(tasks)
+}
+
+func (st *JSONFileJobStore) Load() ([]*taskqueue.Task, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tasks, err := st.readAllLocked()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*taskqueue.Task, 0, len(tasks))
+	for _, t := range tasks {
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func (st *JSONFileJobStore) UpdateStatus(id, status string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tasks, err := st.readAllLocked()
+	if err != nil {
+		return err
+	}
+	t, ok := tasks[id]
+	if !ok {
+		return fmt.Errorf("jobstore: unknown task %s", id)
+	}
+	t.Status = status
+	return st.writeAllLocked(tasks)
+}
+
+func (st *JSONFileJobStore) RecordAttempt(id string, attemptErr error) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tasks, err := st.readAllLocked()
+	if err != nil {
+		return err
+	}
+	t, ok := tasks[id]
+	if !ok {
+		return fmt.Errorf("jobstore: unknown task %s", id)
+	}
+	t.Attempts++
+	if attemptErr != nil {
+		t.LastError = attemptErr.Error()
+	}
+	return st.writeAllLocked(tasks)
+}
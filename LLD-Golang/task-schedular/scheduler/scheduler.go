@@ -3,42 +3,302 @@ package scheduler
 import (
 	"container/heap"
 	"fmt"
+	"math/rand"
+	"sync"
 	"task-schedular/taskqueue"
 	"time"
 )
 
+// priorityWeightBase anchors the weighted round-robin used by
+// nextReadyTaskLocked: priority 0 (highest) gets this many credits per
+// round, and each step down in priority loses one, bottoming out at 1 so
+// even the lowest priority bucket still gets dispatched periodically
+// instead of being starved by a steady stream of higher-priority work.
+const priorityWeightBase = 5
+
+func dispatchWeight(priority int) int {
+	w := priorityWeightBase - priority
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
 type Schedular interface {
 	AddTask(task *taskqueue.Task)
 	Start()
 	Stop()
+	CancelTask(id string) bool
+	PauseTask(id string) bool
+	ResumeTask(id string) bool
 	GetTaskStatus(id string) string
+	GetResult(id string) (*taskqueue.Result, bool)
+}
+
+// BackoffPolicy controls the delay between retry attempts within
+// executeTask.
+// WHY: retrying instantly on failure just hammers a struggling dependency
+// again; exponential backoff gives it room to recover. Jitter keeps a
+// batch of tasks that failed together from all retrying in lockstep,
+// mirroring taskqueue.Scheduler's backoffDelay.
+type BackoffPolicy struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{Base: 200 * time.Millisecond, Max: 5 * time.Second}
+}
+
+func (p BackoffPolicy) delay(attempt int) time.Duration {
+	d := p.Base * time.Duration(1<<uint(attempt))
+	if d > p.Max {
+		d = p.Max
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d + jitter
+}
+
+// DeadLetteredTask is a task that exhausted every retry.
+type DeadLetteredTask struct {
+	Task     *taskqueue.Task
+	LastErr  error
+	Attempts int
+	At       time.Time
+}
+
+// DeadLetterQueue archives tasks that never succeeded so an operator can
+// inspect or manually replay them instead of them silently vanishing.
+type DeadLetterQueue struct {
+	mu    sync.Mutex
+	items []DeadLetteredTask
+}
+
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{}
+}
+
+func (q *DeadLetterQueue) Archive(item DeadLetteredTask) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, item)
+}
+
+func (q *DeadLetterQueue) All() []DeadLetteredTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]DeadLetteredTask, len(q.items))
+	copy(out, q.items)
+	return out
+}
+
+// ResultWriter is notified of every completed (or permanently failed) task
+// result as it lands.
+// WHY: pluggable so a caller can persist results (file, DB, metrics) without
+// TaskSchedular knowing about any of those concerns - it just calls Write.
+type ResultWriter interface {
+	Write(result *taskqueue.Result)
+}
+
+// InMemoryResultWriter is the default writer: it retains the last
+// `retention` results per task ID in memory.
+type InMemoryResultWriter struct {
+	mu        sync.Mutex
+	retention int
+	results   map[string][]*taskqueue.Result
+}
+
+// NewInMemoryResultWriter keeps up to `retention` results per task ID
+// (0 means unbounded). WHY: recurring tasks would otherwise grow their
+// history forever.
+func NewInMemoryResultWriter(retention int) *InMemoryResultWriter {
+	return &InMemoryResultWriter{retention: retention, results: make(map[string][]*taskqueue.Result)}
+}
+
+func (w *InMemoryResultWriter) Write(result *taskqueue.Result) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	history := append(w.results[result.TaskID], result)
+	if w.retention > 0 && len(history) > w.retention {
+		history = history[len(history)-w.retention:]
+	}
+	w.results[result.TaskID] = history
+}
+
+// Latest returns the most recent result recorded for taskID, if any.
+func (w *InMemoryResultWriter) Latest(taskID string) (*taskqueue.Result, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	history := w.results[taskID]
+	if len(history) == 0 {
+		return nil, false
+	}
+	return history[len(history)-1], true
+}
+
+// History returns every retained result for taskID, oldest first.
+func (w *InMemoryResultWriter) History(taskID string) []*taskqueue.Result {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]*taskqueue.Result, len(w.results[taskID]))
+	copy(out, w.results[taskID])
+	return out
 }
 
 type TaskSchedular struct {
-	queue     taskqueue.TaskQueue
-	stopChan  chan struct{}
-	running   bool
-	taskMap   map[string]*taskqueue.Task // for status tracking
-	doneChans map[string]chan bool       // signal task completion
-	taskChan  chan *taskqueue.Task       // channel for worker pool
-	workers   int
+	mu           sync.Mutex
+	cond         *sync.Cond // signalled whenever AddTask changes what the dispatcher should wait for
+	queue        taskqueue.TaskQueue
+	queueVersion int // bumped on every queue change cond-waiters should notice
+	stopChan     chan struct{}
+	running      bool
+	taskMap      map[string]*taskqueue.Task // for status tracking
+	paused       map[string]*taskqueue.Task // tasks PauseTask pulled off the queue, keyed by ID, for ResumeTask to push back
+	cancelled    map[string]bool
+	doneChans    map[string]chan bool // signal task completion
+	taskChan     chan *taskqueue.Task // channel for worker pool
+	workers      int
+	resultWriter *InMemoryResultWriter
+	backoff      BackoffPolicy
+	deadLetters  *DeadLetterQueue
+	store        JobStore
+	wrrCredit    map[int]int // per-priority weighted round-robin credit, see nextReadyTaskLocked
 }
 
+// NewTaskScheduler creates a TaskSchedular backed by an InMemoryJobStore.
+// Use NewTaskSchedulerWithStore for a persistent JobStore such as
+// JSONFileJobStore.
 func NewTaskScheduler(numWorkers int) *TaskSchedular {
+	return NewTaskSchedulerWithStore(numWorkers, NewInMemoryJobStore())
+}
+
+func NewTaskSchedulerWithStore(numWorkers int, store JobStore) *TaskSchedular {
 	s := &TaskSchedular{
-		queue:     make(taskqueue.TaskQueue, 0),
-		stopChan:  make(chan struct{}),
-		running:   false,
-		taskMap:   make(map[string]*taskqueue.Task),
-		doneChans: make(map[string]chan bool),
-		taskChan:  make(chan *taskqueue.Task, numWorkers),
-		workers:   numWorkers,
+		queue:        make(taskqueue.TaskQueue, 0),
+		stopChan:     make(chan struct{}),
+		running:      false,
+		taskMap:      make(map[string]*taskqueue.Task),
+		paused:       make(map[string]*taskqueue.Task),
+		cancelled:    make(map[string]bool),
+		doneChans:    make(map[string]chan bool),
+		taskChan:     make(chan *taskqueue.Task, numWorkers),
+		workers:      numWorkers,
+		resultWriter: NewInMemoryResultWriter(10),
+		backoff:      DefaultBackoffPolicy(),
+		deadLetters:  NewDeadLetterQueue(),
+		store:        store,
+		wrrCredit:    make(map[int]int),
 	}
+	s.cond = sync.NewCond(&s.mu)
 	// start workers for task execution
 	s.startWorkers()
 	return s
 }
 
+// Recover reloads tasks from the JobStore and resubmits any that were
+// still pending or running when the scheduler last stopped. A task
+// whose Job is nil (e.g. one Load()ed back from JSONFileJobStore, since
+// funcs don't survive JSON) can't be run and is skipped - the caller is
+// expected to re-attach Job by ID and call AddTask itself for those.
+func (s *TaskSchedular) Recover() error {
+	tasks, err := s.store.Load()
+	if err != nil {
+		return fmt.Errorf("scheduler: recover: %w", err)
+	}
+	for _, task := range tasks {
+		if task.Job == nil {
+			continue
+		}
+		if task.Status == "pending" || task.Status == "running" {
+			s.AddTask(task)
+		}
+	}
+	return nil
+}
+
+// CancelTask removes a pending task from the queue so it never runs. A
+// task already dispatched to a worker (Status == "running") cannot be
+// cancelled mid-execution and CancelTask returns false.
+func (s *TaskSchedular) CancelTask(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.taskMap[id]
+	if !ok || task.Status == "running" || task.Status == "completed" {
+		return false
+	}
+
+	for i, t := range s.queue {
+		if t.ID == id {
+			heap.Remove(&s.queue, i)
+			break
+		}
+	}
+	delete(s.paused, id)
+	task.Status = "cancelled"
+	s.cancelled[id] = true
+	s.store.UpdateStatus(id, task.Status)
+	return true
+}
+
+// PauseTask pulls a pending task off the queue without cancelling it, so
+// ResumeTask can put it back later. A task already running, or already
+// in a terminal status, cannot be paused.
+func (s *TaskSchedular) PauseTask(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.taskMap[id]
+	if !ok {
+		return false
+	}
+	switch task.Status {
+	case "running", "completed", "failed", "cancelled", "paused":
+		return false
+	}
+
+	for i, t := range s.queue {
+		if t.ID == id {
+			heap.Remove(&s.queue, i)
+			break
+		}
+	}
+	task.Status = "paused"
+	s.paused[id] = task
+	s.store.UpdateStatus(id, task.Status)
+	return true
+}
+
+// ResumeTask puts a PauseTask'd task back on the queue as pending. It
+// only succeeds for a task this scheduler is currently holding paused.
+func (s *TaskSchedular) ResumeTask(id string) bool {
+	s.mu.Lock()
+	task, ok := s.paused[id]
+	if !ok {
+		s.mu.Unlock()
+		return false
+	}
+	delete(s.paused, id)
+	task.Status = "pending"
+	heap.Push(&s.queue, task)
+	s.queueVersion++
+	s.store.UpdateStatus(id, task.Status)
+	s.mu.Unlock()
+
+	s.cond.Broadcast()
+	return true
+}
+
+// DeadLetters returns every task archived after exhausting its retries.
+func (s *TaskSchedular) DeadLetters() []DeadLetteredTask {
+	return s.deadLetters.All()
+}
+
+// GetResult returns the most recent result recorded for a task, if any.
+func (s *TaskSchedular) GetResult(id string) (*taskqueue.Result, bool) {
+	return s.resultWriter.Latest(id)
+}
+
 func (s *TaskSchedular) startWorkers() {
 	for workerID := range make([]int, s.workers) {
 		go func() {
@@ -57,75 +317,298 @@ func (s *TaskSchedular) startWorkers() {
 }
 
 func (s *TaskSchedular) AddTask(task *taskqueue.Task) {
+	s.mu.Lock()
 	task.Status = "pending"
 	s.taskMap[task.ID] = task
-	s.doneChans[task.ID] = make(chan bool)
+	s.doneChans[task.ID] = make(chan bool, 1)
 	heap.Push(&s.queue, task)
+	s.queueVersion++
+	s.mu.Unlock()
+
+	s.store.Save(task)
+
+	// Wake the dispatcher in case it's sleeping until some later task's
+	// ExecutedAt and this one is due sooner.
+	s.cond.Broadcast()
 }
 
 func (s *TaskSchedular) Start() {
+	s.mu.Lock()
 	if s.running {
+		s.mu.Unlock()
 		return
 	}
-
 	s.running = true
+	s.mu.Unlock()
 
-	go func() {
-		for s.queue.Len() > 0 {
-			nextTask := s.queue[0]
-			delay := time.Until(nextTask.ExecutedAt)
+	go s.dispatchLoop()
+}
 
-			select {
-			case <-time.After(delay):
-				task := heap.Pop(&s.queue).(*taskqueue.Task)
-				task.Status = "running"
-				fmt.Printf("Task %s assigned to worker pool (Priority: %d) at %v\n", task.ID, task.Priority, time.Now())
-				s.taskChan <- task
-			case <-s.stopChan:
-				fmt.Println("Scheduler stopped")
-				s.running = false
+// dispatchLoop is the single goroutine that hands ready tasks to the
+// worker pool. It owns s.queue exclusively while running: every read or
+// mutation goes through s.mu, and it sleeps on s.cond (woken by AddTask or
+// Stop) instead of spinning, whenever there's nothing ready to dispatch.
+func (s *TaskSchedular) dispatchLoop() {
+	for {
+		s.mu.Lock()
+		for s.queue.Len() == 0 {
+			if !s.running {
+				s.mu.Unlock()
 				return
 			}
+			s.cond.Wait()
+		}
+		if !s.running {
+			s.mu.Unlock()
+			return
+		}
+
+		task := s.nextReadyTaskLocked()
+		if task == nil {
+			// Queue isn't empty, but nothing in it is due yet - sleep until
+			// the earliest ExecutedAt, or until AddTask wakes us early.
+			wait := time.Until(s.earliestExecutedAtLocked())
+			version := s.queueVersion
+			s.mu.Unlock()
+
+			select {
+			case <-time.After(wait):
+			case <-s.waitForWake(version):
+			}
+			continue
+		}
+		s.mu.Unlock()
+
+		if s.isCancelled(task.ID) {
+			continue
+		}
+		task.Status = "running"
+		s.store.UpdateStatus(task.ID, task.Status)
+		fmt.Printf("Task %s assigned to worker pool (Priority: %d) at %v\n", task.ID, task.Priority, time.Now())
+		s.taskChan <- task
+	}
+}
+
+// nextReadyTaskLocked removes and returns the next task that should run
+// right now, or nil if nothing is due yet. Caller must hold s.mu.
+//
+// When more than one priority bucket has work due, picking the strict
+// highest-priority task every time would let a steady stream of
+// high-priority tasks starve low-priority ones forever. Instead this uses
+// smooth weighted round-robin across the buckets that are currently ready:
+// each bucket accrues dispatchWeight(priority) credits per round, the
+// bucket with the most credit wins and pays back the total, and the
+// earliest-due task within the winning bucket is chosen.
+func (s *TaskSchedular) nextReadyTaskLocked() *taskqueue.Task {
+	now := time.Now()
+	ready := make(map[int][]int) // priority -> indices into s.queue
+	for i, t := range s.queue {
+		if !t.ExecutedAt.After(now) {
+			ready[t.Priority] = append(ready[t.Priority], i)
 		}
-		s.running = false
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+
+	totalWeight := 0
+	for priority := range ready {
+		w := dispatchWeight(priority)
+		totalWeight += w
+		s.wrrCredit[priority] += w
+	}
+
+	best := -1
+	var bestCredit int
+	for priority := range ready {
+		if best == -1 || s.wrrCredit[priority] > bestCredit {
+			best = priority
+			bestCredit = s.wrrCredit[priority]
+		}
+	}
+	s.wrrCredit[best] -= totalWeight
+
+	indices := ready[best]
+	chosen := indices[0]
+	for _, i := range indices[1:] {
+		if s.queue[i].ExecutedAt.Before(s.queue[chosen].ExecutedAt) {
+			chosen = i
+		}
+	}
+
+	task := s.queue[chosen]
+	heap.Remove(&s.queue, chosen)
+	return task
+}
+
+// earliestExecutedAtLocked returns the soonest ExecutedAt across the whole
+// queue. Caller must hold s.mu and the queue must be non-empty. It has to
+// scan every task, not just s.queue[0], because the heap orders by
+// priority first - a low-priority task due in a second can still sit
+// behind a high-priority one due in an hour.
+func (s *TaskSchedular) earliestExecutedAtLocked() time.Time {
+	earliest := s.queue[0].ExecutedAt
+	for _, t := range s.queue[1:] {
+		if t.ExecutedAt.Before(earliest) {
+			earliest = t.ExecutedAt
+		}
+	}
+	return earliest
+}
+
+// waitForWake returns a channel that fires once s.queueVersion moves past
+// sinceVersion (an AddTask happened) or the scheduler stops. Run as its
+// own goroutine because sync.Cond has no timeout, and the dispatcher needs
+// something select-able alongside its ExecutedAt timer.
+func (s *TaskSchedular) waitForWake(sinceVersion int) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	go func() {
+		s.mu.Lock()
+		for s.queueVersion == sinceVersion && s.running {
+			s.cond.Wait()
+		}
+		s.mu.Unlock()
+		ch <- struct{}{}
 	}()
+	return ch
+}
+
+func (s *TaskSchedular) isCancelled(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancelled[id]
 }
 
 func (s *TaskSchedular) executeTask(task *taskqueue.Task) {
 	attempts := 0
-	for attempts <= task.MaxRetries {
-		defer func() {
-			if r := recover(); r != nil {
-				fmt.Printf("Task %s panicked: %v\n", task.ID, r)
-			}
-		}()
+	var output any
+	var jobErr error
 
-		task.Job()
-		task.Status = "completed"
-		s.doneChans[task.ID] <- true
-		break // Success
+	for attempts <= task.MaxRetries {
+		if attempts > 0 {
+			time.Sleep(s.backoff.delay(attempts - 1))
+		}
+		attempts++
+		output, jobErr = s.runJob(task)
+		s.store.RecordAttempt(task.ID, jobErr)
+		if jobErr == nil {
+			task.Status = "completed"
+			s.store.UpdateStatus(task.ID, task.Status)
+			s.signalDone(task.ID, true)
+			break
+		}
 	}
 
-	if task.Interval > 0 && task.Status == "completed" {
-		task.ExecutedAt = time.Now().Add(task.Interval)
-		task.Status = "pending"
-		heap.Push(&s.queue, task)
-	} else if task.Status != "completed" {
+	s.resultWriter.Write(&taskqueue.Result{
+		TaskID:      task.ID,
+		Output:      output,
+		Err:         jobErr,
+		Attempts:    attempts,
+		CompletedAt: time.Now(),
+	})
+
+	if task.Status == "completed" {
+		if next, ok, err := nextFireTime(task); err != nil {
+			fmt.Printf("Task %s: not rescheduling, bad CronSpec: %v\n", task.ID, err)
+		} else if ok {
+			task.ExecutedAt = next
+			task.Status = "pending"
+			s.mu.Lock()
+			heap.Push(&s.queue, task)
+			s.queueVersion++
+			s.mu.Unlock()
+			s.store.UpdateStatus(task.ID, task.Status)
+			s.cond.Broadcast()
+		}
+	} else {
 		fmt.Printf("Task %s failed after %d retries\n", task.ID, task.MaxRetries)
-		s.doneChans[task.ID] <- false
+		task.Status = "failed"
+		s.store.UpdateStatus(task.ID, task.Status)
+		s.deadLetters.Archive(DeadLetteredTask{Task: task, LastErr: jobErr, Attempts: attempts, At: time.Now()})
+		s.signalDone(task.ID, false)
 	}
 }
 
+// nextFireTime reports when task should next run if it's recurring:
+// CronSpec, when set, takes precedence over Interval. ok is false for a
+// one-shot task (neither set), in which case it isn't rescheduled at all.
+func nextFireTime(task *taskqueue.Task) (next time.Time, ok bool, err error) {
+	if task.CronSpec != "" {
+		sched, err := taskqueue.ParseCronSpec(task.CronSpec)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return sched.Next(time.Now()), true, nil
+	}
+	if task.Interval > 0 {
+		return time.Now().Add(task.Interval), true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// signalDone reports a task's completion on its doneChan without blocking.
+// WHY buffered(1) + non-blocking send: nothing in this package currently
+// reads doneChans, and a blocking send to an unread channel would wedge
+// the worker goroutine running executeTask forever.
+func (s *TaskSchedular) signalDone(taskID string, ok bool) {
+	s.mu.Lock()
+	ch := s.doneChans[taskID]
+	s.mu.Unlock()
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- ok:
+	default:
+	}
+}
+
+// runJob invokes task.Job, converting a panic into an error so one bad job
+// can't take down the worker goroutine.
+func (s *TaskSchedular) runJob(task *taskqueue.Task) (output any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task %s panicked: %v", task.ID, r)
+		}
+	}()
+	return task.Job()
+}
+
 func (s *TaskSchedular) Stop() {
-	if s.running {
-		close(s.stopChan)
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
 	}
+	s.running = false
+	s.mu.Unlock()
+
+	close(s.stopChan)
+	s.cond.Broadcast()
 }
 
+// GetTaskStatus returns id's current status. It reads through taskMap
+// when the task is still live in this process (the common case), and
+// falls back to the JobStore - which every status transition above
+// writes through to - for one this process only knows about via a prior
+// Recover(), e.g. a task some other process Save()d to a shared
+// JSONFileJobStore.
 func (s *TaskSchedular) GetTaskStatus(id string) string {
-	if task, exists := s.taskMap[id]; exists {
+	s.mu.Lock()
+	task, exists := s.taskMap[id]
+	s.mu.Unlock()
+	if exists {
 		return task.Status
 	}
 
+	tasks, err := s.store.Load()
+	if err != nil {
+		return "unknown"
+	}
+	for _, t := range tasks {
+		if t.ID == id {
+			return t.Status
+		}
+	}
 	return "unknown"
 }
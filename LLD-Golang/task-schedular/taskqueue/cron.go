@@ -0,0 +1,140 @@
+package taskqueue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed 5-field cron expression (minute hour
+// day-of-month month day-of-week), supporting "*", comma lists,
+// "lo-hi" ranges, and "/step" within a field (e.g. "*/15").
+type CronSchedule struct {
+	minutes, hours, doms, months, dows map[int]bool
+	domWild, dowWild                   bool // whether that field's text was literally "*"
+}
+
+// ParseCronSpec parses a standard 5-field cron expression.
+func ParseCronSpec(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d in %q", len(fields), spec)
+	}
+
+	minutes, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("cron: minute field: %w", err)
+	}
+	hours, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("cron: hour field: %w", err)
+	}
+	doms, err := parseCronField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("cron: day-of-month field: %w", err)
+	}
+	months, err := parseCronField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("cron: month field: %w", err)
+	}
+	dows, err := parseCronField(fields[4], 0, 7)
+	if err != nil {
+		return nil, fmt.Errorf("cron: day-of-week field: %w", err)
+	}
+	if dows[7] { // 7 and 0 both mean Sunday
+		dows[0] = true
+		delete(dows, 7)
+	}
+
+	return &CronSchedule{
+		minutes: minutes, hours: hours, doms: doms, months: months, dows: dows,
+		domWild: fields[2] == "*", dowWild: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField expands one comma-separated cron field into the set of
+// values it matches, where each comma-separated part is "*", a single
+// number, a "lo-hi" range, or any of those with a trailing "/step".
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	out := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		rangePart := part
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			s, err := strconv.Atoi(part[idx+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			step = s
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+			// full range already set above
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			l, errL := strconv.Atoi(bounds[0])
+			h, errH := strconv.Atoi(bounds[1])
+			if errL != nil || errH != nil {
+				return nil, fmt.Errorf("invalid range %q", rangePart)
+			}
+			lo, hi = l, h
+		default:
+			v, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = v, v
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q out of range [%d,%d]", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			out[v] = true
+		}
+	}
+	return out, nil
+}
+
+// cronScanLimit bounds Next's minute-by-minute search so a malformed or
+// impossible schedule (e.g. Feb 30) returns promptly instead of looping
+// forever.
+const cronScanLimit = 5 * 366 * 24 * 60
+
+// Next returns the first minute strictly after `after` that this
+// schedule matches.
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for i := 0; i < cronScanLimit; i++ {
+		if c.matches(t) {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return t
+}
+
+func (c *CronSchedule) matches(t time.Time) bool {
+	if !c.minutes[t.Minute()] || !c.hours[t.Hour()] || !c.months[int(t.Month())] {
+		return false
+	}
+	domOK := c.doms[t.Day()]
+	dowOK := c.dows[int(t.Weekday())]
+	if c.domWild || c.dowWild {
+		// Standard cron rule: once either field is restricted, it alone
+		// gates the match; only when both are wildcards (or both
+		// restricted) do they combine.
+		if c.domWild && c.dowWild {
+			return true
+		}
+		if c.domWild {
+			return dowOK
+		}
+		return domOK
+	}
+	return domOK || dowOK
+}
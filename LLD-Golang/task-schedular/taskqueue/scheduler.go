@@ -0,0 +1,310 @@
+package taskqueue
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// TaskStore persists the tasks a Scheduler hasn't run yet. WHY an
+// interface instead of baking the heap into Scheduler directly: the
+// default is an in-memory heap, but a caller that needs tasks to survive
+// a restart can swap in a store backed by a database without touching
+// Scheduler itself.
+type TaskStore interface {
+	Push(task *Task)
+	Pop() (*Task, bool)  // removes and returns the next task by queue order
+	Peek() (*Task, bool) // returns the next task without removing it
+	Remove(id string) bool
+	Len() int
+}
+
+// InMemoryTaskStore is the default TaskStore: a heap-ordered in-process
+// queue. Not safe for concurrent use on its own - Scheduler serializes
+// every access behind its own mutex.
+type InMemoryTaskStore struct {
+	queue TaskQueue
+}
+
+func NewInMemoryTaskStore() *InMemoryTaskStore {
+	return &InMemoryTaskStore{queue: make(TaskQueue, 0)}
+}
+
+func (st *InMemoryTaskStore) Push(task *Task) {
+	heap.Push(&st.queue, task)
+}
+
+func (st *InMemoryTaskStore) Pop() (*Task, bool) {
+	if st.queue.Len() == 0 {
+		return nil, false
+	}
+	return heap.Pop(&st.queue).(*Task), true
+}
+
+func (st *InMemoryTaskStore) Peek() (*Task, bool) {
+	if st.queue.Len() == 0 {
+		return nil, false
+	}
+	return st.queue[0], true
+}
+
+func (st *InMemoryTaskStore) Remove(id string) bool {
+	for i, t := range st.queue {
+		if t.ID == id {
+			heap.Remove(&st.queue, i)
+			return true
+		}
+	}
+	return false
+}
+
+func (st *InMemoryTaskStore) Len() int {
+	return st.queue.Len()
+}
+
+// SchedulerHooks lets a caller observe job outcomes without Scheduler
+// knowing anything about metrics, logging, or alerting.
+type SchedulerHooks struct {
+	OnSuccess func(task *Task, output any)
+	OnFailure func(task *Task, err error)
+	OnRetry   func(task *Task, attempt int, err error)
+}
+
+// Scheduler runs tasks from a TaskStore on a fixed worker pool: it sleeps
+// until the next task's ExecutedAt instead of busy-looping, retries a
+// failing Job up to MaxRetries with exponential backoff plus jitter, and
+// re-enqueues recurring tasks (CronSpec set or Interval > 0) after each
+// successful run.
+type Scheduler struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	store     TaskStore
+	workers   int
+	taskChan  chan *Task
+	cancelled map[string]bool
+	running   bool
+	hooks     SchedulerHooks
+
+	backoffBase time.Duration
+	backoffMax  time.Duration
+}
+
+// NewScheduler creates a Scheduler with the given worker count. A nil
+// store defaults to NewInMemoryTaskStore.
+func NewScheduler(workers int, store TaskStore, hooks SchedulerHooks) *Scheduler {
+	if store == nil {
+		store = NewInMemoryTaskStore()
+	}
+	s := &Scheduler{
+		store:       store,
+		workers:     workers,
+		taskChan:    make(chan *Task, workers),
+		cancelled:   make(map[string]bool),
+		hooks:       hooks,
+		backoffBase: 200 * time.Millisecond,
+		backoffMax:  5 * time.Second,
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Submit adds task to the store and wakes the dispatcher in case it's
+// sleeping until some later task's ExecutedAt and this one is due sooner.
+func (s *Scheduler) Submit(task *Task) {
+	task.Status = "pending"
+	s.mu.Lock()
+	s.store.Push(task)
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+// Cancel removes a pending task from the store so it never runs. A task
+// already dispatched to a worker cannot be cancelled mid-execution.
+func (s *Scheduler) Cancel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.store.Remove(id) {
+		return false
+	}
+	s.cancelled[id] = true
+	return true
+}
+
+// Start launches the dispatcher and worker pool. Cancelling ctx is what
+// actually tears the workers down; Stop only stops the dispatcher from
+// handing out new work, so callers that want a full shutdown should
+// cancel ctx too.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	for i := 0; i < s.workers; i++ {
+		go s.runWorker(ctx)
+	}
+	go s.dispatch(ctx)
+}
+
+// Stop halts the dispatcher so no further tasks are handed to workers.
+// Workers already holding a task finish it; ctx cancellation is what ends
+// the workers themselves.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+func (s *Scheduler) runWorker(ctx context.Context) {
+	for {
+		select {
+		case task := <-s.taskChan:
+			s.execute(ctx, task)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch is the single goroutine that pulls due tasks off the store and
+// hands them to the worker pool, sleeping until the next ExecutedAt
+// (woken early by Submit/re-enqueue) rather than busy-looping.
+func (s *Scheduler) dispatch(ctx context.Context) {
+	for {
+		s.mu.Lock()
+		for s.store.Len() == 0 {
+			if !s.running {
+				s.mu.Unlock()
+				return
+			}
+			s.cond.Wait()
+		}
+		if !s.running {
+			s.mu.Unlock()
+			return
+		}
+
+		next, _ := s.store.Peek()
+		wait := time.Until(next.ExecutedAt)
+		if wait > 0 {
+			// Sleep on cond, so Submit, a re-enqueue or Stop ends it early.
+			wake := func() { s.mu.Lock(); s.cond.Broadcast(); s.mu.Unlock() }
+			timer := time.AfterFunc(wait, wake)
+			stopCtx := context.AfterFunc(ctx, wake)
+			s.cond.Wait()
+			timer.Stop()
+			stopCtx()
+			s.mu.Unlock()
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		task, _ := s.store.Pop()
+		cancelled := s.cancelled[task.ID]
+		s.mu.Unlock()
+
+		if cancelled {
+			continue
+		}
+
+		task.Status = "running"
+		select {
+		case s.taskChan <- task:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// execute runs task.Job, retrying up to task.MaxRetries with exponential
+// backoff plus jitter, then transitions Status to COMPLETED or FAILED and
+// fires the matching hook. A successful recurring task is re-enqueued at
+// its CronSpec's next fire time, or else Interval from now.
+func (s *Scheduler) execute(ctx context.Context, task *Task) {
+	attempts := 0
+	var output any
+	var jobErr error
+
+	for attempts <= task.MaxRetries {
+		if attempts > 0 {
+			if s.hooks.OnRetry != nil {
+				s.hooks.OnRetry(task, attempts, jobErr)
+			}
+			select {
+			case <-time.After(s.backoffDelay(attempts)):
+			case <-ctx.Done():
+				return
+			}
+		}
+		attempts++
+		output, jobErr = s.runJob(task)
+		if jobErr == nil {
+			break
+		}
+	}
+
+	if jobErr == nil {
+		task.Status = "completed"
+		if s.hooks.OnSuccess != nil {
+			s.hooks.OnSuccess(task, output)
+		}
+	} else {
+		task.Status = "failed"
+		if s.hooks.OnFailure != nil {
+			s.hooks.OnFailure(task, jobErr)
+		}
+	}
+
+	if task.Status == "completed" && (task.CronSpec != "" || task.Interval > 0) {
+		next := time.Now().Add(task.Interval)
+		if task.CronSpec != "" {
+			sched, err := ParseCronSpec(task.CronSpec)
+			if err != nil {
+				return // a bad CronSpec is not rescheduled
+			}
+			next = sched.Next(time.Now())
+		}
+		task.ExecutedAt = next
+		task.Status = "pending"
+		s.mu.Lock()
+		s.store.Push(task)
+		s.mu.Unlock()
+		s.cond.Broadcast()
+	}
+}
+
+// backoffDelay doubles backoffBase per attempt, caps at backoffMax, and
+// adds up to 50% jitter so a batch of tasks that failed together don't
+// all retry in lockstep.
+func (s *Scheduler) backoffDelay(attempt int) time.Duration {
+	d := s.backoffBase * time.Duration(1<<uint(attempt-1))
+	if d > s.backoffMax {
+		d = s.backoffMax
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d + jitter
+}
+
+// runJob invokes task.Job, converting a panic into an error so one bad
+// job can't take down a worker goroutine.
+func (s *Scheduler) runJob(task *Task) (output any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task %s panicked: %v", task.ID, r)
+		}
+	}()
+	return task.Job()
+}
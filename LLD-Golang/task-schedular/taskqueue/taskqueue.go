@@ -5,11 +5,25 @@ import "time"
 type Task struct {
 	ID         string
 	ExecutedAt time.Time
-	Interval   time.Duration // for recurring tasks
+	Interval   time.Duration // for recurring tasks; ignored if CronSpec is set
+	CronSpec   string        // 5-field cron expression (min hour dom mon dow); takes precedence over Interval when both are set
 	Priority   int           // 0 = highest priority
-	Job        func()
+	Job        func() (any, error) `json:"-"` // funcs don't survive JSON persistence; a JobStore-recovered Task needs one re-attached before it can run
 	MaxRetries int
 	Status     string // PENDING, RUNNING, COMPLETED
+	Attempts   int    // attempts JobStore.RecordAttempt counted on the stored copy since it was Saved; not reset between recurring runs
+	LastError  string // Err.Error() of the most recent failed attempt, if any
+}
+
+// Result captures what happened the last time a task's Job ran.
+// WHY: callers need more than a status string to know whether a task
+// succeeded, what it produced, and how many attempts it took.
+type Result struct {
+	TaskID      string
+	Output      any
+	Err         error
+	Attempts    int
+	CompletedAt time.Time
 }
 
 // priority queue of tasks
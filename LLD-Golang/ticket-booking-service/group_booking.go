@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// GroupBookingError identifies which seat aborted a ConfirmBookingGroup saga,
+// so a caller can tell the user exactly what went wrong instead of just
+// "booking failed". When a reservation itself cannot be found, SeatID holds
+// that reservation's ID.
+type GroupBookingError struct {
+	SeatID string
+	Err    error
+}
+
+func (e *GroupBookingError) Error() string {
+	if e.SeatID == "" {
+		return fmt.Sprintf("group booking aborted: %v", e.Err)
+	}
+	return fmt.Sprintf("group booking aborted at seat %s: %v", e.SeatID, e.Err)
+}
+
+func (e *GroupBookingError) Unwrap() error {
+	return e.Err
+}
+
+// reservedSeat pairs a reservation with the seat it holds so the saga below
+// can sort/lock/compensate by seat without repeated repository lookups.
+type reservedSeat struct {
+	res  *Reservation
+	seat *Seat
+}
+
+// ConfirmBookingGroup confirms every reservation in reservationIDs as one
+// atomic saga: all seats lock, validate, charge, and commit together, or
+// none of them do.
+//
+// WHY: ConfirmBooking only ever touches one seat, but ReserveSeats already
+// supports booking a whole party at once. Confirming them one at a time
+// would let a mid-party payment failure leave some seats booked and others
+// not, which is a worse outcome than just failing the whole group.
+func (s *TicketBookingService) ConfirmBookingGroup(ctx context.Context, reservationIDs []string, payReq PaymentRequest, idempotencyKey string) (*Booking, error) {
+	if len(reservationIDs) == 0 {
+		return nil, errors.New("no reservations specified")
+	}
+
+	idemKey := ""
+	if idempotencyKey != "" {
+		idemKey = "idempotency:" + idempotencyKey
+		val, found, err := s.idempotencyStore.Get(ctx, idemKey)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return s.bookingRepo.FindByID(ctx, val)
+		}
+	}
+
+	items := make([]reservedSeat, 0, len(reservationIDs))
+	for _, rid := range reservationIDs {
+		res, err := s.reservationRepo.FindByID(ctx, rid)
+		if err != nil {
+			return nil, &GroupBookingError{SeatID: rid, Err: err}
+		}
+		seat, err := s.seatRepo.FindByID(ctx, res.SeatID)
+		if err != nil {
+			return nil, &GroupBookingError{SeatID: res.SeatID, Err: err}
+		}
+		items = append(items, reservedSeat{res: res, seat: seat})
+	}
+
+	// Sorted locking order (reusing ReserveSeats' convention of sorting by
+	// seat number) so two overlapping group bookings can never deadlock.
+	sort.Slice(items, func(i, j int) bool { return items[i].seat.SeatNumber < items[j].seat.SeatNumber })
+
+	eventID := items[0].res.EventID
+	userID := items[0].res.UserID
+	for _, it := range items {
+		if it.res.EventID != eventID || it.res.UserID != userID {
+			return nil, &GroupBookingError{SeatID: it.seat.SeatID, Err: errors.New("reservations span multiple events or users")}
+		}
+		if it.res.Status != ReservationActive {
+			return nil, &GroupBookingError{SeatID: it.seat.SeatID, Err: ErrInvalidReservation}
+		}
+		if time.Now().After(it.res.ExpiresAt) {
+			return nil, &GroupBookingError{SeatID: it.seat.SeatID, Err: ErrReservationExpired}
+		}
+	}
+
+	var acquiredLocks []struct{ key, val string }
+	defer s.releaseLocks(ctx, acquiredLocks)
+	for _, it := range items {
+		lockKey := fmt.Sprintf("%s%d:%s", lockPrefix, eventID, it.seat.SeatNumber)
+		lockVal := randString(16)
+		ok, _, err := s.lockManager.TryLock(ctx, lockKey, lockVal, 30*time.Second)
+		if err != nil {
+			return nil, &GroupBookingError{SeatID: it.seat.SeatID, Err: err}
+		}
+		if !ok {
+			return nil, &GroupBookingError{SeatID: it.seat.SeatID, Err: ErrSeatNotAvailable}
+		}
+		acquiredLocks = append(acquiredLocks, struct{ key, val string }{lockKey, lockVal})
+	}
+
+	var total int64
+	for _, it := range items {
+		total += it.seat.Price
+	}
+
+	groupPayReq := payReq
+	groupPayReq.Amount = total
+	payResp, err := s.paymentService.Process(ctx, groupPayReq)
+	if err != nil {
+		return nil, &GroupBookingError{Err: err}
+	}
+	if !payResp.Success {
+		return nil, &GroupBookingError{Err: fmt.Errorf("payment failed: %s", payResp.ErrorMessage)}
+	}
+
+	now := time.Now()
+	booking := &Booking{
+		EventID:          eventID,
+		UserID:           userID,
+		TotalAmount:      total,
+		Status:           "CONFIRMED",
+		PaymentID:        payResp.PaymentID,
+		PaymentStatus:    "SUCCESS",
+		BookingReference: generateBookingReference(),
+		ConfirmedAt:      &now,
+	}
+
+	// committed tracks how far the saga got, so abort only has to
+	// compensate the seats/reservations it actually touched.
+	var committed []reservedSeat
+	abort := func(abortErr error) (*Booking, error) {
+		s.paymentService.Refund(ctx, payResp.PaymentID)
+		for _, it := range committed {
+			if time.Now().Before(it.res.ExpiresAt) {
+				it.seat.Status = SeatReserved
+				it.seat.ReservedBy = &it.res.UserID
+				it.seat.ReservedUntil = &it.res.ExpiresAt
+			} else {
+				it.seat.Status = SeatAvailable
+				it.seat.ReservedBy = nil
+				it.seat.ReservedUntil = nil
+			}
+			it.seat.BookingID = nil
+			s.seatRepo.Save(ctx, it.seat)
+			it.res.Status = ReservationActive
+			s.reservationRepo.Save(ctx, it.res)
+		}
+		booking.Status = "CANCELLED"
+		booking.PaymentStatus = "REFUNDED"
+		s.bookingRepo.Save(ctx, booking)
+		return nil, abortErr
+	}
+
+	if err := s.bookingRepo.Save(ctx, booking); err != nil {
+		return abort(&GroupBookingError{Err: err})
+	}
+
+	for _, it := range items {
+		if it.seat.Status != SeatReserved || it.seat.ReservedBy == nil || *it.seat.ReservedBy != userID {
+			return abort(&GroupBookingError{SeatID: it.seat.SeatID, Err: ErrInvalidSeatState})
+		}
+		committed = append(committed, it)
+
+		bs := &BookingSeat{BookingID: booking.BookingID, SeatID: it.seat.SeatID, Price: it.seat.Price}
+		if err := s.bookingSeatRepo.Save(ctx, bs); err != nil {
+			return abort(&GroupBookingError{SeatID: it.seat.SeatID, Err: err})
+		}
+
+		it.seat.Status = SeatBooked
+		it.seat.BookingID = &booking.BookingID
+		it.seat.ReservedBy = nil
+		it.seat.ReservedUntil = nil
+		if err := s.seatRepo.Save(ctx, it.seat); err != nil {
+			return abort(&GroupBookingError{SeatID: it.seat.SeatID, Err: err})
+		}
+
+		if err := s.reservationFSM.Fire(ctx, it.res, TriggerConfirm); err != nil {
+			return abort(&GroupBookingError{SeatID: it.seat.SeatID, Err: err})
+		}
+		if err := s.reservationRepo.Save(ctx, it.res); err != nil {
+			return abort(&GroupBookingError{SeatID: it.seat.SeatID, Err: err})
+		}
+	}
+
+	s.updateEventAvailableSeats(ctx, eventID, -len(items))
+
+	if idemKey != "" {
+		if _, err := s.idempotencyStore.SetNX(ctx, idemKey, booking.BookingID, 24*time.Hour); err != nil {
+			log.Printf("Failed to set idempotency key: %v", err)
+		}
+	}
+
+	return booking, nil
+}
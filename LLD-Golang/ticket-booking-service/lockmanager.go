@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RedlockManager implements the Redlock algorithm over a fixed set of
+// independent LockManager nodes (in production these would be separate Redis
+// instances; here they're separate InMemoryLockManager values so the quorum
+// logic can be exercised without a real cluster).
+//
+// WHY: a single lock node is a single point of failure - if it dies while
+// holding a lock, nothing ever releases it. Requiring a majority of nodes to
+// agree means the lock survives the loss of a minority of nodes, and the
+// fencing token returned is the highest one any acquiring node reported, so
+// callers still get a fencing guarantee against stale holders.
+type RedlockManager struct {
+	nodes []LockManager
+}
+
+// NewRedlockManager wires up a quorum lock manager over nodes. Quorum is
+// majority-of-nodes (len(nodes)/2 + 1), the same threshold the Redlock paper
+// uses.
+func NewRedlockManager(nodes []LockManager) *RedlockManager {
+	return &RedlockManager{nodes: nodes}
+}
+
+func (r *RedlockManager) quorum() int {
+	return len(r.nodes)/2 + 1
+}
+
+// TryLock attempts to acquire the lock on every node and succeeds only if a
+// quorum of nodes grant it. On failure to reach quorum, it unlocks any nodes
+// that did grant the lock so a partial acquisition doesn't linger.
+func (r *RedlockManager) TryLock(ctx context.Context, key, value string, ttl time.Duration) (acquired bool, fence int64, err error) {
+	granted := 0
+	maxFence := int64(0)
+	for _, node := range r.nodes {
+		ok, f, nodeErr := node.TryLock(ctx, key, value, ttl)
+		if nodeErr != nil {
+			continue
+		}
+		if ok {
+			granted++
+			if f > maxFence {
+				maxFence = f
+			}
+		}
+	}
+
+	if granted < r.quorum() {
+		r.Unlock(ctx, key, value)
+		return false, 0, nil
+	}
+	return true, maxFence, nil
+}
+
+// Extend renews ttl on every node currently holding the lock for (key,
+// value), and succeeds only if a quorum still holds it.
+func (r *RedlockManager) Extend(ctx context.Context, key, value string, ttl time.Duration) (extended bool, fence int64, err error) {
+	extendedCount := 0
+	maxFence := int64(0)
+	for _, node := range r.nodes {
+		ok, f, nodeErr := node.Extend(ctx, key, value, ttl)
+		if nodeErr != nil {
+			continue
+		}
+		if ok {
+			extendedCount++
+			if f > maxFence {
+				maxFence = f
+			}
+		}
+	}
+
+	if extendedCount < r.quorum() {
+		return false, 0, nil
+	}
+	return true, maxFence, nil
+}
+
+// Unlock releases (key, value) on every node, best-effort. WHY: a node that
+// never granted the lock simply no-ops on Unlock, so it's safe to call on
+// all of them unconditionally rather than tracking which ones succeeded.
+func (r *RedlockManager) Unlock(ctx context.Context, key, value string) error {
+	var firstErr error
+	for _, node := range r.nodes {
+		if err := node.Unlock(ctx, key, value); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("redlock: node unlock failed: %w", err)
+		}
+	}
+	return firstErr
+}
@@ -124,8 +124,13 @@ type EventRepository interface {
 // LockManager and IdempotencyStore Interfaces
 // =====================
 
+// LockManager is implemented by InMemoryLockManager (single-node demo) and
+// RedlockManager (quorum across N nodes, see lockmanager.go). TryLock
+// returns a fencing token alongside the boolean grant so callers can reject
+// a stale writer that held the lock past its TTL and only found out late.
 type LockManager interface {
-	TryLock(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	TryLock(ctx context.Context, key, value string, ttl time.Duration) (acquired bool, fence int64, err error)
+	Extend(ctx context.Context, key, value string, ttl time.Duration) (extended bool, fence int64, err error)
 	Unlock(ctx context.Context, key, value string) error
 }
 
@@ -159,22 +164,27 @@ type PaymentService interface {
 // =====================
 
 // InMemoryLockManager (Simulates distributed lock)
+// WHY: fence is one monotonically increasing counter shared by all keys.
+// Whoever holds a key's lock at a given moment knows their fence is higher
+// than any prior holder's, so a downstream store (e.g. eventRepo) can reject a write that
+// arrives from a lock holder that has since been superseded.
 type InMemoryLockManager struct {
 	mu    sync.Mutex
-	locks map[string]struct {
-		val string
-		exp time.Time
-	}
+	locks map[string]lockEntry
+	fence int64
+}
+
+type lockEntry struct {
+	val   string
+	exp   time.Time
+	fence int64
 }
 
 func NewInMemoryLockManager() *InMemoryLockManager {
-	return &InMemoryLockManager{locks: make(map[string]struct {
-		val string
-		exp time.Time
-	})}
+	return &InMemoryLockManager{locks: make(map[string]lockEntry)}
 }
 
-func (m *InMemoryLockManager) TryLock(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+func (m *InMemoryLockManager) TryLock(_ context.Context, key, value string, ttl time.Duration) (bool, int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	now := time.Now()
@@ -184,13 +194,26 @@ func (m *InMemoryLockManager) TryLock(_ context.Context, key, value string, ttl
 		}
 	}
 	if _, ok := m.locks[key]; ok {
-		return false, nil
+		return false, 0, nil
 	}
-	m.locks[key] = struct {
-		val string
-		exp time.Time
-	}{value, now.Add(ttl)}
-	return true, nil
+	m.fence++
+	m.locks[key] = lockEntry{val: value, exp: now.Add(ttl), fence: m.fence}
+	return true, m.fence, nil
+}
+
+// Extend renews ttl for an already-held lock without changing its fencing
+// token. WHY: a long-running critical section (e.g. a multi-seat saga)
+// shouldn't have to release and re-acquire just to avoid expiring mid-way.
+func (m *InMemoryLockManager) Extend(_ context.Context, key, value string, ttl time.Duration) (bool, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.locks[key]
+	if !ok || v.val != value {
+		return false, 0, nil
+	}
+	v.exp = time.Now().Add(ttl)
+	m.locks[key] = v
+	return true, v.fence, nil
 }
 
 func (m *InMemoryLockManager) Unlock(_ context.Context, key, value string) error {
@@ -470,6 +493,8 @@ type TicketBookingService struct {
 	paymentService     PaymentService
 	idempotencyStore   IdempotencyStore
 	reservationTimeout time.Duration
+	reservationFSM     *ReservationFSM
+	waitlistRepo       WaitlistRepository
 }
 
 func NewTicketBookingService(
@@ -481,12 +506,13 @@ func NewTicketBookingService(
 	lockManager LockManager,
 	paymentService PaymentService,
 	idempotencyStore IdempotencyStore,
+	waitlistRepo WaitlistRepository,
 	reservationTimeout time.Duration,
 ) *TicketBookingService {
 	if reservationTimeout == 0 {
 		reservationTimeout = 10 * time.Minute
 	}
-	return &TicketBookingService{
+	svc := &TicketBookingService{
 		seatRepo:           seatRepo,
 		reservationRepo:    reservationRepo,
 		bookingRepo:        bookingRepo,
@@ -495,8 +521,66 @@ func NewTicketBookingService(
 		lockManager:        lockManager,
 		paymentService:     paymentService,
 		idempotencyStore:   idempotencyStore,
+		waitlistRepo:       waitlistRepo,
 		reservationTimeout: reservationTimeout,
 	}
+	svc.reservationFSM = NewDefaultReservationFSM(svc.releaseSeatAction, svc.refundIfConfirmedAction)
+	return svc
+}
+
+// releaseSeatAction is the pluggable action run on EXPIRE/CANCEL from
+// ReservationActive. If anyone is waitlisted for the event, the seat goes
+// straight to them instead of sitting Available for whoever retries first.
+func (s *TicketBookingService) releaseSeatAction(ctx context.Context, res *Reservation) error {
+	seat, err := s.seatRepo.FindByID(ctx, res.SeatID)
+	if err != nil {
+		return err
+	}
+
+	if s.waitlistRepo != nil {
+		offered, err := s.offerToWaitlist(ctx, seat)
+		if err != nil {
+			return err
+		}
+		if offered {
+			return nil
+		}
+	}
+
+	seat.Status = SeatAvailable
+	seat.ReservedBy = nil
+	seat.ReservedUntil = nil
+	return s.seatRepo.Save(ctx, seat)
+}
+
+// refundIfConfirmedAction is the pluggable action run when a CONFIRMED
+// reservation is cancelled - e.g. a post-purchase cancellation flow.
+func (s *TicketBookingService) refundIfConfirmedAction(ctx context.Context, res *Reservation) error {
+	seat, err := s.seatRepo.FindByID(ctx, res.SeatID)
+	if err != nil {
+		return err
+	}
+	if seat.BookingID == nil {
+		return nil
+	}
+	booking, err := s.bookingRepo.FindByID(ctx, *seat.BookingID)
+	if err != nil {
+		return err
+	}
+	return s.paymentService.Refund(ctx, booking.PaymentID)
+}
+
+// CancelReservation drives the FSM's CANCEL trigger from whichever state
+// the reservation is currently in (ACTIVE or CONFIRMED).
+func (s *TicketBookingService) CancelReservation(ctx context.Context, reservationID string) error {
+	res, err := s.reservationRepo.FindByID(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	if err := s.reservationFSM.Fire(ctx, res, TriggerCancel); err != nil {
+		return err
+	}
+	return s.reservationRepo.Save(ctx, res)
 }
 
 const lockPrefix = "seat:lock:"
@@ -516,7 +600,7 @@ func (s *TicketBookingService) ReserveSeats(ctx context.Context, eventID int64,
 	for _, seatNum := range sortedSeats {
 		lockKey := fmt.Sprintf("%s%d:%s", lockPrefix, eventID, seatNum)
 		lockVal := randString(16)
-		ok, err := s.lockManager.TryLock(ctx, lockKey, lockVal, 30*time.Second)
+		ok, _, err := s.lockManager.TryLock(ctx, lockKey, lockVal, 30*time.Second)
 		if err != nil {
 			return nil, time.Time{}, err
 		}
@@ -580,7 +664,7 @@ func (s *TicketBookingService) ConfirmBooking(ctx context.Context, reservationID
 
 	lockKey := fmt.Sprintf("%s%d:%s", lockPrefix, res.EventID, seat.SeatNumber)
 	lockVal := randString(16)
-	ok, err := s.lockManager.TryLock(ctx, lockKey, lockVal, 30*time.Second)
+	ok, _, err := s.lockManager.TryLock(ctx, lockKey, lockVal, 30*time.Second)
 	if err != nil {
 		return nil, err
 	}
@@ -640,7 +724,9 @@ func (s *TicketBookingService) ConfirmBooking(ctx context.Context, reservationID
 		return nil, err
 	}
 
-	res.Status = ReservationConfirmed
+	if err = s.reservationFSM.Fire(ctx, res, TriggerConfirm); err != nil {
+		return nil, err
+	}
 	if err = s.reservationRepo.Save(ctx, res); err != nil {
 		return nil, err
 	}
@@ -700,21 +786,21 @@ func (s *TicketBookingService) releaseExpiredReservation(ctx context.Context, re
 
 	lockKey := fmt.Sprintf("%s%d:%s", lockPrefix, res.EventID, seat.SeatNumber)
 	lockVal := randString(16)
-	ok, _ := s.lockManager.TryLock(ctx, lockKey, lockVal, 5*time.Second)
+	ok, _, err := s.lockManager.TryLock(ctx, lockKey, lockVal, 5*time.Second)
+	if err != nil {
+		return
+	}
 	if !ok {
 		return
 	}
 	defer s.lockManager.Unlock(ctx, lockKey, lockVal)
 
 	if seat.Status == SeatReserved && seat.ReservedUntil != nil && time.Now().After(*seat.ReservedUntil) {
-		seat.Status = SeatAvailable
-		seat.ReservedBy = nil
-		seat.ReservedUntil = nil
-		s.seatRepo.Save(ctx, seat)
-
-		res.Status = ReservationExpired
+		if err := s.reservationFSM.Fire(ctx, res, TriggerExpire); err != nil {
+			log.Printf("Error expiring reservation %s: %v", res.ReservationID, err)
+			return
+		}
 		s.reservationRepo.Save(ctx, res)
-
 		s.updateEventAvailableSeats(ctx, res.EventID, 1)
 	}
 }
@@ -774,8 +860,15 @@ func main() {
 	bookingRepo := NewInMemoryBookingRepository()
 	bookingSeatRepo := NewInMemoryBookingSeatRepository()
 	eventRepo := NewInMemoryEventRepository()
-	lockManager := NewInMemoryLockManager()
+	// 3-node Redlock quorum in place of a single InMemoryLockManager: tolerates
+	// the loss of any one node since acquiring 2-of-3 still grants the lock.
+	lockManager := NewRedlockManager([]LockManager{
+		NewInMemoryLockManager(),
+		NewInMemoryLockManager(),
+		NewInMemoryLockManager(),
+	})
 	idempotencyStore := NewInMemoryIdempotencyStore()
+	waitlistRepo := NewInMemoryWaitlistRepository()
 	paymentService := MockPaymentService{}
 
 	// Seed data
@@ -792,6 +885,7 @@ func main() {
 		lockManager,
 		paymentService,
 		idempotencyStore,
+		waitlistRepo,
 		10*time.Minute,
 	)
 
@@ -802,6 +896,15 @@ func main() {
 	}
 	log.Printf("Reserved until %v: %+v", until, reservations)
 
+	// Event is sold out now; a third user joins the waitlist instead of failing.
+	if _, _, err := service.ReserveSeats(ctx, 1, []string{"A1"}, "user456"); err != nil {
+		if err := service.JoinWaitlist(ctx, 1, "user456", 1); err != nil {
+			log.Printf("Failed to join waitlist: %v", err)
+		} else {
+			log.Println("user456 joined the waitlist for event 1")
+		}
+	}
+
 	// Confirm one
 	booking, err := service.ConfirmBookingWithIdempotency(ctx, "key123", reservations[0].ReservationID, PaymentRequest{Amount: 10000, UserID: "user123"})
 	if err != nil {
@@ -809,6 +912,31 @@ func main() {
 	}
 	log.Printf("Booked: %+v", booking)
 
+	// Cancelling the other reservation offers the freed seat to user456.
+	if err := service.CancelReservation(ctx, reservations[1].ReservationID); err != nil {
+		log.Printf("Failed to cancel: %v", err)
+	}
+
+	// Group booking: a party of two seats confirmed atomically in one saga.
+	eventRepo.events[2] = &Event{EventID: 2, AvailableSeats: 2, Version: 0}
+	seatRepo.Save(ctx, &Seat{EventID: 2, SeatNumber: "B1", Status: SeatAvailable, Price: 15000})
+	seatRepo.Save(ctx, &Seat{EventID: 2, SeatNumber: "B2", Status: SeatAvailable, Price: 15000})
+
+	groupReservations, _, err := service.ReserveSeats(ctx, 2, []string{"B1", "B2"}, "user789")
+	if err != nil {
+		log.Fatal(err)
+	}
+	groupReservationIDs := make([]string, len(groupReservations))
+	for i, r := range groupReservations {
+		groupReservationIDs[i] = r.ReservationID
+	}
+	groupBooking, err := service.ConfirmBookingGroup(ctx, groupReservationIDs, PaymentRequest{Amount: 30000, UserID: "user789"}, "group-key123")
+	if err != nil {
+		log.Printf("Group booking failed: %v", err)
+	} else {
+		log.Printf("Group booked: %+v", groupBooking)
+	}
+
 	// Cleanup (simulate)
 	service.CleanupExpiredReservations(ctx)
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// =====================
+// Reservation FSM
+// =====================
+// Reservation.Status was previously just flipped directly by whichever
+// method happened to touch it (ReserveSeats, ConfirmBooking,
+// releaseExpiredReservation). That makes it easy to reach an impossible
+// state (e.g. confirming an already-expired reservation) by forgetting a
+// check in one call site. An explicit FSM makes every legal transition -
+// and the side effect that must run with it - a single registered fact.
+
+// ReservationTrigger names a transition request, as opposed to
+// ReservationStatus which names a state.
+type ReservationTrigger string
+
+const (
+	TriggerConfirm ReservationTrigger = "CONFIRM"
+	TriggerExpire  ReservationTrigger = "EXPIRE"
+	TriggerCancel  ReservationTrigger = "CANCEL"
+)
+
+// ReservationCancelled is a terminal state reachable via TriggerCancel, in
+// addition to the ReservationActive/Expired/Confirmed states Reservation
+// already declares.
+const ReservationCancelled ReservationStatus = "CANCELLED"
+
+// ReservationAction runs as the side effect of a transition - e.g. saving
+// the seat back to Available, or emitting a notification. Returning an
+// error aborts the transition; Status is left unchanged.
+type ReservationAction func(ctx context.Context, res *Reservation) error
+
+type reservationTransition struct {
+	from   ReservationStatus
+	on     ReservationTrigger
+	to     ReservationStatus
+	action ReservationAction
+}
+
+// ReservationFSM holds the legal (from, trigger) -> (to, action) table.
+// WHY: pluggable actions mean TicketBookingService can register what
+// "expire" or "cancel" actually does (release the seat, refund a payment,
+// ...) without the FSM itself knowing about seats or payments.
+type ReservationFSM struct {
+	transitions map[ReservationStatus]map[ReservationTrigger]reservationTransition
+}
+
+// NewReservationFSM builds an FSM with no transitions registered yet; call
+// On to add each one.
+func NewReservationFSM() *ReservationFSM {
+	return &ReservationFSM{transitions: make(map[ReservationStatus]map[ReservationTrigger]reservationTransition)}
+}
+
+// On registers a legal transition. action may be nil for a no-op transition.
+func (f *ReservationFSM) On(from ReservationStatus, trigger ReservationTrigger, to ReservationStatus, action ReservationAction) *ReservationFSM {
+	if f.transitions[from] == nil {
+		f.transitions[from] = make(map[ReservationTrigger]reservationTransition)
+	}
+	f.transitions[from][trigger] = reservationTransition{from, trigger, to, action}
+	return f
+}
+
+// Fire validates res.Status against the registered table, runs the action
+// (if any), and only then advances res.Status.
+func (f *ReservationFSM) Fire(ctx context.Context, res *Reservation, trigger ReservationTrigger) error {
+	byTrigger, ok := f.transitions[res.Status]
+	if !ok {
+		return fmt.Errorf("reservation fsm: no transitions defined from state %s", res.Status)
+	}
+	t, ok := byTrigger[trigger]
+	if !ok {
+		return fmt.Errorf("reservation fsm: %w: %s -> %s", ErrInvalidReservation, res.Status, trigger)
+	}
+	if t.action != nil {
+		if err := t.action(ctx, res); err != nil {
+			return fmt.Errorf("reservation fsm: action for %s: %w", trigger, err)
+		}
+	}
+	res.Status = t.to
+	return nil
+}
+
+// NewDefaultReservationFSM wires the transitions TicketBookingService
+// already implements by hand: ACTIVE -> CONFIRMED/EXPIRED/CANCELLED.
+// WHY: seatRelease/refund hooks are passed in so this file stays ignorant
+// of SeatRepository/PaymentService, mirroring the repository-interface
+// style main.go uses.
+func NewDefaultReservationFSM(releaseSeat, refundIfConfirmed ReservationAction) *ReservationFSM {
+	fsm := NewReservationFSM()
+	fsm.On(ReservationActive, TriggerConfirm, ReservationConfirmed, nil)
+	fsm.On(ReservationActive, TriggerExpire, ReservationExpired, releaseSeat)
+	fsm.On(ReservationActive, TriggerCancel, ReservationCancelled, releaseSeat)
+	fsm.On(ReservationConfirmed, TriggerCancel, ReservationCancelled, refundIfConfirmed)
+	return fsm
+}
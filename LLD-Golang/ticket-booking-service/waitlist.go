@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// =====================
+// Waitlist
+// =====================
+// When every seat for an event is taken, a user can join a FIFO waitlist
+// instead of failing outright. As soon as a seat frees up (an expired or
+// cancelled reservation), the next waitlisted user for that event is
+// automatically offered it.
+
+type WaitlistEntry struct {
+	EventID   int64
+	UserID    string
+	SeatCount int
+	JoinedAt  time.Time
+}
+
+// WaitlistRepository mirrors the repository-interface style already used
+// for Seat/Reservation/Booking in main.go.
+type WaitlistRepository interface {
+	Enqueue(ctx context.Context, entry WaitlistEntry) error
+	Dequeue(ctx context.Context, eventID int64) (WaitlistEntry, bool, error)
+	Len(ctx context.Context, eventID int64) (int, error)
+}
+
+// InMemoryWaitlistRepository keeps a FIFO slice per event.
+type InMemoryWaitlistRepository struct {
+	mu    sync.Mutex
+	queue map[int64][]WaitlistEntry
+}
+
+func NewInMemoryWaitlistRepository() *InMemoryWaitlistRepository {
+	return &InMemoryWaitlistRepository{queue: make(map[int64][]WaitlistEntry)}
+}
+
+func (r *InMemoryWaitlistRepository) Enqueue(_ context.Context, entry WaitlistEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queue[entry.EventID] = append(r.queue[entry.EventID], entry)
+	return nil
+}
+
+func (r *InMemoryWaitlistRepository) Dequeue(_ context.Context, eventID int64) (WaitlistEntry, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q := r.queue[eventID]
+	if len(q) == 0 {
+		return WaitlistEntry{}, false, nil
+	}
+	entry := q[0]
+	r.queue[eventID] = q[1:]
+	return entry, true, nil
+}
+
+func (r *InMemoryWaitlistRepository) Len(_ context.Context, eventID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.queue[eventID]), nil
+}
+
+// JoinWaitlist enqueues userID for seatCount seats on eventID.
+// WHY: a dedicated entry point (rather than overloading ReserveSeats)
+// keeps "sold out, please wait" an explicit, separate outcome from a
+// successful reservation.
+func (s *TicketBookingService) JoinWaitlist(ctx context.Context, eventID int64, userID string, seatCount int) error {
+	return s.waitlistRepo.Enqueue(ctx, WaitlistEntry{
+		EventID:   eventID,
+		UserID:    userID,
+		SeatCount: seatCount,
+		JoinedAt:  time.Now(),
+	})
+}
+
+// offerToWaitlist is called whenever a seat is released (expiry or
+// cancellation). It pops the next waitlist entry for the event, if any,
+// and reserves the freed seat directly for that user.
+// WHY: this runs instead of simply marking the seat Available so a
+// waitlisted user doesn't have to race everyone else retrying ReserveSeats.
+func (s *TicketBookingService) offerToWaitlist(ctx context.Context, seat *Seat) (offered bool, err error) {
+	entry, ok, err := s.waitlistRepo.Dequeue(ctx, seat.EventID)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	reservedUntil := time.Now().Add(s.reservationTimeout)
+	seat.Status = SeatReserved
+	seat.ReservedBy = &entry.UserID
+	seat.ReservedUntil = &reservedUntil
+	if err := s.seatRepo.Save(ctx, seat); err != nil {
+		return false, err
+	}
+
+	res := &Reservation{
+		SeatID:    seat.SeatID,
+		EventID:   seat.EventID,
+		UserID:    entry.UserID,
+		ExpiresAt: reservedUntil,
+		Status:    ReservationActive,
+	}
+	if err := s.reservationRepo.Save(ctx, res); err != nil {
+		return false, err
+	}
+
+	fmt.Printf("Waitlist: offered seat %s to user %s (reserved until %v)\n", seat.SeatID, entry.UserID, reservedUntil)
+	return true, nil
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+	"warehouse-management-system/events"
+)
+
+// =====================================================
+// Carrier Adapter + Batch Shipment Sync
+// Mirrors the batched "closed order sync" pattern: pull records from an
+// external source since a watermark, dedupe by ID against a repository,
+// apply each new record, and advance the watermark to the max processed
+// timestamp.
+// =====================================================
+
+// CarrierAdapter is implemented once per carrier integration. FetchShipments
+// streams rather than returns a slice so a carrier with a large backlog
+// doesn't have to be buffered into memory all at once; the error channel
+// carries fetch-time failures (e.g. a paginated request failing midway)
+// without aborting shipments already sent on the shipment channel.
+type CarrierAdapter interface {
+	FetchShipments(ctx context.Context, since time.Time) (<-chan Shipment, <-chan error)
+	PushTracking(ctx context.Context, s Shipment) error
+	Name() string
+}
+
+// ShipmentRepository is the dedupe boundary for ShipmentSyncer: a shipment
+// already Has() by ID is assumed already Process()ed and is skipped.
+type ShipmentRepository interface {
+	Has(id string) bool
+	Save(s Shipment)
+}
+
+// InMemoryShipmentRepository is the default ShipmentRepository, useful for
+// the demo and for MockAdapter-driven runs within a single process.
+type InMemoryShipmentRepository struct {
+	mu   sync.Mutex
+	seen map[string]Shipment
+}
+
+func NewInMemoryShipmentRepository() *InMemoryShipmentRepository {
+	return &InMemoryShipmentRepository{seen: make(map[string]Shipment)}
+}
+
+func (r *InMemoryShipmentRepository) Has(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.seen[id]
+	return ok
+}
+
+func (r *InMemoryShipmentRepository) Save(s Shipment) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seen[s.ID()] = s
+}
+
+// SyncStats summarizes one ShipmentSyncer.Sync run against a single
+// carrier.
+type SyncStats struct {
+	Received int
+	Applied  int
+	Skipped  int
+	Errored  int
+}
+
+// ShipmentSyncer pulls shipments from a set of carrier adapters in bounded
+// batches, dedupes against a ShipmentRepository, and dispatches each new
+// shipment through Process(bus) - recording the max timestamp seen per
+// carrier as the next watermark for LastSyncTime.
+type ShipmentSyncer struct {
+	Adapters     []CarrierAdapter
+	Repo         ShipmentRepository
+	Bus          *events.EventBus
+	LastSyncTime map[string]time.Time // keyed by CarrierAdapter.Name()
+	Workers      int                  // worker-pool size per adapter; defaults to runtime.NumCPU()
+
+	mu sync.Mutex // guards LastSyncTime across concurrent Sync calls
+}
+
+func NewShipmentSyncer(repo ShipmentRepository, bus *events.EventBus, adapters ...CarrierAdapter) *ShipmentSyncer {
+	return &ShipmentSyncer{
+		Adapters:     adapters,
+		Repo:         repo,
+		Bus:          bus,
+		LastSyncTime: make(map[string]time.Time),
+	}
+}
+
+// Sync runs one batch against every adapter and returns per-carrier stats.
+// Each adapter's shipment stream is drained by a worker pool sized by
+// Workers (runtime.NumCPU() if unset), giving backpressure: FetchShipments
+// blocks on its channel send once workers fall behind instead of the
+// syncer buffering unbounded shipments in memory.
+func (sy *ShipmentSyncer) Sync(ctx context.Context) map[string]SyncStats {
+	results := make(map[string]SyncStats, len(sy.Adapters))
+	for _, adapter := range sy.Adapters {
+		results[adapter.Name()] = sy.syncAdapter(ctx, adapter)
+	}
+	return results
+}
+
+func (sy *ShipmentSyncer) syncAdapter(ctx context.Context, adapter CarrierAdapter) SyncStats {
+	sy.mu.Lock()
+	since := sy.LastSyncTime[adapter.Name()]
+	sy.mu.Unlock()
+
+	shipments, errs := adapter.FetchShipments(ctx, since)
+
+	workers := sy.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var stats SyncStats
+	var statsMu sync.Mutex
+	var watermark time.Time
+	var wg sync.WaitGroup
+
+	// errs is drained alongside the workers, so an adapter that reports an
+	// error mid-stream is never blocked on that send.
+	errsDone := make(chan struct{})
+	go func() {
+		defer close(errsDone)
+		for err := range errs {
+			if err != nil {
+				statsMu.Lock()
+				stats.Errored++
+				statsMu.Unlock()
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range shipments {
+				statsMu.Lock()
+				stats.Received++
+				statsMu.Unlock()
+
+				if sy.Repo.Has(s.ID()) {
+					statsMu.Lock()
+					stats.Skipped++
+					statsMu.Unlock()
+					continue
+				}
+
+				if err := s.Process(sy.Bus); err != nil {
+					statsMu.Lock()
+					stats.Errored++
+					statsMu.Unlock()
+					continue
+				}
+				sy.Repo.Save(s)
+
+				statsMu.Lock()
+				stats.Applied++
+				if s.Timestamp().After(watermark) {
+					watermark = s.Timestamp()
+				}
+				statsMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	<-errsDone
+
+	if !watermark.IsZero() {
+		sy.mu.Lock()
+		sy.LastSyncTime[adapter.Name()] = watermark
+		sy.mu.Unlock()
+	}
+	return stats
+}
+
+// -----------------------------
+// Concrete adapters
+// -----------------------------
+
+// MockAdapter generates a fixed, in-memory batch of shipments for demos
+// and tests, standing in for a real carrier's API.
+type MockAdapter struct {
+	CarrierName string
+	Shipments   []Shipment // pre-seeded fixture data; each must have its Timestamp set
+}
+
+func (m *MockAdapter) Name() string { return m.CarrierName }
+
+func (m *MockAdapter) FetchShipments(ctx context.Context, since time.Time) (<-chan Shipment, <-chan error) {
+	out := make(chan Shipment)
+	errs := make(chan error)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		for _, s := range m.Shipments {
+			if !s.Timestamp().After(since) {
+				continue
+			}
+			select {
+			case out <- s:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+func (m *MockAdapter) PushTracking(ctx context.Context, s Shipment) error {
+	fmt.Printf("[mock:%s] tracking update for %s -> %s\n", m.CarrierName, s.ID(), s.Status())
+	return nil
+}
+
+// DHLAdapter and UPSAdapter are thin stubs wiring the real carrier APIs in
+// production; FetchShipments/PushTracking would issue the actual HTTP
+// calls. Left unimplemented here (empty streams, no-op push) since this
+// demo has no network access - the point is that ShipmentSyncer only
+// depends on the CarrierAdapter interface, so swapping a real client in
+// later requires no change to ShipmentSyncer itself.
+
+type DHLAdapter struct{ APIKey string }
+
+func (a *DHLAdapter) Name() string { return "DHL" }
+
+func (a *DHLAdapter) FetchShipments(ctx context.Context, since time.Time) (<-chan Shipment, <-chan error) {
+	out := make(chan Shipment)
+	errs := make(chan error)
+	close(out)
+	close(errs)
+	return out, errs
+}
+
+func (a *DHLAdapter) PushTracking(ctx context.Context, s Shipment) error {
+	return fmt.Errorf("DHLAdapter.PushTracking: not implemented")
+}
+
+type UPSAdapter struct{ APIKey string }
+
+func (a *UPSAdapter) Name() string { return "UPS" }
+
+func (a *UPSAdapter) FetchShipments(ctx context.Context, since time.Time) (<-chan Shipment, <-chan error) {
+	out := make(chan Shipment)
+	errs := make(chan error)
+	close(out)
+	close(errs)
+	return out, errs
+}
+
+func (a *UPSAdapter) PushTracking(ctx context.Context, s Shipment) error {
+	return fmt.Errorf("UPSAdapter.PushTracking: not implemented")
+}
@@ -0,0 +1,216 @@
+// Package events provides a small typed event bus for the warehouse
+// management system, generalizing what used to be a single-purpose
+// StockNotifier/StockObserver pair into a publish/subscribe mechanism any
+// part of the domain (stock, orders, shipments, storage) can emit onto and
+// any number of subscribers - in-process or a WebhookSubscriber calling out
+// to a downstream system - can react to, without the publisher knowing who
+// (if anyone) is listening.
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Event is anything with a topic to route on. Concrete event types below
+// carry only primitive fields (no domain-package pointers) so they stay
+// decoupled from the caller's types and serialize cleanly for
+// WebhookSubscriber.
+type Event interface {
+	Topic() string
+}
+
+const (
+	TopicStockLow             = "stock.low"
+	TopicShipmentReceived     = "shipment.received"
+	TopicShipmentShipped      = "shipment.shipped"
+	TopicOrderStatusChanged   = "order.status_changed"
+	TopicLocationCapacityOver = "location.capacity_exceeded"
+)
+
+type LowStockEvent struct {
+	SKU          string
+	Name         string
+	Quantity     int
+	ReorderLevel int
+}
+
+func (LowStockEvent) Topic() string { return TopicStockLow }
+
+type ShipmentItemQty struct {
+	SKU string
+	Qty int
+}
+
+type ShipmentReceivedEvent struct {
+	ShipmentID string
+	Carrier    string
+	Items      []ShipmentItemQty
+}
+
+func (ShipmentReceivedEvent) Topic() string { return TopicShipmentReceived }
+
+type ShipmentShippedEvent struct {
+	ShipmentID string
+	Carrier    string
+	Items      []ShipmentItemQty
+}
+
+func (ShipmentShippedEvent) Topic() string { return TopicShipmentShipped }
+
+type OrderStatusChangedEvent struct {
+	OrderNumber string
+	OldStatus   string
+	NewStatus   string
+}
+
+func (OrderStatusChangedEvent) Topic() string { return TopicOrderStatusChanged }
+
+type LocationCapacityExceededEvent struct {
+	LocationID string
+	Capacity   float64
+	Requested  float64 // occupancy that would have resulted, had the add been allowed
+}
+
+func (LocationCapacityExceededEvent) Topic() string { return TopicLocationCapacityOver }
+
+// Handler reacts to one published Event. A returned error marks the
+// delivery as failed - EventBus routes it to the bus's dead-letter
+// callback rather than retrying, since retry policy is caller-specific.
+type Handler func(ctx context.Context, e Event) error
+
+// Subscription identifies one Subscribe call so it can later be passed to
+// Unsubscribe.
+type Subscription struct {
+	id    string
+	topic string
+}
+
+// DeadLetterFunc is called when a subscriber's buffer is full (a slow
+// consumer) or its Handler returns an error.
+type DeadLetterFunc func(sub Subscription, e Event, err error)
+
+type subscriber struct {
+	id      string
+	topic   string
+	ch      chan Event
+	done    chan struct{}
+	handler Handler
+}
+
+// EventBus fans out published events to per-topic subscribers, each
+// served by its own goroutine reading off a bounded buffered channel -
+// a slow or stuck subscriber can never block Publish or other
+// subscribers, it only risks its own messages being dead-lettered once
+// its buffer fills.
+type EventBus struct {
+	mu           sync.Mutex
+	subs         map[string]map[string]*subscriber
+	bufferSize   int
+	onDeadLetter DeadLetterFunc
+	nextID       int
+}
+
+// NewEventBus creates an EventBus whose subscriber channels are buffered
+// to bufferSize (a size <= 0 is treated as 1, since an unbuffered channel
+// would make every Publish block on every subscriber). onDeadLetter may be
+// nil to silently drop failed/overflowed deliveries.
+func NewEventBus(bufferSize int, onDeadLetter DeadLetterFunc) *EventBus {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	return &EventBus{
+		subs:         make(map[string]map[string]*subscriber),
+		bufferSize:   bufferSize,
+		onDeadLetter: onDeadLetter,
+	}
+}
+
+// Subscribe registers handler for topic and starts its delivery
+// goroutine, returning a Subscription to later Unsubscribe with.
+func (b *EventBus) Subscribe(topic string, handler Handler) Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	sub := &subscriber{
+		id:      topicSubID(topic, b.nextID),
+		topic:   topic,
+		ch:      make(chan Event, b.bufferSize),
+		done:    make(chan struct{}),
+		handler: handler,
+	}
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[string]*subscriber)
+	}
+	b.subs[topic][sub.id] = sub
+
+	go b.run(sub)
+	return Subscription{id: sub.id, topic: sub.topic}
+}
+
+func (b *EventBus) run(sub *subscriber) {
+	for {
+		select {
+		case e := <-sub.ch:
+			if err := sub.handler(context.Background(), e); err != nil && b.onDeadLetter != nil {
+				b.onDeadLetter(Subscription{id: sub.id, topic: sub.topic}, e, err)
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// Unsubscribe stops sub's delivery goroutine and removes it from the
+// topic. It is race-free with an in-flight Publish: Publish only ever
+// holds a snapshot of subscribers taken under b.mu, so a Publish that
+// already grabbed sub before Unsubscribe runs may still enqueue one more
+// event into sub.ch, but closing sub.done (rather than sub.ch) means that
+// send can never hit a closed channel - it's simply never read.
+func (b *EventBus) Unsubscribe(sub Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	topicSubs, ok := b.subs[sub.topic]
+	if !ok {
+		return
+	}
+	s, ok := topicSubs[sub.id]
+	if !ok {
+		return
+	}
+	delete(topicSubs, sub.id)
+	close(s.done)
+}
+
+// Publish delivers e to every current subscriber of e.Topic(). Delivery
+// is non-blocking per subscriber: a full buffer counts as a dead letter
+// rather than stalling the publisher.
+func (b *EventBus) Publish(ctx context.Context, e Event) {
+	b.mu.Lock()
+	topicSubs := b.subs[e.Topic()]
+	subs := make([]*subscriber, 0, len(topicSubs))
+	for _, s := range topicSubs {
+		subs = append(subs, s)
+	}
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		select {
+		case s.ch <- e:
+		default:
+			if b.onDeadLetter != nil {
+				b.onDeadLetter(Subscription{id: s.id, topic: s.topic}, e, errFullBuffer)
+			}
+		}
+	}
+	_ = ctx // reserved for future cancellation-aware delivery
+}
+
+func topicSubID(topic string, n int) string {
+	return topic + "#" + strconv.Itoa(n)
+}
+
+var errFullBuffer = errors.New("events: subscriber buffer full")
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookSubscriber POSTs every event it's handed as signed JSON to a
+// configured URL, so a downstream system can react to domain events
+// without the warehouse module knowing anything about it. Its Handle
+// method satisfies Handler, so it plugs straight into EventBus.Subscribe.
+type WebhookSubscriber struct {
+	URL    string
+	Secret []byte
+	Client *http.Client
+}
+
+// NewWebhookSubscriber returns a WebhookSubscriber using http.DefaultClient.
+func NewWebhookSubscriber(url string, secret []byte) *WebhookSubscriber {
+	return &WebhookSubscriber{URL: url, Secret: secret, Client: http.DefaultClient}
+}
+
+// Handle marshals e to JSON, signs it with HMAC-SHA256 over Secret in the
+// X-Signature-256 header (the same scheme GitHub/Stripe webhooks use, so
+// downstream verification code is unsurprising), and POSTs it to URL.
+func (w *WebhookSubscriber) Handle(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("webhook: marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-256", "sha256="+w.sign(body))
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: post to %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: %s returned status %d", w.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *WebhookSubscriber) sign(body []byte) string {
+	mac := hmac.New(sha256.New, w.Secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
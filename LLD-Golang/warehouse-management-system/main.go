@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
+	"warehouse-management-system/events"
 )
 
 // =====================================================
 // WAREHOUSE MANAGEMENT SYSTEM (Go, single file)
 // Core entities + patterns requested:
 // - Factory Pattern for Shipment creation
-// - Observer Pattern for stock alerts (low inventory)
+// - Domain events (events.EventBus) for stock/order/shipment/location alerts
 // =====================================================
 
 // -----------------------------
@@ -20,18 +24,30 @@ type StorageLocation struct {
 	Capacity         float64
 	CurrentOccupancy float64
 	Type             string // shelf, bin, pallet
+	Bus              *events.EventBus
 }
 
 func NewStorageLocation(id string, capacity float64, typ string) *StorageLocation {
 	return &StorageLocation{ID: id, Capacity: capacity, Type: typ}
 }
 
+// SetBus wires s to publish LocationCapacityExceededEvent; left unset, s
+// behaves exactly as before events existed.
+func (s *StorageLocation) SetBus(bus *events.EventBus) { s.Bus = bus }
+
 func (s *StorageLocation) CanAccommodate(size float64) bool {
 	return s.CurrentOccupancy+size <= s.Capacity
 }
 
 func (s *StorageLocation) AddOccupancy(size float64) error {
 	if !s.CanAccommodate(size) {
+		if s.Bus != nil {
+			s.Bus.Publish(context.Background(), events.LocationCapacityExceededEvent{
+				LocationID: s.ID,
+				Capacity:   s.Capacity,
+				Requested:  s.CurrentOccupancy + size,
+			})
+		}
 		return fmt.Errorf("not enough space in location %s", s.ID)
 	}
 	s.CurrentOccupancy += size
@@ -41,19 +57,40 @@ func (s *StorageLocation) AddOccupancy(size float64) error {
 // -----
 // Item
 // -----
+// ErrInsufficientStock reports a Reserve call that would drive an item's
+// Available() below zero, e.g. two outgoing shipments racing on the same
+// SKU. Quantity itself is never touched by Reserve, so this never lets
+// Quantity go negative either.
+type ErrInsufficientStock struct {
+	SKU       string
+	Requested int
+	Available int
+}
+
+func (e *ErrInsufficientStock) Error() string {
+	return fmt.Sprintf("insufficient stock for %s (have %d, need %d)", e.SKU, e.Available, e.Requested)
+}
+
 type Item struct {
 	SKU          string
 	Name         string
 	Quantity     int
+	Reserved     int // qty set aside by a pending Order, not yet Committed or Released
 	Size         float64
 	ReorderLevel int
 	Location     *StorageLocation // one-to-one
+	Bus          *events.EventBus
+	mu           sync.Mutex // guards Quantity/Reserved against concurrent shipment processing
 }
 
 func NewItem(sku, name string, qty int, size float64, reorder int) *Item {
 	return &Item{SKU: sku, Name: name, Quantity: qty, Size: size, ReorderLevel: reorder}
 }
 
+// SetBus wires i to publish LowStockEvent once it falls below
+// ReorderLevel; left unset, i behaves exactly as before events existed.
+func (i *Item) SetBus(bus *events.EventBus) { i.Bus = bus }
+
 func (i *Item) SetLocation(loc *StorageLocation) error {
 	if err := loc.AddOccupancy(i.Size); err != nil {
 		return err
@@ -62,7 +99,75 @@ func (i *Item) SetLocation(loc *StorageLocation) error {
 	return nil
 }
 
-func (i *Item) UpdateStock(delta int) { i.Quantity += delta }
+func (i *Item) UpdateStock(delta int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.Quantity += delta
+	i.publishIfLowLocked()
+}
+
+// publishIfLowLocked publishes a LowStockEvent if i is now below its
+// ReorderLevel. Callers must already hold i.mu.
+func (i *Item) publishIfLowLocked() {
+	if i.Bus == nil || i.Quantity >= i.ReorderLevel {
+		return
+	}
+	i.Bus.Publish(context.Background(), events.LowStockEvent{
+		SKU:          i.SKU,
+		Name:         i.Name,
+		Quantity:     i.Quantity,
+		ReorderLevel: i.ReorderLevel,
+	})
+}
+
+// Available returns the stock not already set aside by a pending Reserve.
+func (i *Item) Available() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.Quantity - i.Reserved
+}
+
+// Reserve sets aside qty of stock for a pending order, failing with
+// ErrInsufficientStock rather than letting Available() go negative.
+func (i *Item) Reserve(qty int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if avail := i.Quantity - i.Reserved; avail < qty {
+		return &ErrInsufficientStock{SKU: i.SKU, Requested: qty, Available: avail}
+	}
+	i.Reserved += qty
+	return nil
+}
+
+// Release gives back a Reserve that was never Committed (e.g. a
+// cancelled order), clamping at zero so a double-release can't
+// underflow Reserved.
+func (i *Item) Release(qty int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.Reserved -= qty
+	if i.Reserved < 0 {
+		i.Reserved = 0
+	}
+}
+
+// Commit turns a previously Reserved qty into an actual stock
+// deduction, for when a shipment fulfilling it ships. Both fields are
+// clamped at zero as a defensive floor; a well-behaved caller only ever
+// Commits qty it already holds Reserved.
+func (i *Item) Commit(qty int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.Reserved -= qty
+	if i.Reserved < 0 {
+		i.Reserved = 0
+	}
+	i.Quantity -= qty
+	if i.Quantity < 0 {
+		i.Quantity = 0
+	}
+	i.publishIfLowLocked()
+}
 
 func (i *Item) IsReorderNeeded() bool { return i.Quantity < i.ReorderLevel }
 
@@ -97,13 +202,30 @@ type Order struct {
 	Status      string // pending, fulfilled, shipped
 	Items       []OrderItem
 	ManagedBy   *User // many orders can be managed by one user
+	Bus         *events.EventBus
 }
 
 func NewOrder(orderNumber, customer string, managedBy *User) *Order {
 	return &Order{OrderNumber: orderNumber, Customer: customer, Status: "pending", ManagedBy: managedBy}
 }
 
-func (o *Order) AddOrderItem(oi OrderItem) { o.Items = append(o.Items, oi) }
+// SetBus wires o to publish OrderStatusChangedEvent; left unset, o
+// behaves exactly as before events existed.
+func (o *Order) SetBus(bus *events.EventBus) { o.Bus = bus }
+
+// AddOrderItem reserves oi's quantity on its Item while the order is
+// still pending, so a second order racing on the same SKU sees it
+// reflected in Available() immediately rather than only once this
+// order ships.
+func (o *Order) AddOrderItem(oi OrderItem) error {
+	if o.Status == "pending" {
+		if err := oi.Item.Reserve(oi.Quantity); err != nil {
+			return err
+		}
+	}
+	o.Items = append(o.Items, oi)
+	return nil
+}
 
 func (o *Order) TotalCost() float64 {
 	sum := 0.0
@@ -113,41 +235,36 @@ func (o *Order) TotalCost() float64 {
 	return sum
 }
 
-func (o *Order) SetStatus(status string) { o.Status = status }
-
-// =====================================================
-// Observer Pattern (Stock Alerts)
-// =====================================================
-// Observers subscribe to low-stock notifications for Items.
-
-type StockObserver interface{ Update(lowItem *Item) }
-
-type StockNotifier struct{ observers []StockObserver }
-
-func (n *StockNotifier) AddObserver(o StockObserver) { n.observers = append(n.observers, o) }
-func (n *StockNotifier) Notify(item *Item) {
-	for _, o := range n.observers {
-		o.Update(item)
+func (o *Order) SetStatus(status string) {
+	old := o.Status
+	o.Status = status
+	if o.Bus != nil && old != status {
+		o.Bus.Publish(context.Background(), events.OrderStatusChangedEvent{
+			OrderNumber: o.OrderNumber,
+			OldStatus:   old,
+			NewStatus:   status,
+		})
 	}
 }
 
-// Concrete observer example: Manager gets alerted
+// =====================================================
+// Domain event subscribers (see events.EventBus)
+// =====================================================
 
+// ManagerStockObserver is a built-in subscriber matching the behavior of
+// the pre-events StockObserver: it alerts a named manager whenever a
+// LowStockEvent is published. Its Handle method satisfies events.Handler,
+// so it's wired up with bus.Subscribe(events.TopicStockLow, manager.Handle).
 type ManagerStockObserver struct{ Name string }
 
-func (m ManagerStockObserver) Update(item *Item) {
-	fmt.Printf("[ALERT] Manager %s: Low stock for %s (SKU=%s, Qty=%d, ReorderLevel=%d)\n",
-		m.Name, item.Name, item.SKU, item.Quantity, item.ReorderLevel)
-}
-
-// Helper to check and notify after a stock change
-func checkAndNotifyReorder(it *Item, notifier *StockNotifier) {
-	if notifier == nil {
-		return
-	}
-	if it.IsReorderNeeded() {
-		notifier.Notify(it)
+func (m ManagerStockObserver) Handle(ctx context.Context, e events.Event) error {
+	lowStock, ok := e.(events.LowStockEvent)
+	if !ok {
+		return nil
 	}
+	fmt.Printf("[ALERT] Manager %s: Low stock for %s (SKU=%s, Qty=%d, ReorderLevel=%d)\n",
+		m.Name, lowStock.Name, lowStock.SKU, lowStock.Quantity, lowStock.ReorderLevel)
+	return nil
 }
 
 // =====================================================
@@ -172,7 +289,9 @@ type Shipment interface {
 	Status() string
 	AddItem(item *Item, qty int)
 	Items() []ShipmentItem
-	Process(notifier *StockNotifier) error // apply stock changes and optionally notify
+	Process(bus *events.EventBus) error // apply stock changes and publish a shipment event
+	Timestamp() time.Time               // when this shipment occurred at the carrier; watermark for ShipmentSyncer
+	SetTimestamp(t time.Time)
 }
 
 type baseShipment struct {
@@ -181,13 +300,16 @@ type baseShipment struct {
 	typeLabel  string
 	status     string // created, processed
 	items      []ShipmentItem
+	occurredAt time.Time
 }
 
-func (b *baseShipment) ID() string            { return b.shipmentID }
-func (b *baseShipment) Carrier() string       { return b.carrier }
-func (b *baseShipment) Type() string          { return b.typeLabel }
-func (b *baseShipment) Status() string        { return b.status }
-func (b *baseShipment) Items() []ShipmentItem { return b.items }
+func (b *baseShipment) ID() string               { return b.shipmentID }
+func (b *baseShipment) Carrier() string          { return b.carrier }
+func (b *baseShipment) Type() string             { return b.typeLabel }
+func (b *baseShipment) Status() string           { return b.status }
+func (b *baseShipment) Items() []ShipmentItem    { return b.items }
+func (b *baseShipment) Timestamp() time.Time     { return b.occurredAt }
+func (b *baseShipment) SetTimestamp(t time.Time) { b.occurredAt = t }
 func (b *baseShipment) AddItem(it *Item, qty int) {
 	b.items = append(b.items, ShipmentItem{Item: it, Qty: qty})
 }
@@ -200,13 +322,19 @@ func NewIncomingShipment(id, carrier string) *IncomingShipment {
 	return &IncomingShipment{baseShipment{shipmentID: id, carrier: carrier, typeLabel: ShipmentIncoming, status: "created"}}
 }
 
-func (s *IncomingShipment) Process(notifier *StockNotifier) error {
+func (s *IncomingShipment) Process(bus *events.EventBus) error {
 	fmt.Printf("Processing incoming shipment %s\n", s.ID())
 	for _, si := range s.items {
 		si.Item.UpdateStock(si.Qty)
-		checkAndNotifyReorder(si.Item, notifier)
 	}
 	s.status = "received"
+	if bus != nil {
+		bus.Publish(context.Background(), events.ShipmentReceivedEvent{
+			ShipmentID: s.ID(),
+			Carrier:    s.Carrier(),
+			Items:      shipmentItemQtys(s.items),
+		})
+	}
 	return nil
 }
 
@@ -218,23 +346,49 @@ func NewOutgoingShipment(id, carrier string) *OutgoingShipment {
 	return &OutgoingShipment{baseShipment{shipmentID: id, carrier: carrier, typeLabel: ShipmentOutgoing, status: "created"}}
 }
 
-func (s *OutgoingShipment) Process(notifier *StockNotifier) error {
+// Process reserves every line item's quantity first - the same
+// Reserve/Available accounting AddOrderItem uses, so a shipment racing
+// an order (or another shipment) on the same SKU can never oversell -
+// then commits each reservation into an actual stock deduction. A
+// Reserve failing partway through rolls back whatever this call
+// already reserved rather than leaving it stuck.
+func (s *OutgoingShipment) Process(bus *events.EventBus) error {
 	fmt.Printf("Processing outgoing shipment %s\n", s.ID())
-	// Validate stock first
+	reserved := make([]ShipmentItem, 0, len(s.items))
 	for _, si := range s.items {
-		if si.Item.Quantity < si.Qty {
-			return fmt.Errorf("insufficient stock for %s (have %d, need %d)", si.Item.SKU, si.Item.Quantity, si.Qty)
+		if err := si.Item.Reserve(si.Qty); err != nil {
+			for _, r := range reserved {
+				r.Item.Release(r.Qty)
+			}
+			return err
 		}
+		reserved = append(reserved, si)
 	}
-	// Deduct
 	for _, si := range s.items {
-		si.Item.UpdateStock(-si.Qty)
-		checkAndNotifyReorder(si.Item, notifier)
+		si.Item.Commit(si.Qty)
 	}
 	s.status = "shipped"
+	if bus != nil {
+		bus.Publish(context.Background(), events.ShipmentShippedEvent{
+			ShipmentID: s.ID(),
+			Carrier:    s.Carrier(),
+			Items:      shipmentItemQtys(s.items),
+		})
+	}
 	return nil
 }
 
+// shipmentItemQtys projects a shipment's line items down to the
+// SKU/quantity pairs events.ShipmentReceivedEvent/ShipmentShippedEvent
+// carry, keeping those event types decoupled from *Item.
+func shipmentItemQtys(items []ShipmentItem) []events.ShipmentItemQty {
+	out := make([]events.ShipmentItemQty, len(items))
+	for i, si := range items {
+		out[i] = events.ShipmentItemQty{SKU: si.Item.SKU, Qty: si.Qty}
+	}
+	return out
+}
+
 // ShipmentFactory creates concrete shipments dynamically
 func ShipmentFactory(typ, id, carrier string) (Shipment, error) {
 	switch typ {
@@ -255,48 +409,63 @@ func main() {
 	shelfA := NewStorageLocation("SHELF-A1", 100, "Shelf")
 	binB := NewStorageLocation("BIN-B1", 50, "Bin")
 
+	// --- Event bus setup ---
+	bus := events.NewEventBus(16, func(sub events.Subscription, e events.Event, err error) {
+		fmt.Printf("[dead-letter] topic=%s event=%+v err=%v\n", e.Topic(), e, err)
+	})
+	shelfA.SetBus(bus)
+	binB.SetBus(bus)
+
+	manager := ManagerStockObserver{Name: "Bob"}
+	bus.Subscribe(events.TopicStockLow, manager.Handle)
+
 	// Items
 	laptop := NewItem("SKU101", "Laptop", 6, 5, 5)
 	mouse := NewItem("SKU102", "Mouse", 12, 1, 10)
+	laptop.SetBus(bus)
+	mouse.SetBus(bus)
 	_ = laptop.SetLocation(shelfA)
 	_ = mouse.SetLocation(binB)
 
 	// Users
-	manager := &User{ID: "U2", Name: "Bob", Email: "bob@example.com", Role: RoleManager}
-	_ = manager
+	managedBy := &User{ID: "U2", Name: "Bob", Email: "bob@example.com", Role: RoleManager}
 
 	// Order
-	order := NewOrder("ORD-001", "John Doe", manager)
-	order.AddOrderItem(OrderItem{Item: laptop, Quantity: 2, Price: 1000})
-	order.AddOrderItem(OrderItem{Item: mouse, Quantity: 3, Price: 25})
+	order := NewOrder("ORD-001", "John Doe", managedBy)
+	order.SetBus(bus)
+	if err := order.AddOrderItem(OrderItem{Item: laptop, Quantity: 2, Price: 1000}); err != nil {
+		fmt.Println("Order item error:", err)
+	}
+	if err := order.AddOrderItem(OrderItem{Item: mouse, Quantity: 3, Price: 25}); err != nil {
+		fmt.Println("Order item error:", err)
+	}
 	fmt.Printf("Order %s total: %.2f (status: %s, managed by: %s)\n", order.OrderNumber, order.TotalCost(), order.Status, order.ManagedBy.Role)
 
-	// --- Observer setup ---
-	notifier := &StockNotifier{}
-	notifier.AddObserver(ManagerStockObserver{Name: "Bob"})
-
 	// --- Factory + Shipment processing ---
 	// Outgoing shipment: ship 2 laptops and 5 mice
 	outShip, _ := ShipmentFactory(ShipmentOutgoing, "SHIP-001", "DHL")
 	outShip.AddItem(laptop, 2)
 	outShip.AddItem(mouse, 5)
-	if err := outShip.Process(notifier); err != nil {
+	if err := outShip.Process(bus); err != nil {
 		fmt.Println("Outgoing shipment error:", err)
 	} else {
 		fmt.Println("Outgoing shipment status:", outShip.Status())
+		order.SetStatus("shipped")
 	}
 
 	// Incoming shipment: restock 3 laptops
 	inShip, _ := ShipmentFactory(ShipmentIncoming, "SHIP-002", "UPS")
 	inShip.AddItem(laptop, 3)
-	_ = inShip.Process(notifier)
+	_ = inShip.Process(bus)
 	fmt.Println("Incoming shipment status:", inShip.Status())
 
-	// Manual check for reorder after operations
-	if laptop.IsReorderNeeded() {
-		notifier.Notify(laptop)
-	}
-	if mouse.IsReorderNeeded() {
-		notifier.Notify(mouse)
-	}
+	// --- Carrier adapter + batch shipment sync ---
+	fedexShip, _ := ShipmentFactory(ShipmentIncoming, "SHIP-003", "FedEx")
+	fedexShip.AddItem(mouse, 20)
+	fedexShip.SetTimestamp(time.Now())
+
+	mockAdapter := &MockAdapter{CarrierName: "FedEx", Shipments: []Shipment{fedexShip}}
+	syncer := NewShipmentSyncer(NewInMemoryShipmentRepository(), bus, mockAdapter)
+	stats := syncer.Sync(context.Background())
+	fmt.Printf("Sync stats for FedEx: %+v\n", stats["FedEx"])
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestReserveNeverOversellsUnderConcurrency spins up N goroutines racing
+// Reserve on the same SKU, as the request asks, to prove Available() can
+// never go negative and Reserved never exceeds Quantity no matter how the
+// goroutines interleave.
+func TestReserveNeverOversellsUnderConcurrency(t *testing.T) {
+	const qty = 100
+	const racers = 500 // more goroutines than stock, so some must fail
+
+	item := NewItem("SKU-1", "Widget", qty, 1.0, 10)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := item.Reserve(1); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != qty {
+		t.Fatalf("succeeded = %d, want exactly %d (one per unit of stock)", succeeded, qty)
+	}
+	if item.Reserved != qty {
+		t.Fatalf("Reserved = %d, want %d", item.Reserved, qty)
+	}
+	if avail := item.Available(); avail != 0 {
+		t.Fatalf("Available() = %d, want 0", avail)
+	}
+
+	// One more reservation must fail outright rather than driving
+	// Available() negative.
+	if err := item.Reserve(1); err == nil {
+		t.Fatalf("Reserve(1) on exhausted stock: got nil error, want ErrInsufficientStock")
+	}
+}
+
+// TestOutgoingShipmentProcessNeverOversells races two shipments that
+// together ask for more stock than exists, proving Process's
+// reserve-then-commit phases serialize on the item's mutex instead of
+// both reading a stale Quantity and shipping more than was on hand.
+func TestOutgoingShipmentProcessNeverOversells(t *testing.T) {
+	const qty = 50
+	item := NewItem("SKU-2", "Gadget", qty, 1.0, 10)
+
+	s1 := NewOutgoingShipment("ship-1", "fedex")
+	s1.AddItem(item, 30)
+	s2 := NewOutgoingShipment("ship-2", "ups")
+	s2.AddItem(item, 30)
+
+	var wg sync.WaitGroup
+	results := make([]error, 2)
+	wg.Add(2)
+	go func() { defer wg.Done(); results[0] = s1.Process(nil) }()
+	go func() { defer wg.Done(); results[1] = s2.Process(nil) }()
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("succeeded shipments = %d, want exactly 1 (only one of 30+30 fits in 50)", succeeded)
+	}
+	if item.Quantity < 0 {
+		t.Fatalf("Quantity went negative: %d", item.Quantity)
+	}
+	if item.Available() < 0 {
+		t.Fatalf("Available() went negative: %d", item.Available())
+	}
+}